@@ -1,6 +1,8 @@
 package binarysearch
 
 import (
+	"math"
+	"reflect"
 	"testing"
 )
 
@@ -159,3 +161,173 @@ func TestFindLastLessOrEqual(t *testing.T) {
 		}
 	})
 }
+
+func TestBounds(t *testing.T) {
+	t.Parallel()
+
+	arr := []int{1, 2, 2, 2, 5, 7}
+	tests := []struct {
+		name                 string
+		target               int
+		lower, upper         int
+		firstGreater, lastLt int
+	}{
+		{name: "before all", target: 0, lower: 0, upper: 0, firstGreater: 0, lastLt: -1},
+		{name: "first element", target: 1, lower: 0, upper: 1, firstGreater: 1, lastLt: -1},
+		{name: "duplicates", target: 2, lower: 1, upper: 4, firstGreater: 4, lastLt: 0},
+		{name: "missing in the middle", target: 4, lower: 4, upper: 4, firstGreater: 4, lastLt: 3},
+		{name: "last element", target: 7, lower: 5, upper: 6, firstGreater: -1, lastLt: 4},
+		{name: "after all", target: 8, lower: 6, upper: 6, firstGreater: -1, lastLt: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LowerBound(arr, tt.target, IntComparator); got != tt.lower {
+				t.Errorf("LowerBound() = %v, want %v", got, tt.lower)
+			}
+			if got := UpperBound(arr, tt.target, IntComparator); got != tt.upper {
+				t.Errorf("UpperBound() = %v, want %v", got, tt.upper)
+			}
+			if first, last := EqualRange(arr, tt.target, IntComparator); first != tt.lower || last != tt.upper {
+				t.Errorf("EqualRange() = %v, %v, want %v, %v", first, last, tt.lower, tt.upper)
+			}
+			if got := FindFirstGreater(arr, tt.target, IntComparator); got != tt.firstGreater {
+				t.Errorf("FindFirstGreater() = %v, want %v", got, tt.firstGreater)
+			}
+			if got := FindLastLess(arr, tt.target, IntComparator); got != tt.lastLt {
+				t.Errorf("FindLastLess() = %v, want %v", got, tt.lastLt)
+			}
+		})
+	}
+
+	if first, last := EqualRange([]int{}, 3, IntComparator); first != 0 || last != 0 {
+		t.Errorf("EqualRange() on empty slice = %v, %v", first, last)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	for n := 0; n < 20; n++ {
+		for k := 0; k <= n; k++ {
+			calls := 0
+			got := Search(n, func(i int) bool {
+				calls++
+				if i < 0 || i >= n {
+					t.Fatalf("Search() called pred(%d) with n = %d", i, n)
+				}
+				return i >= k
+			})
+			if got != k {
+				t.Errorf("Search(%d) = %v, want %v", n, got, k)
+			}
+			if calls > 5 {
+				t.Errorf("Search(%d) made %d calls", n, calls)
+			}
+		}
+	}
+}
+
+func TestExponentialAndInterpolationSearch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		arr    []int
+		target int
+		want   int
+	}{
+		{name: "empty", arr: []int{}, target: 1, want: -1},
+		{name: "single found", arr: []int{3}, target: 3, want: 0},
+		{name: "single missing", arr: []int{3}, target: 4, want: -1},
+		{name: "first", arr: []int{1, 3, 5, 7, 9, 11, 13}, target: 1, want: 0},
+		{name: "middle", arr: []int{1, 3, 5, 7, 9, 11, 13}, target: 9, want: 4},
+		{name: "last", arr: []int{1, 3, 5, 7, 9, 11, 13}, target: 13, want: 6},
+		{name: "gap", arr: []int{1, 3, 5, 7, 9, 11, 13}, target: 6, want: -1},
+		{name: "beyond", arr: []int{1, 3, 5, 7, 9, 11, 13}, target: 14, want: -1},
+		{name: "skewed", arr: []int{1, 2, 3, 4, 5, 1000000}, target: 5, want: 4},
+		{name: "all equal", arr: []int{4, 4, 4}, target: 4, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExponentialSearch(tt.arr, tt.target, IntComparator)
+			if (tt.want < 0 && got != -1) || (tt.want >= 0 && (got < 0 || tt.arr[got] != tt.target)) {
+				t.Errorf("ExponentialSearch() = %v, want %v", got, tt.want)
+			}
+			got = InterpolationSearch(tt.arr, tt.target)
+			if (tt.want < 0 && got != -1) || (tt.want >= 0 && (got < 0 || tt.arr[got] != tt.target)) {
+				t.Errorf("InterpolationSearch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := InterpolationSearch([]float64{0.5, 1.25, 2}, 1.25); got != 1 {
+		t.Errorf("InterpolationSearch() = %v, want 1", got)
+	}
+
+	// Distinct integers that round to the same float64 must not break the position estimate.
+	signed := []int64{math.MaxInt64 - 2, math.MaxInt64 - 1, math.MaxInt64}
+	for i, target := range signed {
+		if got := InterpolationSearch(signed, target); got != i {
+			t.Errorf("InterpolationSearch(%v) = %v, want %v", target, got, i)
+		}
+		if got := InterpolationSearch(signed[i:], target); got != 0 {
+			t.Errorf("InterpolationSearch(%v) = %v, want 0", target, got)
+		}
+	}
+	unsigned := []uint64{math.MaxUint64 - 3, math.MaxUint64 - 1, math.MaxUint64}
+	for i, target := range unsigned {
+		if got := InterpolationSearch(unsigned, target); got != i {
+			t.Errorf("InterpolationSearch(%v) = %v, want %v", target, got, i)
+		}
+	}
+	if got := InterpolationSearch(unsigned, math.MaxUint64-2); got != -1 {
+		t.Errorf("InterpolationSearch() = %v, want -1", got)
+	}
+}
+
+func TestFindInRotated(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		arr    []int
+		target int
+		want   int
+	}{
+		{name: "empty", arr: []int{}, target: 1, want: -1},
+		{name: "not rotated", arr: []int{1, 2, 3, 4}, target: 3, want: 2},
+		{name: "rotated left part", arr: []int{4, 5, 6, 1, 2, 3}, target: 5, want: 1},
+		{name: "rotated right part", arr: []int{4, 5, 6, 1, 2, 3}, target: 2, want: 4},
+		{name: "rotated missing", arr: []int{4, 5, 6, 1, 2, 3}, target: 7, want: -1},
+		{name: "duplicates", arr: []int{1, 1, 1, 0, 1}, target: 0, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindInRotated(tt.arr, tt.target, IntComparator); got != tt.want {
+				t.Errorf("FindInRotated() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Every rotation of sorted slices with duplicates.
+	for _, sorted := range [][]int{{0, 0, 0, 1}, {0, 1, 1, 1, 2, 2}, {1, 2, 3, 4, 5, 6, 7}} {
+		n := len(sorted)
+		for shift := 0; shift < n; shift++ {
+			arr := append(append([]int{}, sorted[shift:]...), sorted[:shift]...)
+			got := FindRotationPoint(arr, IntComparator)
+			if unrotated := append(append([]int{}, arr[got:]...), arr[:got]...); !reflect.DeepEqual(unrotated, sorted) {
+				t.Errorf("FindRotationPoint(%v) = %v", arr, got)
+			}
+			for _, target := range []int{-1, 0, 1, 2, 3, 7, 8} {
+				got := FindInRotated(arr, target, IntComparator)
+				found := false
+				for _, v := range sorted {
+					found = found || v == target
+				}
+				if (got >= 0) != found || (got >= 0 && arr[got] != target) {
+					t.Errorf("FindInRotated(%v, %v) = %v", arr, target, got)
+				}
+			}
+		}
+	}
+}
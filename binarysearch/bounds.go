@@ -0,0 +1,43 @@
+package binarysearch
+
+// LowerBound returns the index of the first element that is greater than or equal to the target.
+// Unlike FindFirstGreaterOrEqual it returns len(arr) instead of -1 if there is no such element,
+// so the result is always the position where the target can be inserted keeping arr sorted.
+// cmp should return a negative number if a < b, zero if a == b, and a positive number if a > b.
+func LowerBound[T1 any, T2 comparable](arr []T1, target T2, cmp Comparator[T1, T2]) int {
+	return Search(len(arr), func(i int) bool { return cmp(arr[i], target) >= 0 })
+}
+
+// UpperBound returns the index of the first element that is greater than the target,
+// or len(arr) if there is no such element. It is the last position where the target
+// can be inserted keeping arr sorted.
+// cmp should return a negative number if a < b, zero if a == b, and a positive number if a > b.
+func UpperBound[T1 any, T2 comparable](arr []T1, target T2, cmp Comparator[T1, T2]) int {
+	return Search(len(arr), func(i int) bool { return cmp(arr[i], target) > 0 })
+}
+
+// EqualRange returns the half-open range [first, last) of elements equal to the target.
+// If there is none, first == last is the insertion point of the target.
+// cmp should return a negative number if a < b, zero if a == b, and a positive number if a > b.
+func EqualRange[T1 any, T2 comparable](arr []T1, target T2, cmp Comparator[T1, T2]) (first, last int) {
+	first = LowerBound(arr, target, cmp)
+	last = first + UpperBound(arr[first:], target, cmp)
+	return first, last
+}
+
+// FindFirstGreater finds the index of the first element that is greater than the target.
+// cmp should return a negative number if a < b, zero if a == b, and a positive number if a > b.
+// If such an element is not found, it returns -1.
+func FindFirstGreater[T1 any, T2 comparable](arr []T1, target T2, cmp Comparator[T1, T2]) int {
+	if i := UpperBound(arr, target, cmp); i < len(arr) {
+		return i
+	}
+	return -1
+}
+
+// FindLastLess finds the index of the last element that is less than the target.
+// cmp should return a negative number if a < b, zero if a == b, and a positive number if a > b.
+// If such an element is not found, it returns -1.
+func FindLastLess[T1 any, T2 comparable](arr []T1, target T2, cmp Comparator[T1, T2]) int {
+	return LowerBound(arr, target, cmp) - 1
+}
@@ -0,0 +1,86 @@
+package rangetable
+
+import "github.com/kwstars/goads/pkg/common"
+
+// OverlappingTable maps possibly overlapping intervals to values.
+//
+// The entries are sorted by start and viewed as a balanced binary search tree: the root of a range
+// of entries is its middle one. Every node records the largest end in its subtree, so a query skips
+// subtrees ending before the query starts, as well as right subtrees starting after it ends.
+// A query reporting m entries takes O(m·log n).
+type OverlappingTable[K common.Integer, V any] struct {
+	entries []Entry[K, V] // sorted by Start
+	maxEnd  []K           // maxEnd[i] is the largest End in the subtree rooted at entries[i]
+}
+
+// NewOverlapping returns a table holding the entries, which may be given in any order and may overlap.
+// It returns ErrInvalidInterval if an interval ends before it starts.
+func NewOverlapping[K common.Integer, V any](entries ...Entry[K, V]) (*OverlappingTable[K, V], error) {
+	sorted, err := sortEntries(entries)
+	if err != nil {
+		return nil, err
+	}
+	t := &OverlappingTable[K, V]{entries: sorted, maxEnd: make([]K, len(sorted))}
+	if len(sorted) > 0 {
+		t.build(0, len(sorted))
+	}
+	return t, nil
+}
+
+// build computes maxEnd for the subtree over entries[lo:hi] and returns its value at the root.
+func (t *OverlappingTable[K, V]) build(lo, hi int) K {
+	mid := lo + (hi-lo)/2
+	m := t.entries[mid].End
+	if lo < mid {
+		if left := t.build(lo, mid); left > m {
+			m = left
+		}
+	}
+	if mid+1 < hi {
+		if right := t.build(mid+1, hi); right > m {
+			m = right
+		}
+	}
+	t.maxEnd[mid] = m
+	return m
+}
+
+// Find returns the entries whose intervals contain k, sorted by start.
+func (t *OverlappingTable[K, V]) Find(k K) []Entry[K, V] {
+	return t.Overlapping(Interval[K]{Start: k, End: k})
+}
+
+// Overlapping returns the entries whose intervals overlap iv, sorted by start.
+func (t *OverlappingTable[K, V]) Overlapping(iv Interval[K]) []Entry[K, V] {
+	var found []Entry[K, V]
+	t.query(0, len(t.entries), iv, &found)
+	return found
+}
+
+func (t *OverlappingTable[K, V]) query(lo, hi int, iv Interval[K], found *[]Entry[K, V]) {
+	if lo >= hi {
+		return
+	}
+	mid := lo + (hi-lo)/2
+	if t.maxEnd[mid] < iv.Start {
+		return // everything in this subtree ends too early
+	}
+	t.query(lo, mid, iv, found)
+	if t.entries[mid].Start > iv.End {
+		return // this entry and the right subtree start too late
+	}
+	if t.entries[mid].Overlaps(iv) {
+		*found = append(*found, t.entries[mid])
+	}
+	t.query(mid+1, hi, iv, found)
+}
+
+// Entries returns the entries sorted by start.
+func (t *OverlappingTable[K, V]) Entries() []Entry[K, V] {
+	return append([]Entry[K, V](nil), t.entries...)
+}
+
+// Size returns the number of entries.
+func (t *OverlappingTable[K, V]) Size() int {
+	return len(t.entries)
+}
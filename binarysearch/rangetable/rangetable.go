@@ -0,0 +1,158 @@
+// Package rangetable implements lookup tables mapping integer intervals to values.
+//
+// Table holds non-overlapping intervals sorted by start and finds the interval containing a key
+// with a binary search (binarysearch.FindExact). OverlappingTable allows intervals to overlap and
+// answers stabbing and overlap queries with a static interval tree.
+// Intervals are closed: both Start and End belong to them.
+//
+// Tables are immutable once built and safe for concurrent reads.
+//
+// References: https://en.wikipedia.org/wiki/Interval_tree
+package rangetable
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/kwstars/goads/binarysearch"
+	"github.com/kwstars/goads/pkg/common"
+)
+
+var (
+	ErrInvalidInterval = errors.New("interval start is after its end")
+	ErrOverlap         = errors.New("intervals overlap")
+)
+
+// Interval is the closed interval [Start, End].
+type Interval[K common.Integer] struct {
+	Start K
+	End   K
+}
+
+// Contains returns true if k lies in the interval.
+func (iv Interval[K]) Contains(k K) bool {
+	return iv.Start <= k && k <= iv.End
+}
+
+// Overlaps returns true if the intervals share at least one key.
+func (iv Interval[K]) Overlaps(other Interval[K]) bool {
+	return iv.Start <= other.End && other.Start <= iv.End
+}
+
+// Entry is an interval together with its value.
+type Entry[K common.Integer, V any] struct {
+	Interval[K]
+	Value V
+}
+
+// Table maps non-overlapping intervals to values.
+type Table[K common.Integer, V any] struct {
+	entries []Entry[K, V] // sorted by Start
+}
+
+// New returns a table holding the entries, which may be given in any order.
+// It returns ErrInvalidInterval if an interval ends before it starts and ErrOverlap if two intervals overlap.
+func New[K common.Integer, V any](entries ...Entry[K, V]) (*Table[K, V], error) {
+	sorted, err := sortEntries(entries)
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(sorted); i++ {
+		if prev, cur := sorted[i-1], sorted[i]; cur.Start <= prev.End {
+			return nil, fmt.Errorf("%w: %v and %v", ErrOverlap, prev.Interval, cur.Interval)
+		}
+	}
+	return &Table[K, V]{entries: sorted}, nil
+}
+
+// sortEntries validates the intervals and returns a copy of the entries sorted by start.
+func sortEntries[K common.Integer, V any](entries []Entry[K, V]) ([]Entry[K, V], error) {
+	sorted := make([]Entry[K, V], len(entries))
+	copy(sorted, entries)
+	for _, e := range sorted {
+		if e.Start > e.End {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInterval, e.Interval)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+	return sorted, nil
+}
+
+// containing compares an entry with a key the way binarysearch expects:
+// zero if the entry contains the key, positive if the entry lies after it.
+func containing[K common.Integer, V any](e Entry[K, V], k K) int8 {
+	switch {
+	case k < e.Start:
+		return 1
+	case k > e.End:
+		return -1
+	}
+	return 0
+}
+
+// Find returns the entry whose interval contains k.
+func (t *Table[K, V]) Find(k K) (Entry[K, V], bool) {
+	if i := binarysearch.FindExact(t.entries, k, containing[K, V]); i >= 0 {
+		return t.entries[i], true
+	}
+	return Entry[K, V]{}, false
+}
+
+// Get returns the value of the interval containing k.
+func (t *Table[K, V]) Get(k K) (V, bool) {
+	e, ok := t.Find(k)
+	return e.Value, ok
+}
+
+// Entries returns the entries sorted by start.
+func (t *Table[K, V]) Entries() []Entry[K, V] {
+	return append([]Entry[K, V](nil), t.entries...)
+}
+
+// Size returns the number of entries.
+func (t *Table[K, V]) Size() int {
+	return len(t.entries)
+}
+
+// Merge returns a table in which adjacent intervals, with no key between them, are joined
+// if equal reports their values as equal. A joined interval keeps the value of its first part.
+func (t *Table[K, V]) Merge(equal func(a, b V) bool) *Table[K, V] {
+	var merged []Entry[K, V]
+	for _, e := range t.entries {
+		if n := len(merged); n > 0 {
+			last := &merged[n-1]
+			// e.Start > last.End, so e.Start-1 cannot underflow.
+			if e.Start-1 == last.End && equal(last.Value, e.Value) {
+				last.End = e.End
+				continue
+			}
+		}
+		merged = append(merged, e)
+	}
+	return &Table[K, V]{entries: merged}
+}
+
+// Gaps returns the parts of [lo, hi] not covered by any interval, in order.
+func (t *Table[K, V]) Gaps(lo, hi K) []Interval[K] {
+	if lo > hi {
+		return nil
+	}
+	var gaps []Interval[K]
+	cursor := lo // the first key not known to be covered
+	// Skip the intervals ending before lo.
+	first := binarysearch.Search(len(t.entries), func(i int) bool { return t.entries[i].End >= lo })
+	for _, e := range t.entries[first:] {
+		if e.Start > hi {
+			break
+		}
+		if e.Start > cursor {
+			gaps = append(gaps, Interval[K]{Start: cursor, End: e.Start - 1})
+		}
+		if e.End >= hi {
+			return gaps
+		}
+		cursor = e.End + 1 // e.End < hi, so this cannot overflow
+	}
+	return append(gaps, Interval[K]{Start: cursor, End: hi})
+}
@@ -0,0 +1,119 @@
+package rangetable
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func entry(start, end int, value string) Entry[int, string] {
+	return Entry[int, string]{Interval: Interval[int]{Start: start, End: end}, Value: value}
+}
+
+func TestTable(t *testing.T) {
+	table, err := New(entry(10, 19, "b"), entry(0, 9, "a"), entry(30, 39, "c"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, table.Size())
+	assert.Equal(t, []Entry[int, string]{entry(0, 9, "a"), entry(10, 19, "b"), entry(30, 39, "c")}, table.Entries())
+
+	tests := []struct {
+		key   int
+		value string
+		ok    bool
+	}{
+		{-1, "", false}, {0, "a", true}, {9, "a", true}, {10, "b", true},
+		{19, "b", true}, {20, "", false}, {35, "c", true}, {40, "", false},
+	}
+	for _, tt := range tests {
+		v, ok := table.Get(tt.key)
+		assert.Equal(t, tt.ok, ok, "key %d", tt.key)
+		assert.Equal(t, tt.value, v, "key %d", tt.key)
+	}
+	e, ok := table.Find(15)
+	assert.True(t, ok)
+	assert.Equal(t, Interval[int]{Start: 10, End: 19}, e.Interval)
+
+	empty, err := New[int, string]()
+	assert.NoError(t, err)
+	_, ok = empty.Find(0)
+	assert.False(t, ok)
+}
+
+func TestTable_Validation(t *testing.T) {
+	_, err := New(entry(0, 10, "a"), entry(10, 20, "b"))
+	assert.True(t, errors.Is(err, ErrOverlap))
+	_, err = New(entry(0, 10, "a"), entry(5, 6, "b"))
+	assert.True(t, errors.Is(err, ErrOverlap))
+	_, err = New(entry(5, 4, "a"))
+	assert.True(t, errors.Is(err, ErrInvalidInterval))
+	_, err = NewOverlapping(entry(5, 4, "a"))
+	assert.True(t, errors.Is(err, ErrInvalidInterval))
+}
+
+func TestTable_Merge(t *testing.T) {
+	table, _ := New(entry(0, 4, "a"), entry(5, 9, "a"), entry(10, 14, "b"), entry(16, 20, "b"), entry(21, 25, "b"))
+	merged := table.Merge(func(a, b string) bool { return a == b })
+	assert.Equal(t, []Entry[int, string]{entry(0, 9, "a"), entry(10, 14, "b"), entry(16, 25, "b")}, merged.Entries())
+	// The original table is unchanged.
+	assert.Equal(t, 5, table.Size())
+
+	always := table.Merge(func(a, b string) bool { return true })
+	assert.Equal(t, []Entry[int, string]{entry(0, 14, "a"), entry(16, 25, "b")}, always.Entries())
+}
+
+func TestTable_Gaps(t *testing.T) {
+	table, _ := New(entry(0, 9, "a"), entry(10, 19, "b"), entry(30, 39, "c"), entry(50, 50, "d"))
+	iv := func(start, end int) Interval[int] { return Interval[int]{Start: start, End: end} }
+
+	assert.Equal(t, []Interval[int]{iv(-5, -1), iv(20, 29), iv(40, 49), iv(51, 60)}, table.Gaps(-5, 60))
+	assert.Equal(t, []Interval[int]{iv(20, 29)}, table.Gaps(5, 35))
+	assert.Empty(t, table.Gaps(0, 19))
+	assert.Equal(t, []Interval[int]{iv(25, 27)}, table.Gaps(25, 27))
+	assert.Nil(t, table.Gaps(10, 5))
+
+	// Keys at the ends of the type's range must not overflow.
+	bytes, _ := New(Entry[uint8, int]{Interval: Interval[uint8]{Start: 0, End: 9}}, Entry[uint8, int]{Interval: Interval[uint8]{Start: 250, End: 255}})
+	assert.Equal(t, []Interval[uint8]{{Start: 10, End: 249}}, bytes.Gaps(0, math.MaxUint8))
+	merged := bytes.Merge(func(a, b int) bool { return true })
+	assert.Equal(t, 2, merged.Size())
+}
+
+func TestOverlappingTable(t *testing.T) {
+	table, err := NewOverlapping(entry(0, 100, "all"), entry(10, 20, "x"), entry(15, 30, "y"), entry(40, 40, "z"))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, table.Size())
+	assert.Equal(t, []Entry[int, string]{entry(0, 100, "all"), entry(10, 20, "x"), entry(15, 30, "y")}, table.Find(17))
+	assert.Equal(t, []Entry[int, string]{entry(0, 100, "all"), entry(40, 40, "z")}, table.Find(40))
+	assert.Empty(t, table.Find(101))
+	assert.Equal(t, []Entry[int, string]{entry(0, 100, "all"), entry(15, 30, "y"), entry(40, 40, "z")},
+		table.Overlapping(Interval[int]{Start: 25, End: 45}))
+	assert.Len(t, table.Entries(), 4)
+
+	empty, _ := NewOverlapping[int, string]()
+	assert.Empty(t, empty.Find(0))
+}
+
+func TestOverlappingTable_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var entries []Entry[int, string]
+	for i := 0; i < 300; i++ {
+		start := r.Intn(1000)
+		entries = append(entries, entry(start, start+r.Intn(50), ""))
+	}
+	table, err := NewOverlapping(entries...)
+	assert.NoError(t, err)
+	for i := 0; i < 200; i++ {
+		start := r.Intn(1100) - 50
+		query := Interval[int]{Start: start, End: start + r.Intn(30)}
+		var want []Entry[int, string]
+		for _, e := range table.Entries() {
+			if e.Overlaps(query) {
+				want = append(want, e)
+			}
+		}
+		assert.Equal(t, want, table.Overlapping(query))
+	}
+}
@@ -0,0 +1,127 @@
+package binarysearch
+
+import (
+	"math"
+
+	"github.com/kwstars/goads/pkg/common"
+)
+
+// Search returns the smallest index i in [0, n) at which pred(i) is true, or n if there is none.
+// pred must be monotonic: false for a prefix of [0, n) and true for the rest.
+// It calls pred O(log n) times.
+func Search(n int, pred func(i int) bool) int {
+	left, right := 0, n
+	for left < right {
+		mid := int(uint(left+right) >> 1)
+		if pred(mid) {
+			right = mid
+		} else {
+			left = mid + 1
+		}
+	}
+	return left
+}
+
+// ExponentialSearch finds the index of the element equal to the target by doubling a bound from the
+// start of arr until it passes the target, then binary searching below it. It takes O(log i) for a
+// target at index i, which makes it faster than FindExact for targets near the start and usable on
+// sorted sequences whose length is expensive to know. If the target is not found, it returns -1.
+// cmp should return a negative number if a < b, zero if a == b, and a positive number if a > b.
+func ExponentialSearch[T1 any, T2 comparable](arr []T1, target T2, cmp Comparator[T1, T2]) int {
+	if len(arr) == 0 {
+		return -1
+	}
+	bound := 1
+	for bound < len(arr) && cmp(arr[bound], target) < 0 {
+		bound <<= 1
+	}
+	// The target is after arr[bound/2] and not after arr[bound].
+	lo, hi := bound>>1, bound+1
+	if hi > len(arr) {
+		hi = len(arr)
+	}
+	i := lo + LowerBound(arr[lo:hi], target, cmp)
+	if i < len(arr) && cmp(arr[i], target) == 0 {
+		return i
+	}
+	return -1
+}
+
+// InterpolationSearch finds the index of an element equal to the target in a sorted slice of numbers
+// by estimating its position from the values at both ends of the remaining range. It takes
+// O(log log n) on uniformly distributed values and O(n) in the worst case.
+// If the target is not found, it returns -1.
+func InterpolationSearch[T common.Number](arr []T, target T) int {
+	lo, hi := 0, len(arr)-1
+	for lo <= hi && target >= arr[lo] && target <= arr[hi] {
+		if arr[hi] == arr[lo] {
+			if arr[lo] == target {
+				return lo
+			}
+			return -1
+		}
+		// Estimate in float64 to avoid integer overflow. Close large integers can round to the same
+		// float64, so fall back to the midpoint when the estimate is unusable, and clamp it to [lo, hi].
+		pos := lo + (hi-lo)>>1
+		if span := float64(arr[hi]) - float64(arr[lo]); span != 0 {
+			frac := (float64(target) - float64(arr[lo])) / span
+			if !math.IsNaN(frac) && !math.IsInf(frac, 0) {
+				pos = lo + int(frac*float64(hi-lo))
+			}
+		}
+		if pos < lo {
+			pos = lo
+		} else if pos > hi {
+			pos = hi
+		}
+		switch {
+		case arr[pos] == target:
+			return pos
+		case arr[pos] < target:
+			lo = pos + 1
+		default:
+			hi = pos - 1
+		}
+	}
+	return -1
+}
+
+// FindRotationPoint returns the index of the smallest element of a sorted slice that has been
+// rotated, that is the number of positions it was rotated by, or 0 if arr is empty.
+// With duplicates the search may degrade to O(n).
+// cmp should return a negative number if a < b, zero if a == b, and a positive number if a > b.
+func FindRotationPoint[T comparable](arr []T, cmp Comparator[T, T]) int {
+	lo, hi := 0, len(arr)-1
+	for lo < hi {
+		mid := lo + (hi-lo)>>1
+		switch c := cmp(arr[mid], arr[hi]); {
+		case c > 0:
+			lo = mid + 1 // the drop is after mid
+		case c < 0:
+			hi = mid // arr[mid:hi+1] is ascending
+		default:
+			// arr[mid] == arr[hi] says nothing about the side of the drop. arr[hi] can be
+			// dropped unless it is the drop itself, which is the case if arr[hi-1] > arr[hi].
+			if cmp(arr[hi-1], arr[hi]) > 0 {
+				return hi
+			}
+			hi--
+		}
+	}
+	return lo
+}
+
+// FindInRotated finds the index of an element equal to the target in a sorted slice that has been
+// rotated, like [4 5 6 1 2 3]. It takes O(log n), or O(n) with many duplicates.
+// If the target is not found, it returns -1.
+// cmp should return a negative number if a < b, zero if a == b, and a positive number if a > b.
+func FindInRotated[T comparable](arr []T, target T, cmp Comparator[T, T]) int {
+	shift := FindRotationPoint(arr, cmp)
+	n := len(arr)
+	// Search the virtually unrotated sequence arr[(shift+i)%n].
+	i := Search(n, func(i int) bool { return cmp(arr[(shift+i)%n], target) >= 0 })
+	if i < n && cmp(arr[(shift+i)%n], target) == 0 {
+		return (shift + i) % n
+	}
+	return -1
+}
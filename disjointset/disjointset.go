@@ -0,0 +1,120 @@
+// Package disjointset implements disjoint-set (union-find) data structures.
+//
+// DisjointSet works on dense integer IDs and combines path compression with union by size,
+// so a sequence of m operations runs in O(m α(n)), where α is the inverse Ackermann function.
+// Generic adds a mapping from arbitrary comparable values to IDs, and Rollback trades path
+// compression for the ability to undo unions, which offline algorithms rely on.
+//
+// Structures are not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Disjoint-set_data_structure
+package disjointset
+
+// DisjointSet partitions the integers 0 to n-1 into disjoint sets.
+// Passing an ID outside that range panics, like indexing a slice out of range.
+type DisjointSet struct {
+	parent []int // parent[x] is the parent of x; roots are their own parent.
+	size   []int // size[r] is the number of elements of the set rooted at r.
+	count  int   // count is the number of sets.
+}
+
+// New returns a disjoint set of n singleton sets {0}, {1}, ..., {n-1}.
+func New(n int) *DisjointSet {
+	d := &DisjointSet{
+		parent: make([]int, n),
+		size:   make([]int, n),
+		count:  n,
+	}
+	for i := range d.parent {
+		d.parent[i] = i
+		d.size[i] = 1
+	}
+	return d
+}
+
+// MakeSet adds a new singleton set and returns its ID, which is the previous Len.
+func (d *DisjointSet) MakeSet() int {
+	id := len(d.parent)
+	d.parent = append(d.parent, id)
+	d.size = append(d.size, 1)
+	d.count++
+	return id
+}
+
+// Find returns the representative of the set containing x.
+// Every element visited on the way is re-linked directly to the root.
+func (d *DisjointSet) Find(x int) int {
+	root := x
+	for d.parent[root] != root {
+		root = d.parent[root]
+	}
+	for d.parent[x] != root {
+		d.parent[x], x = root, d.parent[x]
+	}
+	return root
+}
+
+// Union merges the sets containing x and y, attaching the smaller set under the larger one.
+// It returns false if x and y were already in the same set.
+func (d *DisjointSet) Union(x, y int) bool {
+	rx, ry := d.Find(x), d.Find(y)
+	if rx == ry {
+		return false
+	}
+	if d.size[rx] < d.size[ry] {
+		rx, ry = ry, rx
+	}
+	d.parent[ry] = rx
+	d.size[rx] += d.size[ry]
+	d.count--
+	return true
+}
+
+// Connected returns true if x and y are in the same set.
+func (d *DisjointSet) Connected(x, y int) bool {
+	return d.Find(x) == d.Find(y)
+}
+
+// SetSize returns the number of elements in the set containing x.
+func (d *DisjointSet) SetSize(x int) int {
+	return d.size[d.Find(x)]
+}
+
+// Count returns the number of disjoint sets.
+func (d *DisjointSet) Count() int {
+	return d.count
+}
+
+// Len returns the number of elements.
+func (d *DisjointSet) Len() int {
+	return len(d.parent)
+}
+
+// Members returns the elements of the set containing x in ascending order.
+func (d *DisjointSet) Members(x int) []int {
+	root := d.Find(x)
+	members := make([]int, 0, d.size[root])
+	for i := range d.parent {
+		if d.Find(i) == root {
+			members = append(members, i)
+		}
+	}
+	return members
+}
+
+// Components returns all sets. Each set is sorted and the sets are ordered by their smallest element.
+func (d *DisjointSet) Components() [][]int {
+	index := make(map[int]int, d.count) // root -> position in components
+	components := make([][]int, 0, d.count)
+	for i := range d.parent {
+		root := d.Find(i)
+		pos, ok := index[root]
+		if !ok {
+			pos = len(components)
+			index[root] = pos
+			components = append(components, make([]int, 0, d.size[root]))
+		}
+		components[pos] = append(components[pos], i)
+	}
+	return components
+}
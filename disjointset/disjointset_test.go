@@ -0,0 +1,126 @@
+package disjointset
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisjointSet(t *testing.T) {
+	d := New(6)
+	assert.Equal(t, 6, d.Count())
+	assert.False(t, d.Connected(0, 1))
+
+	assert.True(t, d.Union(0, 1))
+	assert.True(t, d.Union(1, 2))
+	assert.False(t, d.Union(0, 2))
+	assert.True(t, d.Union(3, 4))
+
+	assert.True(t, d.Connected(0, 2))
+	assert.False(t, d.Connected(2, 3))
+	assert.Equal(t, 3, d.Count())
+	assert.Equal(t, 3, d.SetSize(1))
+	assert.Equal(t, 1, d.SetSize(5))
+	assert.Equal(t, []int{3, 4}, d.Members(4))
+	assert.Equal(t, [][]int{{0, 1, 2}, {3, 4}, {5}}, d.Components())
+
+	id := d.MakeSet()
+	assert.Equal(t, 6, id)
+	assert.Equal(t, 7, d.Len())
+	assert.Equal(t, 4, d.Count())
+	d.Union(id, 5)
+	assert.Equal(t, []int{5, 6}, d.Members(6))
+}
+
+func TestDisjointSet_PathCompression(t *testing.T) {
+	d := New(5)
+	// Build the chain 4 -> 3 -> 2 -> 1 -> 0 by hand.
+	for i := 1; i < 5; i++ {
+		d.parent[i] = i - 1
+	}
+	assert.Equal(t, 0, d.Find(4))
+	for i := 1; i < 5; i++ {
+		assert.Equal(t, 0, d.parent[i])
+	}
+}
+
+func TestDisjointSet_MatchesNaive(t *testing.T) {
+	const n = 200
+	r := rand.New(rand.NewSource(1))
+	d := New(n)
+	label := make([]int, n) // naive: every element stores its set label
+	for i := range label {
+		label[i] = i
+	}
+
+	for i := 0; i < 500; i++ {
+		x, y := r.Intn(n), r.Intn(n)
+		merged := d.Union(x, y)
+		assert.Equal(t, label[x] != label[y], merged)
+		if merged {
+			old := label[y]
+			for j := range label {
+				if label[j] == old {
+					label[j] = label[x]
+				}
+			}
+		}
+	}
+	for i := 0; i < 500; i++ {
+		x, y := r.Intn(n), r.Intn(n)
+		assert.Equal(t, label[x] == label[y], d.Connected(x, y))
+	}
+}
+
+func TestGeneric(t *testing.T) {
+	g := NewGeneric("a", "b", "c")
+	assert.Equal(t, 3, g.Count())
+
+	assert.True(t, g.Union("a", "b"))
+	assert.True(t, g.Union("c", "d")) // "d" is added on first use
+	assert.False(t, g.Union("b", "a"))
+
+	assert.True(t, g.Contains("d"))
+	assert.False(t, g.Contains("e"))
+	assert.Equal(t, 4, g.Len())
+	assert.Equal(t, 2, g.Count())
+	assert.True(t, g.Connected("a", "b"))
+	assert.False(t, g.Connected("a", "c"))
+	assert.False(t, g.Connected("a", "e"))
+	assert.True(t, g.Connected("e", "e"))
+	assert.Equal(t, g.Find("a"), g.Find("b"))
+	assert.Equal(t, 2, g.SetSize("d"))
+	assert.Equal(t, 0, g.SetSize("e"))
+	assert.Equal(t, []string{"c", "d"}, g.Members("d"))
+	assert.Nil(t, g.Members("e"))
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}}, g.Components())
+}
+
+func TestRollback(t *testing.T) {
+	r := NewRollback(5)
+	assert.False(t, r.Undo())
+
+	r.Union(0, 1)
+	snapshot := r.Snapshot()
+	r.Union(2, 3)
+	r.Union(1, 3)
+	assert.False(t, r.Union(0, 2))
+	assert.True(t, r.Connected(0, 2))
+	assert.Equal(t, 4, r.SetSize(3))
+	assert.Equal(t, 2, r.Count())
+
+	assert.True(t, r.Undo())
+	assert.False(t, r.Connected(0, 2))
+	assert.True(t, r.Connected(2, 3))
+	assert.Equal(t, 2, r.SetSize(0))
+	assert.Equal(t, 3, r.Count())
+
+	r.Union(4, 0)
+	r.RollbackTo(snapshot)
+	assert.True(t, r.Connected(0, 1))
+	assert.False(t, r.Connected(2, 3))
+	assert.False(t, r.Connected(4, 0))
+	assert.Equal(t, 4, r.Count())
+	assert.Equal(t, []int{1, 0, 0, 0, 0}, r.rank)
+}
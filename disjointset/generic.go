@@ -0,0 +1,111 @@
+package disjointset
+
+// Generic is a disjoint set over arbitrary comparable values.
+// Values are added on first use, so Union and Find never fail.
+type Generic[T comparable] struct {
+	ids    map[T]int // ids maps a value to its ID in sets.
+	values []T       // values maps an ID back to its value.
+	sets   *DisjointSet
+}
+
+// NewGeneric returns a disjoint set holding a singleton set for each of the given values.
+func NewGeneric[T comparable](values ...T) *Generic[T] {
+	g := &Generic[T]{
+		ids:  make(map[T]int, len(values)),
+		sets: New(0),
+	}
+	g.Add(values...)
+	return g
+}
+
+// Add adds each value that is not yet present as a singleton set.
+func (g *Generic[T]) Add(values ...T) {
+	for _, v := range values {
+		g.id(v)
+	}
+}
+
+// id returns the ID of v, adding v if needed.
+func (g *Generic[T]) id(v T) int {
+	if id, ok := g.ids[v]; ok {
+		return id
+	}
+	id := g.sets.MakeSet()
+	g.ids[v] = id
+	g.values = append(g.values, v)
+	return id
+}
+
+// Contains returns true if v has been added.
+func (g *Generic[T]) Contains(v T) bool {
+	_, ok := g.ids[v]
+	return ok
+}
+
+// Find returns the representative of the set containing v.
+func (g *Generic[T]) Find(v T) T {
+	return g.values[g.sets.Find(g.id(v))]
+}
+
+// Union merges the sets containing x and y.
+// It returns false if x and y were already in the same set.
+func (g *Generic[T]) Union(x, y T) bool {
+	return g.sets.Union(g.id(x), g.id(y))
+}
+
+// Connected returns true if x and y are in the same set.
+// A value that has not been added only forms a set with itself.
+func (g *Generic[T]) Connected(x, y T) bool {
+	ix, okx := g.ids[x]
+	iy, oky := g.ids[y]
+	if !okx || !oky {
+		return x == y
+	}
+	return g.sets.Connected(ix, iy)
+}
+
+// SetSize returns the number of elements in the set containing v, or 0 if v is unknown.
+func (g *Generic[T]) SetSize(v T) int {
+	id, ok := g.ids[v]
+	if !ok {
+		return 0
+	}
+	return g.sets.SetSize(id)
+}
+
+// Count returns the number of disjoint sets.
+func (g *Generic[T]) Count() int {
+	return g.sets.Count()
+}
+
+// Len returns the number of elements.
+func (g *Generic[T]) Len() int {
+	return len(g.values)
+}
+
+// Members returns the elements of the set containing v in insertion order, or nil if v is unknown.
+func (g *Generic[T]) Members(v T) []T {
+	id, ok := g.ids[v]
+	if !ok {
+		return nil
+	}
+	return g.toValues(g.sets.Members(id))
+}
+
+// Components returns all sets. Elements keep their insertion order.
+func (g *Generic[T]) Components() [][]T {
+	components := g.sets.Components()
+	result := make([][]T, len(components))
+	for i, c := range components {
+		result[i] = g.toValues(c)
+	}
+	return result
+}
+
+func (g *Generic[T]) toValues(ids []int) []T {
+	values := make([]T, len(ids))
+	for i, id := range ids {
+		values[i] = g.values[id]
+	}
+	return values
+}
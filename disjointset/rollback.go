@@ -0,0 +1,108 @@
+package disjointset
+
+// merge records a union so that it can be undone.
+type merge struct {
+	child, parent int  // child is the root that was attached under parent.
+	rankIncreased bool // rankIncreased tells whether the rank of parent went up.
+}
+
+// Rollback is a disjoint set whose unions can be undone in LIFO order.
+//
+// It uses union by rank without path compression, so the trees never change except through
+// Union and a union can be reverted in O(1). Find runs in O(log n).
+// This is the variant needed by offline algorithms such as dynamic connectivity over time segments.
+type Rollback struct {
+	parent  []int
+	rank    []int
+	size    []int
+	count   int
+	history []merge
+}
+
+// NewRollback returns a rollback-capable disjoint set of n singleton sets.
+func NewRollback(n int) *Rollback {
+	r := &Rollback{
+		parent: make([]int, n),
+		rank:   make([]int, n),
+		size:   make([]int, n),
+		count:  n,
+	}
+	for i := range r.parent {
+		r.parent[i] = i
+		r.size[i] = 1
+	}
+	return r
+}
+
+// Find returns the representative of the set containing x.
+func (r *Rollback) Find(x int) int {
+	for r.parent[x] != x {
+		x = r.parent[x]
+	}
+	return x
+}
+
+// Union merges the sets containing x and y, attaching the root of lower rank under the other.
+// It returns false, and records nothing, if x and y were already in the same set.
+func (r *Rollback) Union(x, y int) bool {
+	rx, ry := r.Find(x), r.Find(y)
+	if rx == ry {
+		return false
+	}
+	if r.rank[rx] < r.rank[ry] {
+		rx, ry = ry, rx
+	}
+	m := merge{child: ry, parent: rx, rankIncreased: r.rank[rx] == r.rank[ry]}
+	r.parent[ry] = rx
+	r.size[rx] += r.size[ry]
+	if m.rankIncreased {
+		r.rank[rx]++
+	}
+	r.count--
+	r.history = append(r.history, m)
+	return true
+}
+
+// Connected returns true if x and y are in the same set.
+func (r *Rollback) Connected(x, y int) bool {
+	return r.Find(x) == r.Find(y)
+}
+
+// SetSize returns the number of elements in the set containing x.
+func (r *Rollback) SetSize(x int) int {
+	return r.size[r.Find(x)]
+}
+
+// Count returns the number of disjoint sets.
+func (r *Rollback) Count() int {
+	return r.count
+}
+
+// Snapshot returns a marker of the current state that can be passed to RollbackTo.
+func (r *Rollback) Snapshot() int {
+	return len(r.history)
+}
+
+// Undo reverts the most recent successful union. It returns false if there is nothing to undo.
+func (r *Rollback) Undo() bool {
+	if len(r.history) == 0 {
+		return false
+	}
+	m := r.history[len(r.history)-1]
+	r.history = r.history[:len(r.history)-1]
+
+	r.parent[m.child] = m.child
+	r.size[m.parent] -= r.size[m.child]
+	if m.rankIncreased {
+		r.rank[m.parent]--
+	}
+	r.count++
+	return true
+}
+
+// RollbackTo reverts all unions made after snapshot was taken.
+func (r *Rollback) RollbackTo(snapshot int) {
+	for len(r.history) > snapshot {
+		r.Undo()
+	}
+}
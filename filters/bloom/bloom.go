@@ -0,0 +1,138 @@
+// Package bloom implements Bloom filters: approximate sets that answer membership queries with
+// a tunable false-positive rate and no false negatives.
+//
+//   - Filter is the classic Bloom filter: k hash positions in an array of m bits per item.
+//   - CountingFilter replaces the bits by 4-bit counters so that items can be removed again.
+//   - ScalableFilter chains filters of growing size, so it keeps its false-positive rate
+//     when more items arrive than expected.
+//
+// The k positions of an item are derived from one 64-bit hash by double hashing,
+// g_i = h1 + i·h2 (mod m), which performs like k independent hash functions.
+//
+// Structures are not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Bloom_filter,
+// https://www.eecs.harvard.edu/~michaelm/postscripts/rsa2008.pdf (double hashing),
+// https://gsd.di.uminho.pt/members/cbm/ps/dbloom.pdf (scalable Bloom filters)
+package bloom
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/kwstars/goads/filters"
+	"github.com/kwstars/goads/pkg/common"
+)
+
+var _ filters.Filter[int] = (*Filter[int])(nil)
+
+var (
+	ErrInvalidParameter = errors.New("invalid filter parameter")
+	ErrIncompatible     = errors.New("filters have different sizes")
+)
+
+// Parameters returns the number of bits m and of hash functions k that minimize the memory of a
+// Bloom filter holding n items at false-positive rate p: m = -n·ln p / ln²2 and k = m/n · ln 2.
+func Parameters(n int, p float64) (m uint64, k int, err error) {
+	if n <= 0 || !(p > 0 && p < 1) {
+		return 0, 0, fmt.Errorf("%w: %d items at false-positive rate %v", ErrInvalidParameter, n, p)
+	}
+	m = uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k = int(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return m, k, nil
+}
+
+// locations calls fn with the k positions of hash in [0, m).
+func locations(hash, m uint64, k int, fn func(i uint64) bool) bool {
+	h1, h2 := hash, common.Mix64(hash)|1
+	for i := 0; i < k; i++ {
+		if !fn((h1 + uint64(i)*h2) % m) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter is a Bloom filter.
+type Filter[T any] struct {
+	words  []uint64
+	m      uint64
+	k      int
+	count  int
+	hasher common.Hasher[T]
+}
+
+// New returns a Bloom filter sized for n items at false-positive rate p.
+func New[T any](hasher common.Hasher[T], n int, p float64) (*Filter[T], error) {
+	m, k, err := Parameters(n, p)
+	if err != nil {
+		return nil, err
+	}
+	return &Filter[T]{words: make([]uint64, (m+63)/64), m: m, k: k, hasher: hasher}, nil
+}
+
+// Add adds the item. It never fails; the error result is there for filters.Filter.
+func (f *Filter[T]) Add(item T) error {
+	locations(f.hasher(item), f.m, f.k, func(i uint64) bool {
+		f.words[i/64] |= 1 << (i % 64)
+		return true
+	})
+	f.count++
+	return nil
+}
+
+// Contains returns false if the item was certainly not added, and true if it probably was.
+func (f *Filter[T]) Contains(item T) bool {
+	return locations(f.hasher(item), f.m, f.k, func(i uint64) bool {
+		return f.words[i/64]&(1<<(i%64)) != 0
+	})
+}
+
+// Count returns the number of items added, counting repeated items each time.
+func (f *Filter[T]) Count() int {
+	return f.count
+}
+
+// Bits returns the size of the bit array m.
+func (f *Filter[T]) Bits() uint64 {
+	return f.m
+}
+
+// HashFunctions returns the number of positions k set per item.
+func (f *Filter[T]) HashFunctions() int {
+	return f.k
+}
+
+// FalsePositiveRate estimates the current false-positive rate from the fraction of set bits.
+func (f *Filter[T]) FalsePositiveRate() float64 {
+	set := 0
+	for _, w := range f.words {
+		set += bits.OnesCount64(w)
+	}
+	return math.Pow(float64(set)/float64(f.m), float64(f.k))
+}
+
+// Union adds all items of other, which must have the same size and hash functions, to f.
+func (f *Filter[T]) Union(other *Filter[T]) error {
+	if f.m != other.m || f.k != other.k {
+		return fmt.Errorf("%w: m=%d k=%d and m=%d k=%d", ErrIncompatible, f.m, f.k, other.m, other.k)
+	}
+	for i, w := range other.words {
+		f.words[i] |= w
+	}
+	f.count += other.count
+	return nil
+}
+
+// Clear removes all items.
+func (f *Filter[T]) Clear() {
+	for i := range f.words {
+		f.words[i] = 0
+	}
+	f.count = 0
+}
@@ -0,0 +1,155 @@
+package bloom
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// falsePositives returns the fraction of n items never added that test positive.
+func falsePositives(contains func(int) bool, n int) float64 {
+	hits := 0
+	for i := 0; i < n; i++ {
+		if contains(-1 - i) {
+			hits++
+		}
+	}
+	return float64(hits) / float64(n)
+}
+
+func TestParameters(t *testing.T) {
+	m, k, err := Parameters(1000, 0.01)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(9586), m)
+	assert.Equal(t, 7, k)
+
+	for _, p := range []float64{0, 1, -0.5} {
+		_, _, err = Parameters(1000, p)
+		assert.True(t, errors.Is(err, ErrInvalidParameter))
+	}
+	_, err = New(common.IntHasher, 0, 0.01)
+	assert.True(t, errors.Is(err, ErrInvalidParameter))
+}
+
+func TestFilter(t *testing.T) {
+	f, err := New(common.IntHasher, 10000, 0.01)
+	assert.NoError(t, err)
+	for i := 0; i < 10000; i++ {
+		assert.NoError(t, f.Add(i))
+	}
+	for i := 0; i < 10000; i++ {
+		assert.True(t, f.Contains(i))
+	}
+	assert.Equal(t, 10000, f.Count())
+	assert.Less(t, falsePositives(f.Contains, 100000), 0.015)
+	assert.InDelta(t, 0.01, f.FalsePositiveRate(), 0.003)
+
+	g, _ := New(common.IntHasher, 10000, 0.01)
+	assert.NoError(t, g.Add(-5))
+	assert.NoError(t, f.Union(g))
+	assert.True(t, f.Contains(-5))
+	h, _ := New(common.IntHasher, 10, 0.01)
+	assert.True(t, errors.Is(f.Union(h), ErrIncompatible))
+
+	data, err := f.MarshalBinary()
+	assert.NoError(t, err)
+	decoded, _ := New(common.IntHasher, 1, 0.5)
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, f.words, decoded.words)
+	assert.Equal(t, f.Bits(), decoded.Bits())
+	assert.Equal(t, f.HashFunctions(), decoded.HashFunctions())
+	assert.Equal(t, f.Count(), decoded.Count())
+	assert.True(t, errors.Is(decoded.UnmarshalBinary(data[:len(data)-1]), ErrInvalidFormat))
+	assert.True(t, errors.Is(decoded.UnmarshalBinary(append(data, 0)), ErrInvalidFormat))
+
+	f.Clear()
+	assert.False(t, f.Contains(1))
+	assert.Equal(t, 0, f.Count())
+}
+
+func TestCountingFilter(t *testing.T) {
+	f, err := NewCounting(common.StringHasher, 1000, 0.01)
+	assert.NoError(t, err)
+	for i := 0; i < 1000; i++ {
+		assert.NoError(t, f.Add(strconv.Itoa(i)))
+	}
+	assert.NoError(t, f.Add("7"))
+	for i := 0; i < 1000; i += 2 {
+		assert.NoError(t, f.Remove(strconv.Itoa(i)))
+	}
+	for i := 1; i < 1000; i += 2 {
+		assert.True(t, f.Contains(strconv.Itoa(i)))
+	}
+	removed := 0
+	for i := 0; i < 1000; i += 2 {
+		if !f.Contains(strconv.Itoa(i)) {
+			removed++
+		}
+	}
+	assert.Greater(t, removed, 490)
+	assert.Equal(t, 501, f.Count())
+
+	// "7" was added twice and survives one removal.
+	assert.NoError(t, f.Remove("7"))
+	assert.True(t, f.Contains("7"))
+	assert.True(t, errors.Is(f.Remove("x"), ErrNotPresent))
+
+	data, _ := f.MarshalBinary()
+	decoded, _ := NewCounting(common.StringHasher, 1, 0.5)
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.True(t, decoded.Contains("7"))
+	assert.Equal(t, f.counters, decoded.counters)
+	_, err = (&Filter[string]{}).MarshalBinary()
+	assert.NoError(t, err)
+	assert.True(t, errors.Is((&Filter[string]{}).UnmarshalBinary(data), ErrInvalidFormat))
+
+	// Counters stick at their maximum instead of wrapping around.
+	g, _ := NewCounting(common.StringHasher, 10, 0.1)
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, g.Add("a"))
+	}
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, g.Remove("a"))
+	}
+	assert.True(t, g.Contains("a"))
+}
+
+func TestScalableFilter(t *testing.T) {
+	f, err := NewScalable(common.IntHasher, 100, 0.01)
+	assert.NoError(t, err)
+	for i := 0; i < 10000; i++ {
+		assert.NoError(t, f.Add(i))
+		assert.NoError(t, f.Add(i))
+	}
+	for i := 0; i < 10000; i++ {
+		assert.True(t, f.Contains(i))
+	}
+	assert.Greater(t, f.Filters(), 5)
+	assert.InDelta(t, 10000, f.Count(), 100)
+	assert.Less(t, falsePositives(f.Contains, 100000), 0.015)
+
+	data, err := f.MarshalBinary()
+	assert.NoError(t, err)
+	decoded, _ := NewScalable(common.IntHasher, 1, 0.5)
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, f.Filters(), decoded.Filters())
+	assert.Equal(t, f.Count(), decoded.Count())
+	for i := 0; i < 10000; i += 7 {
+		assert.True(t, decoded.Contains(i))
+	}
+	assert.True(t, errors.Is(decoded.UnmarshalBinary(data[:100]), ErrInvalidFormat))
+
+	// A crafted header with a huge sub-filter count is rejected before anything is allocated.
+	for _, n := range []uint32{1 << 28, math.MaxUint32} {
+		header := appendUint32(data[:20:20], n)
+		assert.True(t, errors.Is(decoded.UnmarshalBinary(header), ErrInvalidFormat))
+	}
+
+	f.Clear()
+	assert.Equal(t, 1, f.Filters())
+	assert.Equal(t, 0, f.Count())
+}
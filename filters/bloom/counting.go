@@ -0,0 +1,97 @@
+package bloom
+
+import (
+	"errors"
+
+	"github.com/kwstars/goads/filters"
+	"github.com/kwstars/goads/pkg/common"
+)
+
+var _ filters.Filter[int] = (*CountingFilter[int])(nil)
+
+var ErrNotPresent = errors.New("item not present")
+
+// maxCounter is the value at which a 4-bit counter sticks: it no longer knows how many items set it,
+// so it is never decremented again.
+const maxCounter = 15
+
+// CountingFilter is a Bloom filter with 4-bit counters instead of bits, which supports Remove.
+// It uses four times the memory of a Filter with the same parameters.
+type CountingFilter[T any] struct {
+	counters []byte // two counters per byte, the even one in the low nibble
+	m        uint64
+	k        int
+	count    int
+	hasher   common.Hasher[T]
+}
+
+// NewCounting returns a counting Bloom filter sized for n items at false-positive rate p.
+func NewCounting[T any](hasher common.Hasher[T], n int, p float64) (*CountingFilter[T], error) {
+	m, k, err := Parameters(n, p)
+	if err != nil {
+		return nil, err
+	}
+	return &CountingFilter[T]{counters: make([]byte, (m+1)/2), m: m, k: k, hasher: hasher}, nil
+}
+
+func (f *CountingFilter[T]) counter(i uint64) byte {
+	return f.counters[i/2] >> (4 * (i % 2)) & 0xf
+}
+
+func (f *CountingFilter[T]) setCounter(i uint64, c byte) {
+	shift := 4 * (i % 2)
+	f.counters[i/2] = f.counters[i/2]&^(0xf<<shift) | c<<shift
+}
+
+// Add adds the item. It never fails; the error result is there for filters.Filter.
+func (f *CountingFilter[T]) Add(item T) error {
+	locations(f.hasher(item), f.m, f.k, func(i uint64) bool {
+		if c := f.counter(i); c < maxCounter {
+			f.setCounter(i, c+1)
+		}
+		return true
+	})
+	f.count++
+	return nil
+}
+
+// Remove removes one occurrence of the item. It returns ErrNotPresent if the item was certainly
+// not added. Removing an item that was never added, but tests positive, corrupts the filter.
+func (f *CountingFilter[T]) Remove(item T) error {
+	hash := f.hasher(item)
+	if !f.contains(hash) {
+		return ErrNotPresent
+	}
+	locations(hash, f.m, f.k, func(i uint64) bool {
+		if c := f.counter(i); c < maxCounter {
+			f.setCounter(i, c-1)
+		}
+		return true
+	})
+	f.count--
+	return nil
+}
+
+// Contains returns false if the item was certainly not added, and true if it probably was.
+func (f *CountingFilter[T]) Contains(item T) bool {
+	return f.contains(f.hasher(item))
+}
+
+func (f *CountingFilter[T]) contains(hash uint64) bool {
+	return locations(hash, f.m, f.k, func(i uint64) bool {
+		return f.counter(i) != 0
+	})
+}
+
+// Count returns the number of items added and not removed.
+func (f *CountingFilter[T]) Count() int {
+	return f.count
+}
+
+// Clear removes all items.
+func (f *CountingFilter[T]) Clear() {
+	for i := range f.counters {
+		f.counters[i] = 0
+	}
+	f.count = 0
+}
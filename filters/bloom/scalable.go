@@ -0,0 +1,98 @@
+package bloom
+
+import (
+	"math"
+
+	"github.com/kwstars/goads/filters"
+	"github.com/kwstars/goads/pkg/common"
+)
+
+var _ filters.Filter[int] = (*ScalableFilter[int])(nil)
+
+const (
+	// growth is the factor by which each new filter's capacity exceeds the previous one.
+	growth = 2
+	// tightening is the factor applied to the false-positive rate of each new filter. The rates form
+	// a geometric series, so the compound rate stays below the rate the filter was created with.
+	tightening = 0.8
+)
+
+// ScalableFilter is a Bloom filter that grows: when the current filter has taken as many items
+// as it was sized for, a larger one with a lower false-positive rate is added.
+type ScalableFilter[T any] struct {
+	filters  []*Filter[T]
+	capacity int     // items the first filter is sized for
+	p        float64 // false-positive rate bound of the whole filter
+	hasher   common.Hasher[T]
+}
+
+// NewScalable returns a scalable Bloom filter that starts sized for n items and keeps
+// its false-positive rate below p however many items are added.
+func NewScalable[T any](hasher common.Hasher[T], n int, p float64) (*ScalableFilter[T], error) {
+	if _, _, err := Parameters(n, p); err != nil {
+		return nil, err
+	}
+	f := &ScalableFilter[T]{capacity: n, p: p, hasher: hasher}
+	f.Clear()
+	return f, nil
+}
+
+// grow appends the next filter.
+func (f *ScalableFilter[T]) grow() {
+	i := len(f.filters)
+	p := f.p * (1 - tightening) * math.Pow(tightening, float64(i))
+	next, _ := New(f.hasher, f.capacityOf(i), p)
+	f.filters = append(f.filters, next)
+}
+
+// Add adds the item. Items that already test positive are not added again, so that repeated
+// items do not use up capacity. It never fails; the error result is there for filters.Filter.
+func (f *ScalableFilter[T]) Add(item T) error {
+	if f.Contains(item) {
+		return nil
+	}
+	last := f.filters[len(f.filters)-1]
+	if last.count >= f.capacityOf(len(f.filters)-1) {
+		f.grow()
+		last = f.filters[len(f.filters)-1]
+	}
+	return last.Add(item)
+}
+
+func (f *ScalableFilter[T]) capacityOf(i int) int {
+	n := f.capacity
+	for j := 0; j < i; j++ {
+		n *= growth
+	}
+	return n
+}
+
+// Contains returns false if the item was certainly not added, and true if it probably was.
+func (f *ScalableFilter[T]) Contains(item T) bool {
+	for _, sub := range f.filters {
+		if sub.Contains(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of distinct items added, not counting items that tested positive when added.
+func (f *ScalableFilter[T]) Count() int {
+	count := 0
+	for _, sub := range f.filters {
+		count += sub.count
+	}
+	return count
+}
+
+// Filters returns the number of filters in the chain.
+func (f *ScalableFilter[T]) Filters() int {
+	return len(f.filters)
+}
+
+// Clear removes all items and shrinks the chain back to the first filter.
+func (f *ScalableFilter[T]) Clear() {
+	f.filters = nil
+	f.grow()
+}
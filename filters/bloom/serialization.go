@@ -0,0 +1,185 @@
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The serialized formats are little-endian and start with a cookie naming the filter type:
+//
+//	Filter          "BLM1"  m uint64, k uint32, count uint64, ⌈m/64⌉ × uint64 bit words
+//	CountingFilter  "BLC1"  m uint64, k uint32, count uint64, ⌈m/2⌉ bytes of counter pairs
+//	ScalableFilter  "BLS1"  capacity uint64, p float64, n uint32, n × (length uint32, Filter encoding)
+//
+// The hasher is not part of the encoding.
+const (
+	filterCookie   uint32 = 0x314d4c42
+	countingCookie uint32 = 0x31434c42
+	scalableCookie uint32 = 0x31534c42
+)
+
+// minSubFilterSize is the smallest encoding of a sub-filter of a ScalableFilter:
+// its length prefix and the Filter header.
+const minSubFilterSize = 4 + 24
+
+var ErrInvalidFormat = errors.New("invalid bloom filter encoding")
+
+// MarshalBinary encodes the filter.
+func (f *Filter[T]) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, 24+8*len(f.words))
+	buf = appendHeader(buf, filterCookie, f.m, f.k, f.count)
+	for _, w := range f.words {
+		buf = appendUint64(buf, w)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary replaces the content of the filter with the decoded data. The hasher is kept.
+func (f *Filter[T]) UnmarshalBinary(data []byte) error {
+	d := &decoder{data: data}
+	m, k, count := d.header(filterCookie)
+	words := make([]uint64, 0, len(d.data)/8)
+	for d.err == nil && len(words) < int((m+63)/64) {
+		words = append(words, d.uint64())
+	}
+	if err := d.finish(); err != nil {
+		return err
+	}
+	f.words, f.m, f.k, f.count = words, m, k, count
+	return nil
+}
+
+// MarshalBinary encodes the filter.
+func (f *CountingFilter[T]) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, 24+len(f.counters))
+	buf = appendHeader(buf, countingCookie, f.m, f.k, f.count)
+	return append(buf, f.counters...), nil
+}
+
+// UnmarshalBinary replaces the content of the filter with the decoded data. The hasher is kept.
+func (f *CountingFilter[T]) UnmarshalBinary(data []byte) error {
+	d := &decoder{data: data}
+	m, k, count := d.header(countingCookie)
+	counters := append([]byte(nil), d.take(int((m+1)/2))...)
+	if err := d.finish(); err != nil {
+		return err
+	}
+	f.counters, f.m, f.k, f.count = counters, m, k, count
+	return nil
+}
+
+// MarshalBinary encodes the filter.
+func (f *ScalableFilter[T]) MarshalBinary() ([]byte, error) {
+	buf := appendUint32(nil, scalableCookie)
+	buf = appendUint64(buf, uint64(f.capacity))
+	buf = appendUint64(buf, math.Float64bits(f.p))
+	buf = appendUint32(buf, uint32(len(f.filters)))
+	for _, sub := range f.filters {
+		data, _ := sub.MarshalBinary()
+		buf = appendUint32(buf, uint32(len(data)))
+		buf = append(buf, data...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary replaces the content of the filter with the decoded data. The hasher is kept.
+func (f *ScalableFilter[T]) UnmarshalBinary(data []byte) error {
+	d := &decoder{data: data}
+	if d.uint32() != scalableCookie && d.err == nil {
+		return fmt.Errorf("%w: bad cookie", ErrInvalidFormat)
+	}
+	capacity, p, n := int(d.uint64()), math.Float64frombits(d.uint64()), int(d.uint32())
+	if d.err != nil {
+		return d.err
+	}
+	// Check the count before allocating: it comes straight from the input.
+	if capacity <= 0 || !(p > 0 && p < 1) || n == 0 || n > len(d.data)/minSubFilterSize {
+		return fmt.Errorf("%w: capacity %d, rate %v, %d filters", ErrInvalidFormat, capacity, p, n)
+	}
+	subs := make([]*Filter[T], 0, n)
+	for i := 0; i < n && d.err == nil; i++ {
+		sub := &Filter[T]{hasher: f.hasher}
+		if err := sub.UnmarshalBinary(d.take(int(d.uint32()))); err != nil && d.err == nil {
+			return err
+		}
+		subs = append(subs, sub)
+	}
+	if err := d.finish(); err != nil {
+		return err
+	}
+	f.filters, f.capacity, f.p = subs, capacity, p
+	return nil
+}
+
+func appendHeader(buf []byte, cookie uint32, m uint64, k, count int) []byte {
+	buf = appendUint32(buf, cookie)
+	buf = appendUint64(buf, m)
+	buf = appendUint32(buf, uint32(k))
+	return appendUint64(buf, uint64(count))
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	return appendUint32(appendUint32(buf, uint32(v)), uint32(v>>32))
+}
+
+// decoder reads little-endian values and remembers the first error.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) take(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || len(d.data) < n {
+		d.err = fmt.Errorf("%w: unexpected end of data", ErrInvalidFormat)
+		return nil
+	}
+	p := d.data[:n]
+	d.data = d.data[n:]
+	return p
+}
+
+func (d *decoder) uint32() uint32 {
+	if p := d.take(4); p != nil {
+		return binary.LittleEndian.Uint32(p)
+	}
+	return 0
+}
+
+func (d *decoder) uint64() uint64 {
+	if p := d.take(8); p != nil {
+		return binary.LittleEndian.Uint64(p)
+	}
+	return 0
+}
+
+// header reads and checks the header of a Filter or CountingFilter.
+func (d *decoder) header(cookie uint32) (m uint64, k, count int) {
+	if d.uint32() != cookie && d.err == nil {
+		d.err = fmt.Errorf("%w: bad cookie", ErrInvalidFormat)
+	}
+	m, k, count = d.uint64(), int(d.uint32()), int(d.uint64())
+	if d.err == nil && (m == 0 || k == 0 || count < 0 || m > uint64(len(d.data))*8) {
+		d.err = fmt.Errorf("%w: m=%d k=%d count=%d", ErrInvalidFormat, m, k, count)
+	}
+	return m, k, count
+}
+
+// finish returns the first error, or an error if data is left over.
+func (d *decoder) finish() error {
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.data) != 0 {
+		return fmt.Errorf("%w: %d trailing bytes", ErrInvalidFormat, len(d.data))
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+// Package cuckoo implements a cuckoo filter: an approximate set that, unlike a Bloom filter,
+// supports removing items and needs less memory at low false-positive rates.
+//
+// The filter stores a 16-bit fingerprint of each item in one of two buckets of four slots.
+// The second bucket is derived from the first and the fingerprint alone (partial-key cuckoo hashing),
+// so an entry can be relocated without knowing the item. When both buckets are full, a random
+// resident is kicked to its other bucket, and so on, until a free slot is found. The false-positive
+// rate is about 8/2^16 ≈ 0.012%.
+//
+// Adding the same item more than eight times fills both of its buckets, and removing an item that
+// was never added may remove the fingerprint of another item.
+//
+// Structure is not thread safe.
+//
+// References: https://www.cs.cmu.edu/~dga/papers/cuckoo-conext2014.pdf
+package cuckoo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kwstars/goads/filters"
+	"github.com/kwstars/goads/pkg/common"
+)
+
+var _ filters.Filter[int] = (*Filter[int])(nil)
+
+const (
+	bucketSize    = 4
+	maxLoadFactor = 0.95
+	maxKicks      = 500
+	seed          = 0x9e3779b97f4a7c15
+)
+
+var (
+	ErrInvalidParameter = errors.New("invalid filter parameter")
+	ErrFull             = errors.New("cuckoo filter is full")
+)
+
+// victim holds the fingerprint that was left homeless by the last failed relocation.
+type victim struct {
+	index uint64
+	fp    uint16
+	used  bool
+}
+
+// Filter is a cuckoo filter.
+type Filter[T any] struct {
+	slots  []uint16 // bucketSize slots per bucket; 0 marks a free slot
+	mask   uint64   // number of buckets - 1
+	count  int
+	victim victim
+	rand   uint64 // xorshift state used to pick the entry to kick
+	hasher common.Hasher[T]
+}
+
+// New returns a cuckoo filter that holds at least capacity items.
+// The number of buckets is rounded up to a power of two.
+func New[T any](hasher common.Hasher[T], capacity int) (*Filter[T], error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("%w: capacity %d", ErrInvalidParameter, capacity)
+	}
+	buckets := uint64(2)
+	for float64(buckets*bucketSize)*maxLoadFactor < float64(capacity) {
+		buckets <<= 1
+	}
+	return &Filter[T]{slots: make([]uint16, buckets*bucketSize), mask: buckets - 1, rand: seed, hasher: hasher}, nil
+}
+
+// fingerprintAndIndex splits the hash of item into a non-zero fingerprint and its first bucket.
+func (f *Filter[T]) fingerprintAndIndex(item T) (uint16, uint64) {
+	h := f.hasher(item)
+	fp := uint16(h >> 48)
+	if fp == 0 {
+		fp = 1
+	}
+	return fp, h & f.mask
+}
+
+// altIndex returns the other bucket of fp. It is its own inverse: altIndex(altIndex(i, fp), fp) == i.
+func (f *Filter[T]) altIndex(i uint64, fp uint16) uint64 {
+	return (i ^ common.Mix64(uint64(fp))) & f.mask
+}
+
+func (f *Filter[T]) bucket(i uint64) []uint16 {
+	return f.slots[i*bucketSize : (i+1)*bucketSize]
+}
+
+// insert puts fp into a free slot of bucket i.
+func (f *Filter[T]) insert(i uint64, fp uint16) bool {
+	b := f.bucket(i)
+	for j := range b {
+		if b[j] == 0 {
+			b[j] = fp
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filter[T]) has(i uint64, fp uint16) bool {
+	for _, s := range f.bucket(i) {
+		if s == fp {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filter[T]) delete(i uint64, fp uint16) bool {
+	b := f.bucket(i)
+	for j := range b {
+		if b[j] == fp {
+			b[j] = 0
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filter[T]) next() uint64 {
+	f.rand ^= f.rand << 13
+	f.rand ^= f.rand >> 7
+	f.rand ^= f.rand << 17
+	return f.rand
+}
+
+// Add adds the item. It returns ErrFull if an earlier relocation failed and the filter has no room left;
+// the item that triggers a failed relocation is still added.
+func (f *Filter[T]) Add(item T) error {
+	if f.victim.used {
+		return ErrFull
+	}
+	fp, i1 := f.fingerprintAndIndex(item)
+	i2 := f.altIndex(i1, fp)
+	if f.insert(i1, fp) || f.insert(i2, fp) {
+		f.count++
+		return nil
+	}
+
+	i := i1
+	if f.next()&1 == 1 {
+		i = i2
+	}
+	for n := 0; n < maxKicks; n++ {
+		b := f.bucket(i)
+		j := f.next() % bucketSize
+		fp, b[j] = b[j], fp
+		i = f.altIndex(i, fp)
+		if f.insert(i, fp) {
+			f.count++
+			return nil
+		}
+	}
+	f.victim = victim{index: i, fp: fp, used: true}
+	f.count++
+	return nil
+}
+
+// Contains returns false if the item was certainly not added, and true if it probably was.
+func (f *Filter[T]) Contains(item T) bool {
+	fp, i1 := f.fingerprintAndIndex(item)
+	i2 := f.altIndex(i1, fp)
+	if f.victim.used && f.victim.fp == fp && (f.victim.index == i1 || f.victim.index == i2) {
+		return true
+	}
+	return f.has(i1, fp) || f.has(i2, fp)
+}
+
+// Remove removes one occurrence of the item. It returns false if no matching fingerprint was found.
+// Only remove items that were added: removing another item with the same fingerprint and bucket
+// would make this one disappear.
+func (f *Filter[T]) Remove(item T) bool {
+	fp, i1 := f.fingerprintAndIndex(item)
+	i2 := f.altIndex(i1, fp)
+	switch {
+	case f.delete(i1, fp) || f.delete(i2, fp):
+	case f.victim.used && f.victim.fp == fp && (f.victim.index == i1 || f.victim.index == i2):
+		f.victim = victim{}
+		f.count--
+		return true
+	default:
+		return false
+	}
+	f.count--
+
+	// A slot is free again: give the victim a home.
+	if f.victim.used {
+		v := f.victim
+		f.victim = victim{}
+		if !f.insert(v.index, v.fp) && !f.insert(f.altIndex(v.index, v.fp), v.fp) {
+			f.victim = v
+		}
+	}
+	return true
+}
+
+// Count returns the number of items added and not removed.
+func (f *Filter[T]) Count() int {
+	return f.count
+}
+
+// Capacity returns the number of fingerprint slots.
+func (f *Filter[T]) Capacity() int {
+	return len(f.slots)
+}
+
+// LoadFactor returns the ratio of items to slots.
+func (f *Filter[T]) LoadFactor() float64 {
+	return float64(f.count) / float64(len(f.slots))
+}
+
+// Clear removes all items.
+func (f *Filter[T]) Clear() {
+	for i := range f.slots {
+		f.slots[i] = 0
+	}
+	f.count = 0
+	f.victim = victim{}
+	f.rand = seed
+}
@@ -0,0 +1,109 @@
+package cuckoo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	_, err := New(common.IntHasher, 0)
+	assert.True(t, errors.Is(err, ErrInvalidParameter))
+
+	f, err := New(common.IntHasher, 10000)
+	assert.NoError(t, err)
+	assert.Equal(t, 16384, f.Capacity())
+	for i := 0; i < 10000; i++ {
+		assert.NoError(t, f.Add(i))
+	}
+	for i := 0; i < 10000; i++ {
+		assert.True(t, f.Contains(i))
+	}
+	assert.Equal(t, 10000, f.Count())
+	assert.InDelta(t, 0.61, f.LoadFactor(), 0.01)
+
+	hits := 0
+	for i := -1; i >= -100000; i-- {
+		if f.Contains(i) {
+			hits++
+		}
+	}
+	assert.Less(t, hits, 50)
+
+	for i := 0; i < 10000; i += 2 {
+		assert.True(t, f.Remove(i))
+	}
+	for i := 1; i < 10000; i += 2 {
+		assert.True(t, f.Contains(i))
+	}
+	removed := 0
+	for i := 0; i < 10000; i += 2 {
+		if !f.Contains(i) {
+			removed++
+		}
+	}
+	assert.Greater(t, removed, 4990)
+	assert.Equal(t, 5000, f.Count())
+
+	f.Clear()
+	assert.Equal(t, 0, f.Count())
+	assert.False(t, f.Contains(1))
+	assert.False(t, f.Remove(1))
+}
+
+func TestFull(t *testing.T) {
+	f, _ := New(common.IntHasher, 1000)
+	n := 0
+	for ; f.Add(n) == nil; n++ {
+	}
+	assert.Equal(t, n, f.Count())
+	assert.Greater(t, f.LoadFactor(), 0.9)
+	for i := 0; i < n; i++ {
+		assert.True(t, f.Contains(i))
+	}
+	assert.True(t, errors.Is(f.Add(-1), ErrFull))
+
+	// Removing items makes room for the victim and lets Add succeed again.
+	for i := 0; i < n/2; i++ {
+		assert.True(t, f.Remove(i))
+	}
+	for i := n / 2; i < n; i++ {
+		assert.True(t, f.Contains(i))
+	}
+	assert.NoError(t, f.Add(-1))
+	assert.True(t, f.Contains(-1))
+}
+
+func TestDuplicates(t *testing.T) {
+	f, _ := New(common.StringHasher, 100)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, f.Add("a"))
+	}
+	assert.True(t, f.Remove("a"))
+	assert.True(t, f.Remove("a"))
+	assert.True(t, f.Contains("a"))
+	assert.True(t, f.Remove("a"))
+	assert.False(t, f.Contains("a"))
+	assert.False(t, f.Remove("a"))
+}
+
+func TestMarshalBinary(t *testing.T) {
+	f, _ := New(common.IntHasher, 1000)
+	for i := 0; f.Add(i) == nil; i++ {
+	}
+	data, err := f.MarshalBinary()
+	assert.NoError(t, err)
+
+	decoded, _ := New(common.IntHasher, 1)
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, f.slots, decoded.slots)
+	assert.Equal(t, f.victim, decoded.victim)
+	assert.Equal(t, f.Count(), decoded.Count())
+	assert.True(t, errors.Is(decoded.Add(-1), ErrFull))
+
+	for _, bad := range [][]byte{nil, data[:40], append(data, 0), append([]byte{0}, data[1:]...)} {
+		assert.True(t, errors.Is(decoded.UnmarshalBinary(bad), ErrInvalidFormat))
+	}
+}
@@ -0,0 +1,78 @@
+package cuckoo
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// The serialized format is little-endian:
+//
+//	cookie "CKO1" uint32, buckets uint64, count uint64,
+//	victim used uint32, victim index uint64, victim fingerprint uint32,
+//	buckets × 4 × uint16 fingerprints
+//
+// The hasher is not part of the encoding.
+const cookie uint32 = 0x314f4b43
+
+var ErrInvalidFormat = errors.New("invalid cuckoo filter encoding")
+
+// MarshalBinary encodes the filter.
+func (f *Filter[T]) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, 36+2*len(f.slots))
+	buf = appendUint32(buf, cookie)
+	buf = appendUint64(buf, f.mask+1)
+	buf = appendUint64(buf, uint64(f.count))
+	used := uint32(0)
+	if f.victim.used {
+		used = 1
+	}
+	buf = appendUint32(buf, used)
+	buf = appendUint64(buf, f.victim.index)
+	buf = appendUint32(buf, uint32(f.victim.fp))
+	for _, s := range f.slots {
+		buf = append(buf, byte(s), byte(s>>8))
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary replaces the content of the filter with the decoded data. The hasher is kept.
+func (f *Filter[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < 36 {
+		return fmt.Errorf("%w: unexpected end of data", ErrInvalidFormat)
+	}
+	le := binary.LittleEndian
+	if le.Uint32(data) != cookie {
+		return fmt.Errorf("%w: bad cookie", ErrInvalidFormat)
+	}
+	buckets, count := le.Uint64(data[4:]), le.Uint64(data[12:])
+	used, index, fp := le.Uint32(data[20:]), le.Uint64(data[24:]), le.Uint32(data[32:])
+	data = data[36:]
+
+	if buckets < 2 || buckets&(buckets-1) != 0 || buckets > uint64(len(data)) {
+		return fmt.Errorf("%w: %d buckets", ErrInvalidFormat, buckets)
+	}
+	if uint64(len(data)) != buckets*bucketSize*2 {
+		return fmt.Errorf("%w: %d bytes for %d buckets", ErrInvalidFormat, len(data), buckets)
+	}
+	if count > buckets*bucketSize+1 || used > 1 || index >= buckets || fp > 0xffff {
+		return fmt.Errorf("%w: count=%d victim=%d/%d/%d", ErrInvalidFormat, count, used, index, fp)
+	}
+
+	slots := make([]uint16, buckets*bucketSize)
+	for i := range slots {
+		slots[i] = le.Uint16(data[2*i:])
+	}
+	f.slots, f.mask, f.count = slots, buckets-1, int(count)
+	f.victim = victim{index: index, fp: uint16(fp), used: used == 1}
+	f.rand = seed
+	return nil
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	return appendUint32(appendUint32(buf, uint32(v)), uint32(v>>32))
+}
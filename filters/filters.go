@@ -0,0 +1,26 @@
+// Package filters defines the interface of approximate membership filters.
+//
+// A filter answers whether an item may have been added: false positives are possible at a rate
+// chosen when the filter is created, false negatives are not. Filters store only hash bits, so they
+// take a small fraction of the memory of the items and cannot list them.
+package filters
+
+import "encoding"
+
+// Filter is an approximate set of items of type T.
+//
+// A filter only stores hashes, so UnmarshalBinary must be called on a filter created by its
+// constructor with the hasher used before marshaling.
+type Filter[T any] interface {
+	// Add adds the item. It returns an error if the filter cannot take more items.
+	Add(item T) error
+	// Contains returns false if the item was certainly not added, and true if it probably was.
+	Contains(item T) bool
+	// Count returns the number of items added.
+	Count() int
+	// Clear removes all items.
+	Clear()
+
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
@@ -0,0 +1,247 @@
+// Package adjacencylist implements a graph that stores the outgoing edges of every vertex in a list.
+//
+// Memory is O(V + E) and iterating the neighbors of a vertex is O(deg), which suits sparse graphs.
+// Testing for a single edge scans the list of its source, so HasEdge and Weight are O(deg).
+// Neighbors keep the order in which their edges were added.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Adjacency_list
+package adjacencylist
+
+import "github.com/kwstars/goads/graphs"
+
+var _ graphs.Graph[int, int] = (*Graph[int, int])(nil)
+
+// Graph is an adjacency-list graph.
+type Graph[V comparable, W any] struct {
+	directed bool
+	vertices []V                       // vertices in insertion order
+	index    map[V]int                 // index maps a vertex to its position in vertices.
+	out      map[V][]graphs.Edge[V, W] // out holds the outgoing edges; undirected edges are stored at both ends.
+	in       map[V][]V                 // in holds the predecessors of each vertex in directed graphs.
+	edges    int
+}
+
+// NewDirected returns an empty directed graph.
+func NewDirected[V comparable, W any]() *Graph[V, W] {
+	return newGraph[V, W](true)
+}
+
+// NewUndirected returns an empty undirected graph.
+func NewUndirected[V comparable, W any]() *Graph[V, W] {
+	return newGraph[V, W](false)
+}
+
+func newGraph[V comparable, W any](directed bool) *Graph[V, W] {
+	return &Graph[V, W]{
+		directed: directed,
+		index:    make(map[V]int),
+		out:      make(map[V][]graphs.Edge[V, W]),
+		in:       make(map[V][]V),
+	}
+}
+
+// Directed returns true if edges have a direction.
+func (g *Graph[V, W]) Directed() bool {
+	return g.directed
+}
+
+// AddVertex adds a vertex. It is a no-op if the vertex already exists.
+func (g *Graph[V, W]) AddVertex(v V) {
+	if _, ok := g.index[v]; ok {
+		return
+	}
+	g.index[v] = len(g.vertices)
+	g.vertices = append(g.vertices, v)
+	g.out[v] = nil
+}
+
+// RemoveVertex removes a vertex together with all its incident edges.
+func (g *Graph[V, W]) RemoveVertex(v V) {
+	pos, ok := g.index[v]
+	if !ok {
+		return
+	}
+
+	for _, e := range g.OutEdges(v) {
+		g.RemoveEdge(v, e.To)
+	}
+	if g.directed {
+		for _, u := range append([]V(nil), g.in[v]...) {
+			g.RemoveEdge(u, v)
+		}
+	}
+
+	delete(g.out, v)
+	delete(g.in, v)
+	delete(g.index, v)
+	g.vertices = append(g.vertices[:pos], g.vertices[pos+1:]...)
+	for i := pos; i < len(g.vertices); i++ {
+		g.index[g.vertices[i]] = i
+	}
+}
+
+// HasVertex returns true if the vertex exists.
+func (g *Graph[V, W]) HasVertex(v V) bool {
+	_, ok := g.index[v]
+	return ok
+}
+
+// Vertices returns all vertices in insertion order.
+func (g *Graph[V, W]) Vertices() []V {
+	vertices := make([]V, len(g.vertices))
+	copy(vertices, g.vertices)
+	return vertices
+}
+
+// find returns the position of the edge to `to` in the list of from, or -1.
+func (g *Graph[V, W]) find(from, to V) int {
+	for i, e := range g.out[from] {
+		if e.To == to {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddEdge adds an edge, adding missing endpoints first. An existing edge gets the new weight.
+func (g *Graph[V, W]) AddEdge(from, to V, weight W) {
+	g.AddVertex(from)
+	g.AddVertex(to)
+
+	if i := g.find(from, to); i >= 0 {
+		g.out[from][i].Weight = weight
+		if !g.directed && from != to {
+			g.out[to][g.find(to, from)].Weight = weight
+		}
+		return
+	}
+
+	g.out[from] = append(g.out[from], graphs.Edge[V, W]{From: from, To: to, Weight: weight})
+	if g.directed {
+		g.in[to] = append(g.in[to], from)
+	} else if from != to {
+		g.out[to] = append(g.out[to], graphs.Edge[V, W]{From: to, To: from, Weight: weight})
+	}
+	g.edges++
+}
+
+// RemoveEdge removes an edge. It is a no-op if the edge does not exist.
+func (g *Graph[V, W]) RemoveEdge(from, to V) {
+	i := g.find(from, to)
+	if i < 0 {
+		return
+	}
+
+	g.out[from] = append(g.out[from][:i], g.out[from][i+1:]...)
+	if g.directed {
+		preds := g.in[to]
+		for j, u := range preds {
+			if u == from {
+				g.in[to] = append(preds[:j], preds[j+1:]...)
+				break
+			}
+		}
+	} else if from != to {
+		j := g.find(to, from)
+		g.out[to] = append(g.out[to][:j], g.out[to][j+1:]...)
+	}
+	g.edges--
+}
+
+// HasEdge returns true if there is an edge from one vertex to the other.
+func (g *Graph[V, W]) HasEdge(from, to V) bool {
+	return g.find(from, to) >= 0
+}
+
+// Weight returns the weight of an edge.
+func (g *Graph[V, W]) Weight(from, to V) (W, bool) {
+	if i := g.find(from, to); i >= 0 {
+		return g.out[from][i].Weight, true
+	}
+	var zero W
+	return zero, false
+}
+
+// EdgeCount returns the number of edges.
+func (g *Graph[V, W]) EdgeCount() int {
+	return g.edges
+}
+
+// Edges returns all edges grouped by source vertex in insertion order.
+// Each undirected edge is returned once, from the endpoint that was added first.
+func (g *Graph[V, W]) Edges() []graphs.Edge[V, W] {
+	edges := make([]graphs.Edge[V, W], 0, g.edges)
+	for _, v := range g.vertices {
+		for _, e := range g.out[v] {
+			if g.directed || g.index[e.From] <= g.index[e.To] {
+				edges = append(edges, e)
+			}
+		}
+	}
+	return edges
+}
+
+// Neighbors returns the vertices reachable from v over one edge.
+func (g *Graph[V, W]) Neighbors(v V) []V {
+	neighbors := make([]V, len(g.out[v]))
+	for i, e := range g.out[v] {
+		neighbors[i] = e.To
+	}
+	return neighbors
+}
+
+// OutEdges returns the edges leaving v.
+func (g *Graph[V, W]) OutEdges(v V) []graphs.Edge[V, W] {
+	edges := make([]graphs.Edge[V, W], len(g.out[v]))
+	copy(edges, g.out[v])
+	return edges
+}
+
+// Degree returns the number of edges incident to v; a self-loop counts twice.
+func (g *Graph[V, W]) Degree(v V) int {
+	if g.directed {
+		return g.InDegree(v) + g.OutDegree(v)
+	}
+	degree := len(g.out[v])
+	if g.find(v, v) >= 0 {
+		degree++
+	}
+	return degree
+}
+
+// InDegree returns the number of edges entering v.
+func (g *Graph[V, W]) InDegree(v V) int {
+	if !g.directed {
+		return g.Degree(v)
+	}
+	return len(g.in[v])
+}
+
+// OutDegree returns the number of edges leaving v.
+func (g *Graph[V, W]) OutDegree(v V) int {
+	if !g.directed {
+		return g.Degree(v)
+	}
+	return len(g.out[v])
+}
+
+// Empty returns true if the graph has no vertices.
+func (g *Graph[V, W]) Empty() bool {
+	return len(g.vertices) == 0
+}
+
+// Size returns the number of vertices.
+func (g *Graph[V, W]) Size() int {
+	return len(g.vertices)
+}
+
+// Clear removes all vertices and edges.
+func (g *Graph[V, W]) Clear() {
+	g.vertices = nil
+	g.index = make(map[V]int)
+	g.out = make(map[V][]graphs.Edge[V, W])
+	g.in = make(map[V][]V)
+	g.edges = 0
+}
@@ -0,0 +1,42 @@
+package adjacencylist
+
+import (
+	"testing"
+
+	"github.com/kwstars/goads/graphs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraph_NeighborOrder(t *testing.T) {
+	g := NewDirected[int, struct{}]()
+	g.AddVertex(1)
+	g.AddVertex(2)
+	g.AddVertex(3)
+	g.AddEdge(1, 3, struct{}{})
+	g.AddEdge(1, 2, struct{}{})
+
+	// Neighbors keep the order in which their edges were added.
+	assert.Equal(t, []int{3, 2}, g.Neighbors(1))
+}
+
+func TestGraph_UndirectedEdgesFromFirstEndpoint(t *testing.T) {
+	g := NewUndirected[string, int]()
+	g.AddVertex("x")
+	g.AddEdge("y", "x", 1)
+
+	assert.Equal(t, []graphs.Edge[string, int]{{From: "x", To: "y", Weight: 1}}, g.Edges())
+	assert.Equal(t, []graphs.Edge[string, int]{{From: "y", To: "x", Weight: 1}}, g.OutEdges("y"))
+}
+
+func TestGraph_RemoveVertexWithIncomingEdges(t *testing.T) {
+	g := NewDirected[int, int]()
+	g.AddEdge(1, 2, 0)
+	g.AddEdge(3, 2, 0)
+	g.AddEdge(2, 2, 0)
+
+	g.RemoveVertex(2)
+	assert.Equal(t, 0, g.EdgeCount())
+	assert.Empty(t, g.Neighbors(1))
+	assert.Empty(t, g.Neighbors(3))
+	assert.Equal(t, map[int]int{1: 0, 3: 1}, g.index)
+}
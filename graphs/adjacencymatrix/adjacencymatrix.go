@@ -0,0 +1,275 @@
+// Package adjacencymatrix implements a graph that stores its edges in a V×V matrix.
+//
+// Testing for an edge and reading its weight are O(1), while memory is O(V²) and iterating
+// the neighbors of a vertex is O(V), which suits small or dense graphs.
+// Adding or removing a vertex resizes the matrix in O(V²).
+// Neighbors are returned in vertex insertion order.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Adjacency_matrix
+package adjacencymatrix
+
+import "github.com/kwstars/goads/graphs"
+
+var _ graphs.Graph[int, int] = (*Graph[int, int])(nil)
+
+// cell is an entry of the matrix.
+type cell[W any] struct {
+	weight  W
+	present bool
+}
+
+// Graph is an adjacency-matrix graph.
+type Graph[V comparable, W any] struct {
+	directed bool
+	vertices []V         // vertices in insertion order
+	index    map[V]int   // index maps a vertex to its row and column.
+	matrix   [][]cell[W] // matrix[i][j] is the edge from vertices[i] to vertices[j].
+	edges    int
+}
+
+// NewDirected returns an empty directed graph.
+func NewDirected[V comparable, W any]() *Graph[V, W] {
+	return &Graph[V, W]{directed: true, index: make(map[V]int)}
+}
+
+// NewUndirected returns an empty undirected graph.
+func NewUndirected[V comparable, W any]() *Graph[V, W] {
+	return &Graph[V, W]{index: make(map[V]int)}
+}
+
+// Directed returns true if edges have a direction.
+func (g *Graph[V, W]) Directed() bool {
+	return g.directed
+}
+
+// AddVertex adds a vertex, growing the matrix by one row and one column.
+func (g *Graph[V, W]) AddVertex(v V) {
+	if _, ok := g.index[v]; ok {
+		return
+	}
+	g.index[v] = len(g.vertices)
+	g.vertices = append(g.vertices, v)
+	for i := range g.matrix {
+		g.matrix[i] = append(g.matrix[i], cell[W]{})
+	}
+	g.matrix = append(g.matrix, make([]cell[W], len(g.vertices)))
+}
+
+// RemoveVertex removes a vertex together with its row and column.
+func (g *Graph[V, W]) RemoveVertex(v V) {
+	pos, ok := g.index[v]
+	if !ok {
+		return
+	}
+
+	for _, u := range g.vertices {
+		g.RemoveEdge(v, u)
+		g.RemoveEdge(u, v)
+	}
+
+	g.matrix = append(g.matrix[:pos], g.matrix[pos+1:]...)
+	for i := range g.matrix {
+		g.matrix[i] = append(g.matrix[i][:pos], g.matrix[i][pos+1:]...)
+	}
+	delete(g.index, v)
+	g.vertices = append(g.vertices[:pos], g.vertices[pos+1:]...)
+	for i := pos; i < len(g.vertices); i++ {
+		g.index[g.vertices[i]] = i
+	}
+}
+
+// HasVertex returns true if the vertex exists.
+func (g *Graph[V, W]) HasVertex(v V) bool {
+	_, ok := g.index[v]
+	return ok
+}
+
+// Vertices returns all vertices in insertion order.
+func (g *Graph[V, W]) Vertices() []V {
+	vertices := make([]V, len(g.vertices))
+	copy(vertices, g.vertices)
+	return vertices
+}
+
+// positions returns the row and column of an edge.
+func (g *Graph[V, W]) positions(from, to V) (int, int, bool) {
+	i, ok := g.index[from]
+	if !ok {
+		return 0, 0, false
+	}
+	j, ok := g.index[to]
+	return i, j, ok
+}
+
+// AddEdge adds an edge, adding missing endpoints first. An existing edge gets the new weight.
+func (g *Graph[V, W]) AddEdge(from, to V, weight W) {
+	g.AddVertex(from)
+	g.AddVertex(to)
+	i, j, _ := g.positions(from, to)
+
+	if !g.matrix[i][j].present {
+		g.edges++
+	}
+	g.matrix[i][j] = cell[W]{weight: weight, present: true}
+	if !g.directed {
+		g.matrix[j][i] = g.matrix[i][j]
+	}
+}
+
+// RemoveEdge removes an edge. It is a no-op if the edge does not exist.
+func (g *Graph[V, W]) RemoveEdge(from, to V) {
+	i, j, ok := g.positions(from, to)
+	if !ok || !g.matrix[i][j].present {
+		return
+	}
+	g.matrix[i][j] = cell[W]{}
+	if !g.directed {
+		g.matrix[j][i] = cell[W]{}
+	}
+	g.edges--
+}
+
+// HasEdge returns true if there is an edge from one vertex to the other.
+func (g *Graph[V, W]) HasEdge(from, to V) bool {
+	i, j, ok := g.positions(from, to)
+	return ok && g.matrix[i][j].present
+}
+
+// Weight returns the weight of an edge.
+func (g *Graph[V, W]) Weight(from, to V) (W, bool) {
+	i, j, ok := g.positions(from, to)
+	if !ok || !g.matrix[i][j].present {
+		var zero W
+		return zero, false
+	}
+	return g.matrix[i][j].weight, true
+}
+
+// EdgeCount returns the number of edges.
+func (g *Graph[V, W]) EdgeCount() int {
+	return g.edges
+}
+
+// Edges returns all edges in row-major order.
+// Each undirected edge is returned once, from the endpoint that was added first.
+func (g *Graph[V, W]) Edges() []graphs.Edge[V, W] {
+	edges := make([]graphs.Edge[V, W], 0, g.edges)
+	for i, row := range g.matrix {
+		start := 0
+		if !g.directed {
+			start = i
+		}
+		for j := start; j < len(row); j++ {
+			if row[j].present {
+				edges = append(edges, graphs.Edge[V, W]{From: g.vertices[i], To: g.vertices[j], Weight: row[j].weight})
+			}
+		}
+	}
+	return edges
+}
+
+// Neighbors returns the vertices reachable from v over one edge.
+func (g *Graph[V, W]) Neighbors(v V) []V {
+	i, ok := g.index[v]
+	if !ok {
+		return nil
+	}
+	var neighbors []V
+	for j, c := range g.matrix[i] {
+		if c.present {
+			neighbors = append(neighbors, g.vertices[j])
+		}
+	}
+	return neighbors
+}
+
+// OutEdges returns the edges leaving v.
+func (g *Graph[V, W]) OutEdges(v V) []graphs.Edge[V, W] {
+	i, ok := g.index[v]
+	if !ok {
+		return nil
+	}
+	var edges []graphs.Edge[V, W]
+	for j, c := range g.matrix[i] {
+		if c.present {
+			edges = append(edges, graphs.Edge[V, W]{From: v, To: g.vertices[j], Weight: c.weight})
+		}
+	}
+	return edges
+}
+
+// Degree returns the number of edges incident to v; a self-loop counts twice.
+func (g *Graph[V, W]) Degree(v V) int {
+	if g.directed {
+		return g.InDegree(v) + g.OutDegree(v)
+	}
+	i, ok := g.index[v]
+	if !ok {
+		return 0
+	}
+	degree := g.countRow(i)
+	if g.matrix[i][i].present {
+		degree++
+	}
+	return degree
+}
+
+// InDegree returns the number of edges entering v.
+func (g *Graph[V, W]) InDegree(v V) int {
+	if !g.directed {
+		return g.Degree(v)
+	}
+	j, ok := g.index[v]
+	if !ok {
+		return 0
+	}
+	degree := 0
+	for i := range g.matrix {
+		if g.matrix[i][j].present {
+			degree++
+		}
+	}
+	return degree
+}
+
+// OutDegree returns the number of edges leaving v.
+func (g *Graph[V, W]) OutDegree(v V) int {
+	if !g.directed {
+		return g.Degree(v)
+	}
+	i, ok := g.index[v]
+	if !ok {
+		return 0
+	}
+	return g.countRow(i)
+}
+
+func (g *Graph[V, W]) countRow(i int) int {
+	count := 0
+	for _, c := range g.matrix[i] {
+		if c.present {
+			count++
+		}
+	}
+	return count
+}
+
+// Empty returns true if the graph has no vertices.
+func (g *Graph[V, W]) Empty() bool {
+	return len(g.vertices) == 0
+}
+
+// Size returns the number of vertices.
+func (g *Graph[V, W]) Size() int {
+	return len(g.vertices)
+}
+
+// Clear removes all vertices and edges.
+func (g *Graph[V, W]) Clear() {
+	g.vertices = nil
+	g.index = make(map[V]int)
+	g.matrix = nil
+	g.edges = 0
+}
@@ -0,0 +1,39 @@
+package adjacencymatrix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraph_NeighborOrder(t *testing.T) {
+	g := NewDirected[int, struct{}]()
+	g.AddVertex(1)
+	g.AddVertex(2)
+	g.AddVertex(3)
+	g.AddEdge(1, 3, struct{}{})
+	g.AddEdge(1, 2, struct{}{})
+
+	// Neighbors follow the vertex order of the matrix.
+	assert.Equal(t, []int{2, 3}, g.Neighbors(1))
+	assert.Nil(t, g.Neighbors(4))
+	assert.Nil(t, g.OutEdges(4))
+	assert.Equal(t, 0, g.Degree(4))
+}
+
+func TestGraph_MatrixShape(t *testing.T) {
+	g := NewUndirected[string, float64]()
+	g.AddEdge("a", "b", 0.5)
+	g.AddEdge("b", "c", 1.5)
+	g.RemoveVertex("b")
+	g.AddVertex("d")
+
+	assert.Len(t, g.matrix, 3)
+	for _, row := range g.matrix {
+		assert.Len(t, row, 3)
+		for _, c := range row {
+			assert.False(t, c.present)
+		}
+	}
+	assert.Equal(t, map[string]int{"a": 0, "c": 1, "d": 2}, g.index)
+}
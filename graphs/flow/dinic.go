@@ -0,0 +1,64 @@
+package flow
+
+import (
+	"github.com/kwstars/goads/graphs"
+	"github.com/kwstars/goads/pkg/common"
+)
+
+// Dinic computes a maximum flow from source to sink.
+// edges are directed and weighted by capacity; parallel edges are allowed.
+// It returns ErrSourceIsSink or ErrNegativeCapacity for invalid input.
+//
+// Each phase builds the level graph of shortest residual paths and saturates it with a blocking flow,
+// so there are at most V phases. On unit-capacity networks it runs in O(E·√V).
+func Dinic[V comparable, W common.Number](edges []graphs.Edge[V, W], source, sink V) (*Flow[V, W], error) {
+	n, err := newNetwork(edges, source, sink)
+	if err != nil {
+		return nil, err
+	}
+
+	// No augmenting path can carry more than the capacity leaving the source.
+	var limit W
+	for _, a := range n.adj[n.source] {
+		limit += a.cap
+	}
+
+	next := make([]int, len(n.vertices))
+	for {
+		level, _ := n.levels()
+		if level[n.sink] < 0 {
+			break
+		}
+		for i := range next {
+			next[i] = 0
+		}
+		for n.augment(n.source, limit, level, next) > 0 {
+			// Keep augmenting until the level graph is blocked.
+		}
+	}
+	return n.result(), nil
+}
+
+// augment pushes flow along one path of the level graph from u to the sink and returns the amount.
+// next[v] is the first arc of v not yet known to be blocked, so every arc is skipped at most once per phase.
+// The recursion depth is bounded by the length of the path.
+func (n *network[V, W]) augment(u int, limit W, level, next []int) W {
+	if u == n.sink {
+		return limit
+	}
+	for ; next[u] < len(n.adj[u]); next[u]++ {
+		a := &n.adj[u][next[u]]
+		if a.cap <= 0 || level[a.to] != level[u]+1 {
+			continue
+		}
+		bound := limit
+		if a.cap < bound {
+			bound = a.cap
+		}
+		if d := n.augment(a.to, bound, level, next); d > 0 {
+			n.push(a, d)
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,34 @@
+package flow
+
+import (
+	"github.com/kwstars/goads/graphs"
+	"github.com/kwstars/goads/pkg/common"
+)
+
+// EdmondsKarp computes a maximum flow from source to sink.
+// edges are directed and weighted by capacity; parallel edges are allowed.
+// It returns ErrSourceIsSink or ErrNegativeCapacity for invalid input.
+func EdmondsKarp[V comparable, W common.Number](edges []graphs.Edge[V, W], source, sink V) (*Flow[V, W], error) {
+	n, err := newNetwork(edges, source, sink)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		level, parent := n.levels()
+		if level[n.sink] < 0 {
+			break
+		}
+		// Find the bottleneck of the shortest augmenting path, then push it.
+		bottleneck := n.adj[parent[n.sink][0]][parent[n.sink][1]].cap
+		for v := n.sink; v != n.source; v = parent[v][0] {
+			if c := n.adj[parent[v][0]][parent[v][1]].cap; c < bottleneck {
+				bottleneck = c
+			}
+		}
+		for v := n.sink; v != n.source; v = parent[v][0] {
+			n.push(&n.adj[parent[v][0]][parent[v][1]], bottleneck)
+		}
+	}
+	return n.result(), nil
+}
@@ -0,0 +1,152 @@
+// Package flow implements maximum flow, minimum cut and bipartite matching algorithms.
+//
+// A flow network is given as a list of directed edges whose weights are capacities, so it can come
+// from the Edges method of any directed graph. Capacities can be of any integer or floating-point
+// type; with floating-point capacities rounding errors may cause extra augmentations.
+//
+//   - EdmondsKarp: augments along shortest paths found by breadth-first search, O(V·E²).
+//   - Dinic: augments along blocking flows in a level graph, O(V²·E).
+//   - HopcroftKarp: maximum bipartite matching, O(E·√V).
+//
+// References: https://en.wikipedia.org/wiki/Maximum_flow_problem
+package flow
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kwstars/goads/graphs"
+	"github.com/kwstars/goads/pkg/common"
+)
+
+var (
+	ErrNegativeCapacity = errors.New("negative capacity")
+	ErrSourceIsSink     = errors.New("source and sink are the same vertex")
+)
+
+// Flow is a maximum flow together with the minimum cut it proves optimal.
+type Flow[V comparable, W common.Number] struct {
+	// Value is the total flow from the source to the sink.
+	Value W
+	// Edges holds the flow on each input edge, in input order; the weight is the flow.
+	Edges []graphs.Edge[V, W]
+
+	sourceSide []V
+	cut        []graphs.Edge[V, W]
+}
+
+// MinCut returns the vertices on the source side of a minimum cut and the input edges crossing it.
+// The capacities of the cut edges add up to the flow value.
+func (f *Flow[V, W]) MinCut() ([]V, []graphs.Edge[V, W]) {
+	return f.sourceSide, f.cut
+}
+
+// arc is an edge of the residual network; rev is the index of the opposite arc in adj[to].
+type arc[W common.Number] struct {
+	to  int
+	cap W
+	rev int
+}
+
+// network is the residual network over vertices numbered in order of appearance.
+type network[V comparable, W common.Number] struct {
+	input    []graphs.Edge[V, W]
+	vertices []V
+	ids      map[V]int
+	adj      [][]arc[W]
+	forward  [][2]int // forward[i] locates the arc of input edge i as (vertex, index)
+	source   int
+	sink     int
+}
+
+func newNetwork[V comparable, W common.Number](edges []graphs.Edge[V, W], source, sink V) (*network[V, W], error) {
+	if source == sink {
+		return nil, fmt.Errorf("%w: %v", ErrSourceIsSink, source)
+	}
+	n := &network[V, W]{input: edges, ids: make(map[V]int), forward: make([][2]int, len(edges))}
+	n.source, n.sink = n.id(source), n.id(sink)
+	for i, e := range edges {
+		if e.Weight < 0 {
+			return nil, fmt.Errorf("%w: %v -> %v", ErrNegativeCapacity, e.From, e.To)
+		}
+		u, v := n.id(e.From), n.id(e.To)
+		n.forward[i] = [2]int{u, len(n.adj[u])}
+		n.adj[u] = append(n.adj[u], arc[W]{to: v, cap: e.Weight, rev: len(n.adj[v])})
+		if u == v {
+			// A self-loop never carries flow, but keep rev valid.
+			n.adj[u][len(n.adj[u])-1].rev++
+		}
+		n.adj[v] = append(n.adj[v], arc[W]{to: u, rev: len(n.adj[u]) - 1})
+	}
+	return n, nil
+}
+
+func (n *network[V, W]) id(v V) int {
+	if id, ok := n.ids[v]; ok {
+		return id
+	}
+	id := len(n.vertices)
+	n.ids[v] = id
+	n.vertices = append(n.vertices, v)
+	n.adj = append(n.adj, nil)
+	return id
+}
+
+// push sends d units along an arc and returns them along its opposite.
+func (n *network[V, W]) push(a *arc[W], d W) {
+	a.cap -= d
+	n.adj[a.to][a.rev].cap += d
+}
+
+// levels returns the number of residual arcs on a shortest path from the source to every vertex,
+// or -1 for unreachable vertices, together with the BFS parent arc of every vertex.
+func (n *network[V, W]) levels() ([]int, [][2]int) {
+	level := make([]int, len(n.vertices))
+	parent := make([][2]int, len(n.vertices))
+	for i := range level {
+		level[i] = -1
+	}
+	level[n.source] = 0
+	queue := []int{n.source}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for i, a := range n.adj[u] {
+			if a.cap > 0 && level[a.to] < 0 {
+				level[a.to] = level[u] + 1
+				parent[a.to] = [2]int{u, i}
+				queue = append(queue, a.to)
+			}
+		}
+	}
+	return level, parent
+}
+
+// result reads the flow off the residual network. The vertices still reachable from the source
+// form the source side of a minimum cut.
+func (n *network[V, W]) result() *Flow[V, W] {
+	f := &Flow[V, W]{Edges: make([]graphs.Edge[V, W], len(n.input))}
+	for i, e := range n.input {
+		a := n.adj[n.forward[i][0]][n.forward[i][1]]
+		f.Edges[i] = graphs.Edge[V, W]{From: e.From, To: e.To, Weight: e.Weight - a.cap}
+		if n.ids[e.From] == n.source {
+			f.Value += f.Edges[i].Weight
+		}
+		if n.ids[e.To] == n.source {
+			f.Value -= f.Edges[i].Weight
+		}
+	}
+
+	level, _ := n.levels()
+	for v, l := range level {
+		if l >= 0 {
+			f.sourceSide = append(f.sourceSide, n.vertices[v])
+		}
+	}
+	for _, e := range n.input {
+		if level[n.ids[e.From]] >= 0 && level[n.ids[e.To]] < 0 {
+			f.cut = append(f.cut, e)
+		}
+	}
+	return f
+}
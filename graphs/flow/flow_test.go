@@ -0,0 +1,189 @@
+package flow
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/kwstars/goads/graphs"
+	"github.com/stretchr/testify/assert"
+)
+
+type maxFlowFunc func(edges []graphs.Edge[string, int], source, sink string) (*Flow[string, int], error)
+
+var algorithms = map[string]maxFlowFunc{
+	"edmonds-karp": EdmondsKarp[string, int],
+	"dinic":        Dinic[string, int],
+}
+
+// clrs is the flow network of CLRS figure 26.1, whose maximum flow is 23.
+var clrs = []graphs.Edge[string, int]{
+	{From: "s", To: "v1", Weight: 16}, {From: "s", To: "v2", Weight: 13},
+	{From: "v1", To: "v3", Weight: 12}, {From: "v2", To: "v1", Weight: 4},
+	{From: "v2", To: "v4", Weight: 14}, {From: "v3", To: "v2", Weight: 9},
+	{From: "v3", To: "t", Weight: 20}, {From: "v4", To: "v3", Weight: 7},
+	{From: "v4", To: "t", Weight: 4},
+}
+
+func TestMaxFlow(t *testing.T) {
+	for name, maxFlow := range algorithms {
+		t.Run(name, func(t *testing.T) {
+			f, err := maxFlow(clrs, "s", "t")
+			assert.NoError(t, err)
+			assert.Equal(t, 23, f.Value)
+			assertValid(t, clrs, "s", "t", f)
+
+			side, cut := f.MinCut()
+			assert.ElementsMatch(t, []string{"s", "v1", "v2", "v4"}, side)
+			assert.ElementsMatch(t, []graphs.Edge[string, int]{
+				{From: "v1", To: "v3", Weight: 12}, {From: "v4", To: "v3", Weight: 7}, {From: "v4", To: "t", Weight: 4},
+			}, cut)
+
+			// No path at all.
+			f, err = maxFlow(clrs, "t", "s")
+			assert.NoError(t, err)
+			assert.Equal(t, 0, f.Value)
+
+			// A sink outside the network.
+			f, err = maxFlow(clrs, "s", "nowhere")
+			assert.NoError(t, err)
+			assert.Equal(t, 0, f.Value)
+			side, cut = f.MinCut()
+			assert.Len(t, side, 6)
+			assert.Empty(t, cut)
+
+			_, err = maxFlow(clrs, "s", "s")
+			assert.True(t, errors.Is(err, ErrSourceIsSink))
+			_, err = maxFlow([]graphs.Edge[string, int]{{From: "s", To: "t", Weight: -1}}, "s", "t")
+			assert.True(t, errors.Is(err, ErrNegativeCapacity))
+		})
+	}
+}
+
+func TestMaxFlow_ParallelAndSelfLoops(t *testing.T) {
+	edges := []graphs.Edge[string, int]{
+		{From: "s", To: "a", Weight: 3}, {From: "s", To: "a", Weight: 2},
+		{From: "a", To: "a", Weight: 10}, {From: "a", To: "s", Weight: 4},
+		{From: "a", To: "t", Weight: 4}, {From: "t", To: "s", Weight: 9},
+	}
+	for name, maxFlow := range algorithms {
+		t.Run(name, func(t *testing.T) {
+			f, err := maxFlow(edges, "s", "t")
+			assert.NoError(t, err)
+			assert.Equal(t, 4, f.Value)
+			assertValid(t, edges, "s", "t", f)
+			assert.Equal(t, 0, f.Edges[2].Weight)
+		})
+	}
+}
+
+func TestMaxFlow_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	names := []string{"s", "t", "a", "b", "c", "d", "e", "f", "g", "h"}
+	for round := 0; round < 100; round++ {
+		var edges []graphs.Edge[string, int]
+		for i := 0; i < 25; i++ {
+			edges = append(edges, graphs.Edge[string, int]{
+				From: names[r.Intn(len(names))], To: names[r.Intn(len(names))], Weight: r.Intn(10),
+			})
+		}
+		ek, err := EdmondsKarp(edges, "s", "t")
+		assert.NoError(t, err)
+		dinic, err := Dinic(edges, "s", "t")
+		assert.NoError(t, err)
+		assert.Equal(t, ek.Value, dinic.Value)
+		assertValid(t, edges, "s", "t", ek)
+		assertValid(t, edges, "s", "t", dinic)
+	}
+}
+
+func TestMaxFlow_Float(t *testing.T) {
+	edges := []graphs.Edge[int, float64]{{From: 0, To: 1, Weight: 1.5}, {From: 1, To: 2, Weight: 0.25}, {From: 0, To: 2, Weight: 1}}
+	f, err := Dinic(edges, 0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.25, f.Value)
+}
+
+func TestHopcroftKarp(t *testing.T) {
+	// Workers and the jobs they can do; a perfect matching exists.
+	jobs := map[string][]string{
+		"ann":  {"cook", "drive"},
+		"bob":  {"cook"},
+		"carl": {"drive", "paint", "sing"},
+		"dora": {"paint"},
+	}
+	left := []string{"ann", "bob", "carl", "dora"}
+	neighbors := func(v string) []string { return jobs[v] }
+	m := HopcroftKarp(left, neighbors)
+	assert.Equal(t, map[string]string{"ann": "drive", "bob": "cook", "carl": "sing", "dora": "paint"}, m)
+
+	// The same value may be on both sides.
+	m2 := HopcroftKarp([]int{1, 2}, func(v int) []int { return []int{1} })
+	assert.Len(t, m2, 1)
+
+	assert.Empty(t, HopcroftKarp(nil, neighbors))
+}
+
+func TestHopcroftKarp_AgreesWithMaxFlow(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for round := 0; round < 50; round++ {
+		adj := make(map[int][]int)
+		var left []int
+		edges := []graphs.Edge[int, int]{}
+		for u := 0; u < 15; u++ {
+			left = append(left, u)
+			edges = append(edges, graphs.Edge[int, int]{From: -1, To: u, Weight: 1})
+			for i := r.Intn(4); i > 0; i-- {
+				v := 100 + r.Intn(15)
+				adj[u] = append(adj[u], v)
+				edges = append(edges, graphs.Edge[int, int]{From: u, To: v, Weight: 1})
+			}
+		}
+		for v := 100; v < 115; v++ {
+			edges = append(edges, graphs.Edge[int, int]{From: v, To: -2, Weight: 1})
+		}
+
+		m := HopcroftKarp(left, func(u int) []int { return adj[u] })
+		f, err := Dinic(edges, -1, -2)
+		assert.NoError(t, err)
+		assert.Equal(t, f.Value, len(m))
+
+		used := make(map[int]bool)
+		for u, v := range m {
+			assert.Contains(t, adj[u], v)
+			assert.False(t, used[v])
+			used[v] = true
+		}
+	}
+}
+
+// assertValid checks capacity and conservation constraints and that the minimum cut matches the value.
+func assertValid(t *testing.T, edges []graphs.Edge[string, int], source, sink string, f *Flow[string, int]) {
+	excess := make(map[string]int)
+	assert.Len(t, f.Edges, len(edges))
+	for i, e := range f.Edges {
+		assert.Equal(t, edges[i].From, e.From)
+		assert.Equal(t, edges[i].To, e.To)
+		assert.GreaterOrEqual(t, e.Weight, 0)
+		assert.LessOrEqual(t, e.Weight, edges[i].Weight)
+		excess[e.From] -= e.Weight
+		excess[e.To] += e.Weight
+	}
+	for v, x := range excess {
+		switch v {
+		case source:
+			assert.Equal(t, -f.Value, x)
+		case sink:
+			assert.Equal(t, f.Value, x)
+		default:
+			assert.Equal(t, 0, x, "flow not conserved at %v", v)
+		}
+	}
+
+	_, cut := f.MinCut()
+	capacity := 0
+	for _, e := range cut {
+		capacity += e.Weight
+	}
+	assert.Equal(t, f.Value, capacity)
+}
@@ -0,0 +1,109 @@
+package flow
+
+import "github.com/kwstars/goads/graphs"
+
+// HopcroftKarp returns a maximum matching of a bipartite graph as a map from left to right vertices.
+// neighbors must return the right vertices adjacent to a left vertex. Left and right vertices are
+// kept apart, so the same value may appear on both sides.
+//
+// Each phase finds a maximal set of vertex-disjoint shortest augmenting paths with one
+// breadth-first and one depth-first search, and there are O(√V) phases.
+func HopcroftKarp[V comparable](left []V, neighbors graphs.NeighborFunc[V]) map[V]V {
+	// Number left vertices by position and right vertices in order of appearance.
+	var right []V
+	rightIDs := make(map[V]int)
+	adj := make([][]int, len(left))
+	for u, l := range left {
+		for _, r := range neighbors(l) {
+			id, ok := rightIDs[r]
+			if !ok {
+				id = len(right)
+				rightIDs[r] = id
+				right = append(right, r)
+			}
+			adj[u] = append(adj[u], id)
+		}
+	}
+
+	m := &matcher{
+		adj:       adj,
+		pairLeft:  make([]int, len(left)),
+		pairRight: make([]int, len(right)),
+		dist:      make([]int, len(left)),
+	}
+	for i := range m.pairLeft {
+		m.pairLeft[i] = -1
+	}
+	for i := range m.pairRight {
+		m.pairRight[i] = -1
+	}
+	for m.layer() {
+		for u := range left {
+			if m.pairLeft[u] < 0 {
+				m.augment(u)
+			}
+		}
+	}
+
+	matching := make(map[V]V)
+	for u, r := range m.pairLeft {
+		if r >= 0 {
+			matching[left[u]] = right[r]
+		}
+	}
+	return matching
+}
+
+// unlayered marks a left vertex that is not on any shortest augmenting path in the current phase.
+const unlayered = -1
+
+type matcher struct {
+	adj       [][]int
+	pairLeft  []int // pairLeft[u] is the right vertex matched to u, or -1
+	pairRight []int // pairRight[r] is the left vertex matched to r, or -1
+	dist      []int // dist[u] is the BFS layer of left vertex u
+}
+
+// layer assigns BFS layers to left vertices starting from the free ones, alternating between
+// unmatched and matched edges. It returns true if a free right vertex is reachable.
+func (m *matcher) layer() bool {
+	var queue []int
+	for u, r := range m.pairLeft {
+		if r < 0 {
+			m.dist[u] = 0
+			queue = append(queue, u)
+		} else {
+			m.dist[u] = unlayered
+		}
+	}
+	found := false
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, r := range m.adj[u] {
+			w := m.pairRight[r]
+			if w < 0 {
+				found = true
+			} else if m.dist[w] == unlayered {
+				m.dist[w] = m.dist[u] + 1
+				queue = append(queue, w)
+			}
+		}
+	}
+	return found
+}
+
+// augment looks for an augmenting path from u along the layers and flips it if found.
+// Vertices that lead nowhere are unlayered so later searches in the phase skip them.
+func (m *matcher) augment(u int) bool {
+	for _, r := range m.adj[u] {
+		w := m.pairRight[r]
+		if w < 0 || (m.dist[w] == m.dist[u]+1 && m.augment(w)) {
+			m.pairLeft[u] = r
+			m.pairRight[r] = u
+			return true
+		}
+	}
+	m.dist[u] = unlayered
+	return false
+}
@@ -0,0 +1,66 @@
+package graphs
+
+import "github.com/kwstars/goads/containers"
+
+// Edge is an edge from one vertex to another, carrying a weight.
+// In an undirected graph From and To are interchangeable.
+type Edge[V comparable, W any] struct {
+	From   V
+	To     V
+	Weight W
+}
+
+// Graph is a directed or undirected graph with weighted edges.
+// Unweighted graphs can use struct{} as weight type.
+// At most one edge connects an ordered pair of vertices (an unordered pair in undirected graphs).
+type Graph[V comparable, W any] interface {
+	containers.Container[V]
+
+	// Directed returns true if edges have a direction.
+	Directed() bool
+
+	// AddVertex adds a vertex. It is a no-op if the vertex already exists.
+	AddVertex(v V)
+	// RemoveVertex removes a vertex together with all its incident edges.
+	RemoveVertex(v V)
+	// HasVertex returns true if the vertex exists.
+	HasVertex(v V) bool
+	// Vertices returns all vertices in insertion order.
+	Vertices() []V
+
+	// AddEdge adds an edge, adding missing endpoints first. An existing edge gets the new weight.
+	AddEdge(from, to V, weight W)
+	// RemoveEdge removes an edge. It is a no-op if the edge does not exist.
+	RemoveEdge(from, to V)
+	// HasEdge returns true if there is an edge from one vertex to the other.
+	HasEdge(from, to V) bool
+	// Weight returns the weight of an edge.
+	Weight(from, to V) (W, bool)
+	// EdgeCount returns the number of edges.
+	EdgeCount() int
+	// Edges returns all edges. Each undirected edge is returned once.
+	Edges() []Edge[V, W]
+
+	// Neighbors returns the vertices reachable from v over one edge.
+	Neighbors(v V) []V
+	// OutEdges returns the edges leaving v. In undirected graphs From is always v.
+	OutEdges(v V) []Edge[V, W]
+
+	// Degree returns the number of edges incident to v; a self-loop counts twice.
+	// In directed graphs it is the sum of InDegree and OutDegree.
+	Degree(v V) int
+	// InDegree returns the number of edges entering v.
+	InDegree(v V) int
+	// OutDegree returns the number of edges leaving v.
+	OutDegree(v V) int
+}
+
+// EdgeFunc returns the edges leaving a vertex.
+// It is the minimal view of a weighted graph the algorithm packages depend on, so they work with
+// any representation: pass the OutEdges method of a Graph, or a closure over an implicit graph.
+type EdgeFunc[V comparable, W any] func(v V) []Edge[V, W]
+
+// NeighborFunc returns the vertices reachable from a vertex over one edge.
+// It is the unweighted counterpart of EdgeFunc: pass the Neighbors method of a Graph,
+// or a closure over an implicit graph.
+type NeighborFunc[V comparable] func(v V) []V
@@ -0,0 +1,138 @@
+package graphs_test
+
+import (
+	"testing"
+
+	"github.com/kwstars/goads/graphs"
+	"github.com/kwstars/goads/graphs/adjacencylist"
+	"github.com/kwstars/goads/graphs/adjacencymatrix"
+	"github.com/stretchr/testify/assert"
+)
+
+type constructor func(directed bool) graphs.Graph[string, int]
+
+// implementations returns every graph representation, so that each behavior is checked against all of them.
+func implementations() map[string]constructor {
+	return map[string]constructor{
+		"adjacencylist": func(directed bool) graphs.Graph[string, int] {
+			if directed {
+				return adjacencylist.NewDirected[string, int]()
+			}
+			return adjacencylist.NewUndirected[string, int]()
+		},
+		"adjacencymatrix": func(directed bool) graphs.Graph[string, int] {
+			if directed {
+				return adjacencymatrix.NewDirected[string, int]()
+			}
+			return adjacencymatrix.NewUndirected[string, int]()
+		},
+	}
+}
+
+func TestGraph_Directed(t *testing.T) {
+	for name, newGraph := range implementations() {
+		t.Run(name, func(t *testing.T) {
+			g := newGraph(true)
+			assert.True(t, g.Directed())
+			assert.True(t, g.Empty())
+
+			g.AddEdge("a", "b", 1)
+			g.AddEdge("a", "c", 2)
+			g.AddEdge("c", "a", 3)
+			g.AddEdge("c", "c", 4)
+			g.AddVertex("d")
+			g.AddVertex("a")
+
+			assert.Equal(t, []string{"a", "b", "c", "d"}, g.Vertices())
+			assert.Equal(t, 4, g.Size())
+			assert.Equal(t, 4, g.EdgeCount())
+			assert.True(t, g.HasEdge("a", "b"))
+			assert.False(t, g.HasEdge("b", "a"))
+			assert.False(t, g.HasEdge("x", "a"))
+
+			w, ok := g.Weight("c", "a")
+			assert.True(t, ok)
+			assert.Equal(t, 3, w)
+			g.AddEdge("c", "a", 30)
+			w, _ = g.Weight("c", "a")
+			assert.Equal(t, 30, w)
+			assert.Equal(t, 4, g.EdgeCount())
+
+			assert.ElementsMatch(t, []string{"b", "c"}, g.Neighbors("a"))
+			assert.ElementsMatch(t, []graphs.Edge[string, int]{{From: "c", To: "a", Weight: 30}, {From: "c", To: "c", Weight: 4}}, g.OutEdges("c"))
+			assert.Equal(t, 2, g.OutDegree("a"))
+			assert.Equal(t, 1, g.InDegree("a"))
+			assert.Equal(t, 3, g.Degree("a"))
+			assert.Equal(t, 4, g.Degree("c"))
+			assert.Equal(t, 0, g.Degree("d"))
+			assert.ElementsMatch(t, []graphs.Edge[string, int]{
+				{From: "a", To: "b", Weight: 1},
+				{From: "a", To: "c", Weight: 2},
+				{From: "c", To: "a", Weight: 30},
+				{From: "c", To: "c", Weight: 4},
+			}, g.Edges())
+
+			g.RemoveEdge("a", "b")
+			g.RemoveEdge("b", "a")
+			assert.False(t, g.HasEdge("a", "b"))
+			assert.Equal(t, 3, g.EdgeCount())
+
+			g.RemoveVertex("c")
+			g.RemoveVertex("x")
+			assert.Equal(t, []string{"a", "b", "d"}, g.Vertices())
+			assert.Equal(t, 0, g.EdgeCount())
+			assert.Empty(t, g.Neighbors("a"))
+			assert.Equal(t, 0, g.InDegree("a"))
+
+			g.AddEdge("d", "a", 5)
+			assert.Equal(t, []string{"a"}, g.Neighbors("d"))
+
+			g.Clear()
+			assert.True(t, g.Empty())
+			assert.Equal(t, 0, g.EdgeCount())
+			assert.False(t, g.HasVertex("a"))
+		})
+	}
+}
+
+func TestGraph_Undirected(t *testing.T) {
+	for name, newGraph := range implementations() {
+		t.Run(name, func(t *testing.T) {
+			g := newGraph(false)
+			assert.False(t, g.Directed())
+
+			g.AddEdge("a", "b", 1)
+			g.AddEdge("c", "a", 2)
+			g.AddEdge("b", "b", 3)
+
+			assert.Equal(t, 3, g.EdgeCount())
+			assert.True(t, g.HasEdge("b", "a"))
+			w, ok := g.Weight("a", "c")
+			assert.True(t, ok)
+			assert.Equal(t, 2, w)
+
+			g.AddEdge("b", "a", 10)
+			w, _ = g.Weight("a", "b")
+			assert.Equal(t, 10, w)
+			assert.Equal(t, 3, g.EdgeCount())
+
+			assert.ElementsMatch(t, []string{"b", "c"}, g.Neighbors("a"))
+			assert.ElementsMatch(t, []graphs.Edge[string, int]{{From: "a", To: "b", Weight: 10}, {From: "a", To: "c", Weight: 2}}, g.OutEdges("a"))
+			assert.Equal(t, 2, g.Degree("a"))
+			assert.Equal(t, 3, g.Degree("b"), "self-loop counts twice")
+			assert.Equal(t, g.Degree("b"), g.InDegree("b"))
+			assert.Equal(t, g.Degree("b"), g.OutDegree("b"))
+			assert.Len(t, g.Edges(), 3)
+
+			g.RemoveEdge("b", "a")
+			assert.False(t, g.HasEdge("a", "b"))
+			assert.Equal(t, 2, g.EdgeCount())
+
+			g.RemoveVertex("a")
+			assert.Equal(t, []string{"b", "c"}, g.Vertices())
+			assert.Equal(t, 1, g.EdgeCount())
+			assert.Equal(t, 0, g.Degree("c"))
+			assert.Equal(t, []graphs.Edge[string, int]{{From: "b", To: "b", Weight: 3}}, g.Edges())
+		})
+	}
+}
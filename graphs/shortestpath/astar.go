@@ -0,0 +1,44 @@
+package shortestpath
+
+import (
+	"fmt"
+
+	"github.com/kwstars/goads/graphs"
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/queues/priorityqueue"
+)
+
+// AStar finds a shortest path from source to target.
+// heuristic estimates the remaining distance from a vertex to target; the path is optimal as long as
+// the heuristic never overestimates. A heuristic that always returns 0 turns AStar into Dijkstra.
+// It returns false if target is unreachable, and ErrNegativeWeight on a negative edge.
+func AStar[V comparable, W common.Number](edges graphs.EdgeFunc[V, W], source, target V, heuristic func(v V) W) (Path[V, W], bool, error) {
+	g := map[V]W{source: 0} // g is the best known distance from source.
+	prev := make(map[V]V)
+	pq := priorityqueue.New(minFirst[V, W])
+	pq.Enqueue(item[V, W]{vertex: source, priority: heuristic(source)})
+
+	for !pq.Empty() {
+		it, _ := pq.Dequeue()
+		u := it.vertex
+		if it.priority > g[u]+heuristic(u) {
+			continue // stale entry
+		}
+		if u == target {
+			return Path[V, W]{Vertices: walkBack(prev, source, target), Distance: g[u]}, true, nil
+		}
+
+		for _, e := range edges(u) {
+			if e.Weight < 0 {
+				return Path[V, W]{}, false, fmt.Errorf("%w: %v -> %v", ErrNegativeWeight, e.From, e.To)
+			}
+			d := g[u] + e.Weight
+			if old, ok := g[e.To]; !ok || d < old {
+				g[e.To] = d
+				prev[e.To] = u
+				pq.Enqueue(item[V, W]{vertex: e.To, priority: d + heuristic(e.To)})
+			}
+		}
+	}
+	return Path[V, W]{}, false, nil
+}
@@ -0,0 +1,56 @@
+package shortestpath
+
+import (
+	"github.com/kwstars/goads/graphs"
+	"github.com/kwstars/goads/pkg/common"
+)
+
+// BellmanFord computes shortest paths from source, allowing negative weights.
+// vertices must contain every vertex reachable from source.
+// If a negative cycle is reachable from source, it returns a *NegativeCycleError holding the cycle.
+func BellmanFord[V comparable, W common.Number](vertices []V, edges graphs.EdgeFunc[V, W], source V) (*Tree[V, W], error) {
+	t := newTree[V, W](source)
+
+	// relax runs one pass over all edges and returns the last vertex whose distance improved.
+	relax := func() (V, bool) {
+		var last V
+		changed := false
+		for _, u := range vertices {
+			du, ok := t.dist[u]
+			if !ok {
+				continue
+			}
+			for _, e := range edges(u) {
+				if old, ok := t.dist[e.To]; !ok || du+e.Weight < old {
+					t.dist[e.To] = du + e.Weight
+					t.prev[e.To] = u
+					last, changed = e.To, true
+				}
+			}
+		}
+		return last, changed
+	}
+
+	for i := 1; i < len(vertices); i++ {
+		if _, changed := relax(); !changed {
+			return t, nil
+		}
+	}
+
+	v, changed := relax()
+	if !changed {
+		return t, nil
+	}
+
+	// v was improved in the V-th pass, so following predecessors V times surely ends inside the cycle.
+	for i := 0; i < len(vertices); i++ {
+		v = t.prev[v]
+	}
+	cycle := []V{v}
+	for u := t.prev[v]; u != v; u = t.prev[u] {
+		cycle = append(cycle, u)
+	}
+	cycle = append(cycle, v)
+	reverse(cycle)
+	return nil, &NegativeCycleError[V]{Cycle: cycle}
+}
@@ -0,0 +1,105 @@
+package shortestpath
+
+import (
+	"fmt"
+
+	"github.com/kwstars/goads/graphs"
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/queues/priorityqueue"
+)
+
+// search is one direction of a bidirectional search.
+type search[V comparable, W common.Number] struct {
+	edges graphs.EdgeFunc[V, W]
+	dist  map[V]W
+	prev  map[V]V
+	done  map[V]bool
+	pq    *priorityqueue.Queue[item[V, W]]
+}
+
+func newSearch[V comparable, W common.Number](edges graphs.EdgeFunc[V, W], start V) *search[V, W] {
+	s := &search[V, W]{
+		edges: edges,
+		dist:  map[V]W{start: 0},
+		prev:  make(map[V]V),
+		done:  make(map[V]bool),
+		pq:    priorityqueue.New(minFirst[V, W]),
+	}
+	s.pq.Enqueue(item[V, W]{vertex: start})
+	return s
+}
+
+// top returns the smallest tentative distance in the queue, dropping stale entries.
+func (s *search[V, W]) top() (W, bool) {
+	for !s.pq.Empty() {
+		it, _ := s.pq.Peek()
+		if !s.done[it.vertex] {
+			return it.priority, true
+		}
+		_, _ = s.pq.Dequeue()
+	}
+	return 0, false
+}
+
+// BidirectionalDijkstra finds a shortest path from source to target by running Dijkstra forward
+// from source over forward and backward from target over backward, which must return the edges
+// entering a vertex with From and To swapped. For undirected graphs pass the same function twice.
+// It returns false if target is unreachable, and ErrNegativeWeight on a negative edge.
+func BidirectionalDijkstra[V comparable, W common.Number](forward, backward graphs.EdgeFunc[V, W], source, target V) (Path[V, W], bool, error) {
+	if source == target {
+		return Path[V, W]{Vertices: []V{source}}, true, nil
+	}
+
+	fw, bw := newSearch(forward, source), newSearch(backward, target)
+	var best W
+	var meet V
+	found := false
+
+	for {
+		topF, okF := fw.top()
+		topB, okB := bw.top()
+		if !okF || !okB {
+			break
+		}
+		// No undiscovered path can be shorter than the two frontiers combined.
+		if found && topF+topB >= best {
+			break
+		}
+
+		// Expand the direction with the smaller frontier.
+		s, other := fw, bw
+		if topB < topF {
+			s, other = bw, fw
+		}
+		it, _ := s.pq.Dequeue()
+		u := it.vertex
+		s.done[u] = true
+
+		for _, e := range s.edges(u) {
+			if e.Weight < 0 {
+				return Path[V, W]{}, false, fmt.Errorf("%w: %v -> %v", ErrNegativeWeight, e.From, e.To)
+			}
+			d := s.dist[u] + e.Weight
+			if old, ok := s.dist[e.To]; !ok || d < old {
+				s.dist[e.To] = d
+				s.prev[e.To] = u
+				s.pq.Enqueue(item[V, W]{vertex: e.To, priority: d})
+			}
+			if od, ok := other.dist[e.To]; ok {
+				if total := s.dist[e.To] + od; !found || total < best {
+					best, meet, found = total, e.To, true
+				}
+			}
+		}
+	}
+
+	if !found {
+		return Path[V, W]{}, false, nil
+	}
+	path := walkBack(fw.prev, source, meet)
+	tail := walkBack(bw.prev, target, meet) // target ... meet
+	for i := len(tail) - 2; i >= 0; i-- {
+		path = append(path, tail[i])
+	}
+	return Path[V, W]{Vertices: path, Distance: best}, true, nil
+}
@@ -0,0 +1,43 @@
+package shortestpath
+
+import (
+	"fmt"
+
+	"github.com/kwstars/goads/graphs"
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/queues/priorityqueue"
+)
+
+// Dijkstra computes shortest paths from source to every reachable vertex.
+// All weights must be non-negative; a negative edge makes it return ErrNegativeWeight.
+//
+// The priority queue has no decrease-key, so an improved vertex is queued again and
+// outdated entries are discarded when they reach the front (lazy deletion).
+func Dijkstra[V comparable, W common.Number](edges graphs.EdgeFunc[V, W], source V) (*Tree[V, W], error) {
+	t := newTree[V, W](source)
+	done := make(map[V]bool)
+	pq := priorityqueue.New(minFirst[V, W])
+	pq.Enqueue(item[V, W]{vertex: source})
+
+	for !pq.Empty() {
+		it, _ := pq.Dequeue()
+		u := it.vertex
+		if done[u] {
+			continue
+		}
+		done[u] = true
+
+		for _, e := range edges(u) {
+			if e.Weight < 0 {
+				return nil, fmt.Errorf("%w: %v -> %v", ErrNegativeWeight, e.From, e.To)
+			}
+			d := t.dist[u] + e.Weight
+			if old, ok := t.dist[e.To]; !ok || d < old {
+				t.dist[e.To] = d
+				t.prev[e.To] = u
+				pq.Enqueue(item[V, W]{vertex: e.To, priority: d})
+			}
+		}
+	}
+	return t, nil
+}
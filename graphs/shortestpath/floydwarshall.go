@@ -0,0 +1,100 @@
+package shortestpath
+
+import (
+	"fmt"
+
+	"github.com/kwstars/goads/graphs"
+	"github.com/kwstars/goads/pkg/common"
+)
+
+// AllPairs holds the shortest distances between every ordered pair of vertices.
+type AllPairs[V comparable, W common.Number] struct {
+	index    map[V]int
+	vertices []V
+	dist     [][]W
+	next     [][]int // next[i][j] is the vertex after i on a shortest path to j, or -1 if j is unreachable.
+}
+
+// Distance returns the length of a shortest path from u to v.
+// It returns false if v is unreachable from u or either vertex is unknown.
+func (a *AllPairs[V, W]) Distance(u, v V) (W, bool) {
+	i, ok := a.index[u]
+	j, ok2 := a.index[v]
+	if !ok || !ok2 || a.next[i][j] < 0 {
+		return 0, false
+	}
+	return a.dist[i][j], true
+}
+
+// Path returns a shortest path from u to v.
+// It returns false if v is unreachable from u or either vertex is unknown.
+func (a *AllPairs[V, W]) Path(u, v V) (Path[V, W], bool) {
+	d, ok := a.Distance(u, v)
+	if !ok {
+		return Path[V, W]{}, false
+	}
+	i, j := a.index[u], a.index[v]
+	path := []V{u}
+	for i != j {
+		i = a.next[i][j]
+		path = append(path, a.vertices[i])
+	}
+	return Path[V, W]{Vertices: path, Distance: d}, true
+}
+
+// FloydWarshall computes the shortest paths between all pairs of vertices.
+// edges must only lead to vertices listed in vertices.
+// It returns ErrNegativeCycle if the graph contains a negative cycle; use BellmanFord to extract the cycle.
+func FloydWarshall[V comparable, W common.Number](vertices []V, edges graphs.EdgeFunc[V, W]) (*AllPairs[V, W], error) {
+	n := len(vertices)
+	a := &AllPairs[V, W]{
+		index:    make(map[V]int, n),
+		vertices: vertices,
+		dist:     make([][]W, n),
+		next:     make([][]int, n),
+	}
+	for i, v := range vertices {
+		a.index[v] = i
+	}
+	for i := range vertices {
+		a.dist[i] = make([]W, n)
+		a.next[i] = make([]int, n)
+		for j := range a.next[i] {
+			a.next[i][j] = -1
+		}
+		a.next[i][i] = i
+	}
+	for i, u := range vertices {
+		for _, e := range edges(u) {
+			j := a.index[e.To]
+			if a.next[i][j] < 0 || e.Weight < a.dist[i][j] {
+				a.dist[i][j] = e.Weight
+				a.next[i][j] = j
+			}
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			if a.next[i][k] < 0 {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				if a.next[k][j] < 0 {
+					continue
+				}
+				if d := a.dist[i][k] + a.dist[k][j]; a.next[i][j] < 0 || d < a.dist[i][j] {
+					a.dist[i][j] = d
+					a.next[i][j] = a.next[i][k]
+				}
+			}
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		if a.dist[i][i] < 0 {
+			return nil, fmt.Errorf("%w through %v", ErrNegativeCycle, vertices[i])
+		}
+	}
+	return a, nil
+}
@@ -0,0 +1,124 @@
+// Package shortestpath implements single-source, single-pair and all-pairs shortest path algorithms.
+//
+// The algorithms only need a graphs.EdgeFunc (and the vertex list where every vertex must be visited),
+// so they run on any graph representation. Weights can be of any integer or floating-point type.
+//
+//   - Dijkstra: non-negative weights, O((V + E) log V) with a binary-heap priority queue.
+//   - BellmanFord: arbitrary weights, O(V·E), reports a reachable negative cycle.
+//   - AStar: single pair, Dijkstra guided by a heuristic that never overestimates.
+//   - BidirectionalDijkstra: single pair, searches from both ends and meets in the middle.
+//   - FloydWarshall: all pairs, O(V³).
+//
+// References: https://en.wikipedia.org/wiki/Shortest_path_problem
+package shortestpath
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kwstars/goads/pkg/common"
+)
+
+var (
+	ErrNegativeWeight = errors.New("negative edge weight")
+	ErrNegativeCycle  = errors.New("negative cycle")
+)
+
+// NegativeCycleError reports a negative cycle. It matches ErrNegativeCycle with errors.Is.
+type NegativeCycleError[V comparable] struct {
+	// Cycle lists the vertices of the cycle in edge order; the first vertex is repeated at the end.
+	Cycle []V
+}
+
+func (e *NegativeCycleError[V]) Error() string {
+	return fmt.Sprintf("%v: %v", ErrNegativeCycle, e.Cycle)
+}
+
+func (e *NegativeCycleError[V]) Unwrap() error {
+	return ErrNegativeCycle
+}
+
+// Path is a path between two vertices together with its total weight.
+type Path[V comparable, W common.Number] struct {
+	Vertices []V
+	Distance W
+}
+
+// Tree is a shortest-path tree: the distance from a source to every reachable vertex
+// and the predecessor of each vertex on a shortest path.
+type Tree[V comparable, W common.Number] struct {
+	source V
+	dist   map[V]W
+	prev   map[V]V
+}
+
+func newTree[V comparable, W common.Number](source V) *Tree[V, W] {
+	t := &Tree[V, W]{source: source, dist: make(map[V]W), prev: make(map[V]V)}
+	t.dist[source] = 0
+	return t
+}
+
+// Source returns the vertex the distances are measured from.
+func (t *Tree[V, W]) Source() V {
+	return t.source
+}
+
+// Distance returns the length of a shortest path from the source to v.
+// It returns false if v is unreachable.
+func (t *Tree[V, W]) Distance(v V) (W, bool) {
+	d, ok := t.dist[v]
+	return d, ok
+}
+
+// Distances returns the distances of all reachable vertices.
+func (t *Tree[V, W]) Distances() map[V]W {
+	dist := make(map[V]W, len(t.dist))
+	for v, d := range t.dist {
+		dist[v] = d
+	}
+	return dist
+}
+
+// PathTo returns a shortest path from the source to v.
+// It returns false if v is unreachable.
+func (t *Tree[V, W]) PathTo(v V) (Path[V, W], bool) {
+	d, ok := t.dist[v]
+	if !ok {
+		return Path[V, W]{}, false
+	}
+	return Path[V, W]{Vertices: walkBack(t.prev, t.source, v), Distance: d}, true
+}
+
+// walkBack follows prev from v to source and returns the path from source to v.
+func walkBack[V comparable](prev map[V]V, source, v V) []V {
+	path := []V{v}
+	for v != source {
+		v = prev[v]
+		path = append(path, v)
+	}
+	reverse(path)
+	return path
+}
+
+func reverse[V any](s []V) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// item is an entry of a priority queue ordered by priority.
+// With lazy deletion a vertex may be queued several times; stale entries are skipped when dequeued.
+type item[V comparable, W common.Number] struct {
+	vertex   V
+	priority W
+}
+
+// minFirst makes priorityqueue.Queue return the item with the smallest priority first.
+func minFirst[V comparable, W common.Number](a, b item[V, W]) int8 {
+	if a.priority < b.priority {
+		return 1
+	} else if a.priority > b.priority {
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,240 @@
+package shortestpath
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/kwstars/goads/graphs"
+	"github.com/kwstars/goads/graphs/adjacencylist"
+	"github.com/stretchr/testify/assert"
+)
+
+// sample is the directed graph
+//
+//	a -1-> b -2-> d
+//	a -4-> c -1-> d
+//	b -1-> c       e (isolated)
+func sample() *adjacencylist.Graph[string, int] {
+	g := adjacencylist.NewDirected[string, int]()
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("a", "c", 4)
+	g.AddEdge("b", "c", 1)
+	g.AddEdge("b", "d", 2)
+	g.AddEdge("c", "d", 1)
+	g.AddVertex("e")
+	return g
+}
+
+// reversed returns the graph with every edge turned around.
+func reversed(g graphs.Graph[string, int]) *adjacencylist.Graph[string, int] {
+	r := adjacencylist.NewDirected[string, int]()
+	for _, v := range g.Vertices() {
+		r.AddVertex(v)
+	}
+	for _, e := range g.Edges() {
+		r.AddEdge(e.To, e.From, e.Weight)
+	}
+	return r
+}
+
+func TestDijkstra(t *testing.T) {
+	tree, err := Dijkstra(sample().OutEdges, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, "a", tree.Source())
+	assert.Equal(t, map[string]int{"a": 0, "b": 1, "c": 2, "d": 3}, tree.Distances())
+
+	path, ok := tree.PathTo("d")
+	assert.True(t, ok)
+	assert.Equal(t, 3, path.Distance)
+	assert.Equal(t, []string{"a", "b", "d"}, path.Vertices)
+
+	path, ok = tree.PathTo("a")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"a"}, path.Vertices)
+
+	_, ok = tree.PathTo("e")
+	assert.False(t, ok)
+	_, ok = tree.Distance("e")
+	assert.False(t, ok)
+}
+
+func TestDijkstra_NegativeWeight(t *testing.T) {
+	g := sample()
+	g.AddEdge("c", "b", -1)
+	_, err := Dijkstra(g.OutEdges, "a")
+	assert.True(t, errors.Is(err, ErrNegativeWeight))
+}
+
+func TestBellmanFord(t *testing.T) {
+	g := sample()
+	g.AddEdge("a", "c", -1)
+	tree, err := BellmanFord(g.Vertices(), g.OutEdges, "a")
+	assert.NoError(t, err)
+
+	path, ok := tree.PathTo("d")
+	assert.True(t, ok)
+	assert.Equal(t, Path[string, int]{Vertices: []string{"a", "c", "d"}, Distance: 0}, path)
+}
+
+func TestBellmanFord_NegativeCycle(t *testing.T) {
+	g := sample()
+	g.AddEdge("d", "b", -4) // b -> d -> b weighs -2
+
+	_, err := BellmanFord(g.Vertices(), g.OutEdges, "a")
+	assert.True(t, errors.Is(err, ErrNegativeCycle))
+
+	var cycleErr *NegativeCycleError[string]
+	assert.True(t, errors.As(err, &cycleErr))
+	cycle := cycleErr.Cycle
+	assert.Equal(t, cycle[0], cycle[len(cycle)-1])
+	total := 0
+	for i := 0; i+1 < len(cycle); i++ {
+		w, ok := g.Weight(cycle[i], cycle[i+1])
+		assert.True(t, ok, "%v is not a cycle", cycle)
+		total += w
+	}
+	assert.Less(t, total, 0)
+
+	// A negative cycle that is not reachable from the source is not reported.
+	_, err = BellmanFord(g.Vertices(), g.OutEdges, "e")
+	assert.NoError(t, err)
+}
+
+func TestAStar_Grid(t *testing.T) {
+	type cell struct{ x, y int }
+	const size = 20
+	wall := func(c cell) bool { return c.x == 10 && c.y < 15 }
+	edges := func(c cell) []graphs.Edge[cell, int] {
+		var out []graphs.Edge[cell, int]
+		for _, d := range []cell{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			n := cell{c.x + d.x, c.y + d.y}
+			if n.x >= 0 && n.y >= 0 && n.x < size && n.y < size && !wall(n) {
+				out = append(out, graphs.Edge[cell, int]{From: c, To: n, Weight: 1})
+			}
+		}
+		return out
+	}
+	target := cell{19, 0}
+	manhattan := func(c cell) int {
+		dx, dy := target.x-c.x, target.y-c.y
+		if dx < 0 {
+			dx = -dx
+		}
+		if dy < 0 {
+			dy = -dy
+		}
+		return dx + dy
+	}
+
+	path, ok, err := AStar(edges, cell{0, 0}, target, manhattan)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 19+2*15, path.Distance)
+	assert.Len(t, path.Vertices, path.Distance+1)
+
+	tree, _ := Dijkstra(edges, cell{0, 0})
+	want, _ := tree.Distance(target)
+	assert.Equal(t, want, path.Distance)
+
+	_, ok, err = AStar(edges, cell{0, 0}, cell{-1, -1}, func(cell) int { return 0 })
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestFloydWarshall(t *testing.T) {
+	g := sample()
+	all, err := FloydWarshall(g.Vertices(), g.OutEdges)
+	assert.NoError(t, err)
+
+	path, ok := all.Path("a", "d")
+	assert.True(t, ok)
+	assert.Equal(t, Path[string, int]{Vertices: []string{"a", "b", "d"}, Distance: 3}, path)
+
+	d, ok := all.Distance("b", "d")
+	assert.True(t, ok)
+	assert.Equal(t, 2, d)
+
+	_, ok = all.Distance("d", "a")
+	assert.False(t, ok)
+	_, ok = all.Path("x", "a")
+	assert.False(t, ok)
+
+	g.AddEdge("d", "b", -4)
+	_, err = FloydWarshall(g.Vertices(), g.OutEdges)
+	assert.True(t, errors.Is(err, ErrNegativeCycle))
+}
+
+func TestBidirectionalDijkstra(t *testing.T) {
+	g := sample()
+	r := reversed(g)
+
+	path, ok, err := BidirectionalDijkstra(g.OutEdges, r.OutEdges, "a", "d")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, Path[string, int]{Vertices: []string{"a", "b", "d"}, Distance: 3}, path)
+
+	path, ok, _ = BidirectionalDijkstra(g.OutEdges, r.OutEdges, "b", "b")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"b"}, path.Vertices)
+
+	_, ok, _ = BidirectionalDijkstra(g.OutEdges, r.OutEdges, "a", "e")
+	assert.False(t, ok)
+}
+
+func TestAlgorithmsAgree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 20; round++ {
+		g := adjacencylist.NewDirected[int, int]()
+		n := 30
+		for v := 0; v < n; v++ {
+			g.AddVertex(v)
+		}
+		for i := 0; i < 120; i++ {
+			g.AddEdge(r.Intn(n), r.Intn(n), r.Intn(20))
+		}
+		rev := adjacencylist.NewDirected[int, int]()
+		for _, e := range g.Edges() {
+			rev.AddEdge(e.To, e.From, e.Weight)
+		}
+
+		dijkstra, err := Dijkstra(g.OutEdges, 0)
+		assert.NoError(t, err)
+		bellman, err := BellmanFord(g.Vertices(), g.OutEdges, 0)
+		assert.NoError(t, err)
+		all, err := FloydWarshall(g.Vertices(), g.OutEdges)
+		assert.NoError(t, err)
+		assert.Equal(t, dijkstra.Distances(), bellman.Distances())
+
+		for v := 0; v < n; v++ {
+			want, reachable := dijkstra.Distance(v)
+
+			d, ok := all.Distance(0, v)
+			assert.Equal(t, reachable, ok)
+			assert.Equal(t, want, d)
+
+			path, ok, err := BidirectionalDijkstra(g.OutEdges, rev.OutEdges, 0, v)
+			assert.NoError(t, err)
+			assert.Equal(t, reachable, ok)
+			assert.Equal(t, want, path.Distance)
+			assertValidPath(t, g, path)
+
+			path, ok, err = AStar(g.OutEdges, 0, v, func(int) int { return 0 })
+			assert.NoError(t, err)
+			assert.Equal(t, reachable, ok)
+			assert.Equal(t, want, path.Distance)
+			assertValidPath(t, g, path)
+		}
+	}
+}
+
+// assertValidPath checks that consecutive vertices are joined by edges adding up to the distance.
+func assertValidPath(t *testing.T, g graphs.Graph[int, int], path Path[int, int]) {
+	total := 0
+	for i := 0; i+1 < len(path.Vertices); i++ {
+		w, ok := g.Weight(path.Vertices[i], path.Vertices[i+1])
+		assert.True(t, ok)
+		total += w
+	}
+	assert.Equal(t, path.Distance, total)
+}
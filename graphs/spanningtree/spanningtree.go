@@ -0,0 +1,86 @@
+// Package spanningtree implements minimum spanning tree algorithms for undirected weighted graphs.
+//
+// Both algorithms return a minimum spanning forest when the graph is not connected: one tree
+// per connected component. Weights can be of any integer or floating-point type and may be negative.
+//
+//   - Kruskal: works on an edge list, O(E log E), joining components with a disjoint set.
+//   - Prim: works on a graphs.EdgeFunc, O(E log V), growing each tree from a binary heap of edges.
+//
+// References: https://en.wikipedia.org/wiki/Minimum_spanning_tree
+package spanningtree
+
+import (
+	"sort"
+
+	"github.com/kwstars/goads/disjointset"
+	"github.com/kwstars/goads/graphs"
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/trees/binaryheap"
+)
+
+// Kruskal returns the edges of a minimum spanning forest and their total weight.
+// It considers the edges by increasing weight and keeps each one that joins two different trees.
+// Each undirected edge needs to be listed only once, as the Edges method of an undirected graph does.
+func Kruskal[V comparable, W common.Number](edges []graphs.Edge[V, W]) ([]graphs.Edge[V, W], W) {
+	sorted := append([]graphs.Edge[V, W](nil), edges...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Weight < sorted[j].Weight })
+
+	forest := disjointset.NewGeneric[V]()
+	var chosen []graphs.Edge[V, W]
+	var total W
+	for _, e := range sorted {
+		if forest.Union(e.From, e.To) {
+			chosen = append(chosen, e)
+			total += e.Weight
+		}
+	}
+	return chosen, total
+}
+
+// Prim returns the edges of a minimum spanning forest and their total weight.
+// Starting from each vertex not yet in a tree, it repeatedly adds the lightest edge leaving the tree.
+// edges must return the edges of an undirected graph, that is every edge in both directions,
+// as the OutEdges method of an undirected graph does.
+func Prim[V comparable, W common.Number](vertices []V, edges graphs.EdgeFunc[V, W]) ([]graphs.Edge[V, W], W) {
+	inTree := make(map[V]bool, len(vertices))
+	heap := binaryheap.New(lightestFirst[V, W])
+	var chosen []graphs.Edge[V, W]
+	var total W
+
+	grow := func(v V) {
+		inTree[v] = true
+		for _, e := range edges(v) {
+			if !inTree[e.To] {
+				heap.Push(e)
+			}
+		}
+	}
+
+	for _, root := range vertices {
+		if inTree[root] {
+			continue
+		}
+		grow(root)
+		for !heap.Empty() {
+			e, _ := heap.Pop()
+			// Edges are not removed when their far end joins the tree; skip them here.
+			if inTree[e.To] {
+				continue
+			}
+			chosen = append(chosen, e)
+			total += e.Weight
+			grow(e.To)
+		}
+	}
+	return chosen, total
+}
+
+// lightestFirst makes binaryheap.BinaryHeap a min heap of edges by weight.
+func lightestFirst[V comparable, W common.Number](a, b graphs.Edge[V, W]) int8 {
+	if a.Weight < b.Weight {
+		return 1
+	} else if a.Weight > b.Weight {
+		return -1
+	}
+	return 0
+}
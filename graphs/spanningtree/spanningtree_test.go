@@ -0,0 +1,86 @@
+package spanningtree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/kwstars/goads/disjointset"
+	"github.com/kwstars/goads/graphs"
+	"github.com/kwstars/goads/graphs/adjacencylist"
+	"github.com/stretchr/testify/assert"
+)
+
+// sample is the undirected graph from the Wikipedia article on Kruskal's algorithm,
+// plus a separate component {x, y}.
+func sample() *adjacencylist.Graph[string, int] {
+	g := adjacencylist.NewUndirected[string, int]()
+	for _, e := range []graphs.Edge[string, int]{
+		{From: "A", To: "B", Weight: 7}, {From: "A", To: "D", Weight: 5},
+		{From: "B", To: "C", Weight: 8}, {From: "B", To: "D", Weight: 9}, {From: "B", To: "E", Weight: 7},
+		{From: "C", To: "E", Weight: 5},
+		{From: "D", To: "E", Weight: 15}, {From: "D", To: "F", Weight: 6},
+		{From: "E", To: "F", Weight: 8}, {From: "E", To: "G", Weight: 9},
+		{From: "F", To: "G", Weight: 11},
+		{From: "x", To: "y", Weight: -2},
+	} {
+		g.AddEdge(e.From, e.To, e.Weight)
+	}
+	return g
+}
+
+func TestKruskal(t *testing.T) {
+	g := sample()
+	edges, total := Kruskal(g.Edges())
+	assert.Equal(t, 39-2, total)
+	assert.Len(t, edges, 7)
+	assertForest(t, g, edges, total)
+
+	edges, total = Kruskal[string, int](nil)
+	assert.Empty(t, edges)
+	assert.Equal(t, 0, total)
+}
+
+func TestPrim(t *testing.T) {
+	g := sample()
+	edges, total := Prim(g.Vertices(), g.OutEdges)
+	assert.Equal(t, 39-2, total)
+	assert.Len(t, edges, 7)
+	assertForest(t, g, edges, total)
+	assert.Equal(t, graphs.Edge[string, int]{From: "A", To: "D", Weight: 5}, edges[0])
+}
+
+func TestAgree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 50; round++ {
+		g := adjacencylist.NewUndirected[int, float64]()
+		for v := 0; v < 40; v++ {
+			g.AddVertex(v)
+		}
+		for i := 0; i < 80; i++ {
+			g.AddEdge(r.Intn(40), r.Intn(40), r.Float64())
+		}
+		kruskal, kTotal := Kruskal(g.Edges())
+		prim, pTotal := Prim(g.Vertices(), g.OutEdges)
+		assert.InDelta(t, kTotal, pTotal, 1e-9)
+		assert.Len(t, prim, len(kruskal))
+	}
+}
+
+// assertForest checks that the edges belong to g, add up to total and form a spanning forest.
+func assertForest(t *testing.T, g graphs.Graph[string, int], edges []graphs.Edge[string, int], total int) {
+	forest := disjointset.NewGeneric(g.Vertices()...)
+	components := forest.Count()
+	sum := 0
+	for _, e := range edges {
+		w, ok := g.Weight(e.From, e.To)
+		assert.True(t, ok)
+		assert.Equal(t, w, e.Weight)
+		assert.True(t, forest.Union(e.From, e.To), "%v closes a cycle", e)
+		sum += e.Weight
+	}
+	assert.Equal(t, total, sum)
+	for _, e := range g.Edges() {
+		assert.True(t, forest.Connected(e.From, e.To))
+	}
+	assert.Equal(t, components-len(edges), forest.Count())
+}
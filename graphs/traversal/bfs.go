@@ -0,0 +1,64 @@
+package traversal
+
+import (
+	"github.com/kwstars/goads/graphs"
+	"github.com/kwstars/goads/lists/arraylist"
+)
+
+// BFS runs a breadth-first search from the sources, which all start at depth 0.
+// Vertices are pre-visited in order of their distance in edges from the nearest source,
+// and post-visited as soon as their edges have been examined.
+// It returns false if a callback stopped the traversal.
+func BFS[V comparable](neighbors graphs.NeighborFunc[V], visitor Visitor[V], sources ...V) bool {
+	discovered := make(map[V]bool)
+	queue := arraylist.New[V](nil)
+
+	for _, s := range sources {
+		if discovered[s] {
+			continue
+		}
+		discovered[s] = true
+		if !visitor.preVisit(s) {
+			return false
+		}
+		queue.Append(s)
+	}
+
+	for !queue.Empty() {
+		u, _ := queue.PopFront()
+		for _, w := range neighbors(u) {
+			if discovered[w] {
+				if !visitor.edge(u, w, NonTreeEdge) {
+					return false
+				}
+				continue
+			}
+			if !visitor.edge(u, w, TreeEdge) {
+				return false
+			}
+			discovered[w] = true
+			if !visitor.preVisit(w) {
+				return false
+			}
+			queue.Append(w)
+		}
+		if !visitor.postVisit(u) {
+			return false
+		}
+	}
+	return true
+}
+
+// Distances returns the number of edges on a shortest path from source to every reachable vertex.
+func Distances[V comparable](neighbors graphs.NeighborFunc[V], source V) map[V]int {
+	dist := map[V]int{source: 0}
+	BFS(neighbors, Visitor[V]{
+		Edge: func(from, to V, kind EdgeKind) bool {
+			if kind == TreeEdge {
+				dist[to] = dist[from] + 1
+			}
+			return true
+		},
+	}, source)
+	return dist
+}
@@ -0,0 +1,119 @@
+package traversal
+
+import (
+	"github.com/kwstars/goads/graphs"
+	"github.com/kwstars/goads/lists/arraylist"
+)
+
+type color int8
+
+const (
+	white color = iota // undiscovered
+	gray               // on the stack
+	black              // finished
+)
+
+// frame is a vertex on the DFS stack together with the neighbors still to examine.
+type frame[V comparable] struct {
+	vertex V
+	next   []V
+}
+
+// DFS runs a depth-first search from each root in turn that has not been discovered yet,
+// building a depth-first forest. Edges are classified relative to that forest; in an
+// undirected graph each tree edge is also seen in reverse as a back edge to the parent.
+// It returns false if a callback stopped the traversal.
+func DFS[V comparable](neighbors graphs.NeighborFunc[V], visitor Visitor[V], roots ...V) bool {
+	state := make(map[V]color)
+	order := make(map[V]int) // discovery time, to tell forward from cross edges
+	stack := arraylist.New[*frame[V]](nil)
+
+	discover := func(v V) bool {
+		state[v] = gray
+		order[v] = len(order)
+		if !visitor.preVisit(v) {
+			return false
+		}
+		stack.Append(&frame[V]{vertex: v, next: neighbors(v)})
+		return true
+	}
+
+	for _, r := range roots {
+		if state[r] != white {
+			continue
+		}
+		if !discover(r) {
+			return false
+		}
+		for !stack.Empty() {
+			top, _ := stack.Get(stack.Size() - 1)
+			if len(top.next) == 0 {
+				_, _ = stack.Pop()
+				state[top.vertex] = black
+				if !visitor.postVisit(top.vertex) {
+					return false
+				}
+				continue
+			}
+
+			u, w := top.vertex, top.next[0]
+			top.next = top.next[1:]
+			var kind EdgeKind
+			switch {
+			case state[w] == white:
+				kind = TreeEdge
+			case state[w] == gray:
+				kind = BackEdge
+			case order[w] > order[u]:
+				kind = ForwardEdge
+			default:
+				kind = CrossEdge
+			}
+			if !visitor.edge(u, w, kind) {
+				return false
+			}
+			if kind == TreeEdge && !discover(w) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// FindCycle returns a directed cycle reachable from any of the vertices, with the first vertex
+// repeated at the end, or false if there is none.
+func FindCycle[V comparable](vertices []V, neighbors graphs.NeighborFunc[V]) ([]V, bool) {
+	var cycle []V
+	parent := make(map[V]V)
+	DFS(neighbors, Visitor[V]{
+		Edge: func(from, to V, kind EdgeKind) bool {
+			switch kind {
+			case TreeEdge:
+				parent[to] = from
+			case BackEdge:
+				cycle = closeCycle(parent, from, to)
+				return false
+			}
+			return true
+		},
+	}, vertices...)
+	return cycle, cycle != nil
+}
+
+func reverse[V any](s []V) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// closeCycle returns the cycle formed by the back edge from -> to, given the DFS tree parents.
+// The gray vertices are exactly the current DFS path, so following parents from the edge
+// source climbs back to its target.
+func closeCycle[V comparable](parent map[V]V, from, to V) []V {
+	cycle := []V{to}
+	for v := from; v != to; v = parent[v] {
+		cycle = append(cycle, v)
+	}
+	reverse(cycle[1:])
+	return append(cycle, to)
+}
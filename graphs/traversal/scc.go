@@ -0,0 +1,125 @@
+package traversal
+
+import (
+	"github.com/kwstars/goads/graphs"
+	"github.com/kwstars/goads/lists/arraylist"
+)
+
+// tarjanFrame is a vertex on Tarjan's call stack and the position of the next neighbor to examine.
+type tarjanFrame[V comparable] struct {
+	vertex V
+	next   []V
+	i      int
+}
+
+// Tarjan returns the strongly connected components of the graph using Tarjan's algorithm,
+// a single depth-first search tracking the lowest discovery index reachable from each subtree.
+// Components are returned in reverse topological order of the condensation: a component
+// only has edges to components before it. vertices must contain every vertex of the graph.
+func Tarjan[V comparable](vertices []V, neighbors graphs.NeighborFunc[V]) [][]V {
+	index := make(map[V]int, len(vertices))
+	low := make(map[V]int, len(vertices))
+	onStack := make(map[V]bool)
+	var components [][]V
+	var component []V // vertices visited but not yet assigned to a component
+	calls := arraylist.New[*tarjanFrame[V]](nil)
+
+	visit := func(v V) {
+		index[v] = len(index)
+		low[v] = index[v]
+		component = append(component, v)
+		onStack[v] = true
+		calls.Append(&tarjanFrame[V]{vertex: v, next: neighbors(v)})
+	}
+
+	for _, root := range vertices {
+		if _, ok := index[root]; ok {
+			continue
+		}
+		visit(root)
+		for !calls.Empty() {
+			top, _ := calls.Get(calls.Size() - 1)
+			v := top.vertex
+			if top.i < len(top.next) {
+				w := top.next[top.i]
+				top.i++
+				if _, ok := index[w]; !ok {
+					visit(w)
+				} else if onStack[w] && index[w] < low[v] {
+					low[v] = index[w]
+				}
+				continue
+			}
+
+			_, _ = calls.Pop()
+			if low[v] == index[v] {
+				i := len(component) - 1
+				for component[i] != v {
+					i--
+				}
+				scc := append([]V(nil), component[i:]...)
+				for _, w := range scc {
+					delete(onStack, w)
+				}
+				component = component[:i]
+				components = append(components, scc)
+			}
+			if !calls.Empty() {
+				parent, _ := calls.Get(calls.Size() - 1)
+				if low[v] < low[parent.vertex] {
+					low[parent.vertex] = low[v]
+				}
+			}
+		}
+	}
+	return components
+}
+
+// Kosaraju returns the strongly connected components of the graph using Kosaraju's algorithm:
+// a depth-first search records finishing order, then a search of the reversed graph in reverse
+// finishing order collects one component per tree. Components are returned in topological order
+// of the condensation: a component only has edges to components after it.
+// vertices must contain every vertex of the graph.
+func Kosaraju[V comparable](vertices []V, neighbors graphs.NeighborFunc[V]) [][]V {
+	finished := make([]V, 0, len(vertices))
+	reversed := make(map[V][]V, len(vertices))
+	DFS(neighbors, Visitor[V]{
+		PostVisit: func(v V) bool {
+			finished = append(finished, v)
+			return true
+		},
+		Edge: func(from, to V, _ EdgeKind) bool {
+			reversed[to] = append(reversed[to], from)
+			return true
+		},
+	}, vertices...)
+
+	var components [][]V
+	var component []V
+	assigned := make(map[V]bool, len(vertices))
+	// Each search runs in the reversed graph with earlier components removed.
+	backward := func(v V) []V {
+		var out []V
+		for _, w := range reversed[v] {
+			if !assigned[w] {
+				out = append(out, w)
+			}
+		}
+		return out
+	}
+	for i := len(finished) - 1; i >= 0; i-- {
+		if assigned[finished[i]] {
+			continue
+		}
+		component = nil
+		DFS(backward, Visitor[V]{
+			PreVisit: func(v V) bool {
+				assigned[v] = true
+				component = append(component, v)
+				return true
+			},
+		}, finished[i])
+		components = append(components, component)
+	}
+	return components
+}
@@ -0,0 +1,82 @@
+package traversal
+
+import (
+	"github.com/kwstars/goads/graphs"
+	"github.com/kwstars/goads/lists/arraylist"
+)
+
+// TopologicalSort orders the vertices so that every edge leads from an earlier to a later vertex,
+// using Kahn's algorithm: repeatedly remove a vertex without incoming edges. Among vertices that
+// become free at the same time, the order of the vertices argument is kept.
+// vertices must contain every vertex of the graph. If the graph has a cycle it returns a *CycleError.
+func TopologicalSort[V comparable](vertices []V, neighbors graphs.NeighborFunc[V]) ([]V, error) {
+	indegree := make(map[V]int, len(vertices))
+	for _, v := range vertices {
+		for _, w := range neighbors(v) {
+			indegree[w]++
+		}
+	}
+
+	queue := arraylist.New[V](nil)
+	for _, v := range vertices {
+		if indegree[v] == 0 {
+			queue.Append(v)
+		}
+	}
+
+	sorted := make([]V, 0, len(vertices))
+	for !queue.Empty() {
+		v, _ := queue.PopFront()
+		sorted = append(sorted, v)
+		for _, w := range neighbors(v) {
+			if indegree[w]--; indegree[w] == 0 {
+				queue.Append(w)
+			}
+		}
+	}
+
+	if len(sorted) < len(vertices) {
+		// Every vertex left over still has an incoming edge, so a cycle runs through them.
+		var rest []V
+		for _, v := range vertices {
+			if indegree[v] > 0 {
+				rest = append(rest, v)
+			}
+		}
+		cycle, _ := FindCycle(rest, neighbors)
+		return nil, &CycleError[V]{Cycle: cycle}
+	}
+	return sorted, nil
+}
+
+// TopologicalSortDFS orders the vertices so that every edge leads from an earlier to a later vertex,
+// using the reverse postorder of a depth-first search.
+// vertices must contain every vertex of the graph. If the graph has a cycle it returns a *CycleError.
+func TopologicalSortDFS[V comparable](vertices []V, neighbors graphs.NeighborFunc[V]) ([]V, error) {
+	sorted := make([]V, 0, len(vertices))
+	var cycle []V
+	parent := make(map[V]V)
+
+	DFS(neighbors, Visitor[V]{
+		PostVisit: func(v V) bool {
+			sorted = append(sorted, v)
+			return true
+		},
+		Edge: func(from, to V, kind EdgeKind) bool {
+			switch kind {
+			case TreeEdge:
+				parent[to] = from
+			case BackEdge:
+				cycle = closeCycle(parent, from, to)
+				return false
+			}
+			return true
+		},
+	}, vertices...)
+
+	if cycle != nil {
+		return nil, &CycleError[V]{Cycle: cycle}
+	}
+	reverse(sorted)
+	return sorted, nil
+}
@@ -0,0 +1,88 @@
+// Package traversal implements graph traversals and the algorithms built on them:
+// breadth-first and depth-first search with visitor callbacks, cycle detection,
+// topological sorting and strongly connected components.
+//
+// The algorithms only need a graphs.NeighborFunc (and the vertex list where every vertex must be visited),
+// so they run on any graph representation. All traversals are iterative and keep their stack
+// on the heap, so they do not overflow on deep graphs. Cycle detection, topological sorting and
+// strongly connected components treat the graph as directed.
+//
+// References: https://en.wikipedia.org/wiki/Graph_traversal
+package traversal
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrCycle = errors.New("graph has a cycle")
+
+// CycleError reports a cycle in a graph that was required to be acyclic. It matches ErrCycle with errors.Is.
+type CycleError[V comparable] struct {
+	// Cycle lists the vertices of the cycle in edge order; the first vertex is repeated at the end.
+	Cycle []V
+}
+
+func (e *CycleError[V]) Error() string {
+	return fmt.Sprintf("%v: %v", ErrCycle, e.Cycle)
+}
+
+func (e *CycleError[V]) Unwrap() error {
+	return ErrCycle
+}
+
+// EdgeKind classifies an edge relative to the search forest.
+type EdgeKind int8
+
+const (
+	// TreeEdge leads to a newly discovered vertex.
+	TreeEdge EdgeKind = iota
+	// BackEdge leads to an ancestor still on the DFS stack, including the vertex itself; it closes a cycle.
+	BackEdge
+	// ForwardEdge leads to an already finished descendant.
+	ForwardEdge
+	// CrossEdge leads to an already finished vertex that is not a descendant.
+	CrossEdge
+	// NonTreeEdge is any edge to an already discovered vertex in a breadth-first search,
+	// which does not distinguish between back, forward and cross edges.
+	NonTreeEdge
+)
+
+func (k EdgeKind) String() string {
+	switch k {
+	case TreeEdge:
+		return "tree"
+	case BackEdge:
+		return "back"
+	case ForwardEdge:
+		return "forward"
+	case CrossEdge:
+		return "cross"
+	case NonTreeEdge:
+		return "non-tree"
+	}
+	return fmt.Sprintf("EdgeKind(%d)", int8(k))
+}
+
+// Visitor holds the callbacks of a traversal. Nil callbacks are skipped.
+// A callback returning false stops the whole traversal.
+type Visitor[V comparable] struct {
+	// PreVisit is called when a vertex is discovered.
+	PreVisit func(v V) bool
+	// PostVisit is called when all edges leaving a vertex have been examined.
+	PostVisit func(v V) bool
+	// Edge is called for every edge examined, before the target vertex is discovered.
+	Edge func(from, to V, kind EdgeKind) bool
+}
+
+func (vis *Visitor[V]) preVisit(v V) bool {
+	return vis.PreVisit == nil || vis.PreVisit(v)
+}
+
+func (vis *Visitor[V]) postVisit(v V) bool {
+	return vis.PostVisit == nil || vis.PostVisit(v)
+}
+
+func (vis *Visitor[V]) edge(from, to V, kind EdgeKind) bool {
+	return vis.Edge == nil || vis.Edge(from, to, kind)
+}
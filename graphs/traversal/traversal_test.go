@@ -0,0 +1,223 @@
+package traversal
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/kwstars/goads/graphs/adjacencylist"
+	"github.com/stretchr/testify/assert"
+)
+
+type unit = struct{}
+
+// sample is the directed graph
+//
+//	1 -> 2 -> 3 -> 4
+//	1 -> 3    5 -> 4
+func sample() *adjacencylist.Graph[int, unit] {
+	g := adjacencylist.NewDirected[int, unit]()
+	for _, e := range [][2]int{{1, 2}, {1, 3}, {2, 3}, {3, 4}, {5, 4}} {
+		g.AddEdge(e[0], e[1], unit{})
+	}
+	return g
+}
+
+func TestBFS(t *testing.T) {
+	g := sample()
+	var pre, post []int
+	var tree [][2]int
+	completed := BFS(g.Neighbors, Visitor[int]{
+		PreVisit:  func(v int) bool { pre = append(pre, v); return true },
+		PostVisit: func(v int) bool { post = append(post, v); return true },
+		Edge: func(from, to int, kind EdgeKind) bool {
+			if kind == TreeEdge {
+				tree = append(tree, [2]int{from, to})
+			} else {
+				assert.Equal(t, NonTreeEdge, kind)
+			}
+			return true
+		},
+	}, 1)
+	assert.True(t, completed)
+	assert.Equal(t, []int{1, 2, 3, 4}, pre)
+	assert.Equal(t, []int{1, 2, 3, 4}, post)
+	assert.Equal(t, [][2]int{{1, 2}, {1, 3}, {3, 4}}, tree)
+
+	assert.Equal(t, map[int]int{1: 0, 2: 1, 3: 1, 4: 2}, Distances(g.Neighbors, 1))
+
+	// Stopping early.
+	pre = nil
+	completed = BFS(g.Neighbors, Visitor[int]{
+		PreVisit: func(v int) bool { pre = append(pre, v); return v != 2 },
+	}, 1, 5)
+	assert.False(t, completed)
+	assert.Equal(t, []int{1, 5, 2}, pre)
+}
+
+func TestDFS_EdgeClassification(t *testing.T) {
+	g := sample()
+	g.AddEdge(4, 2, unit{}) // back edge closing 2 -> 3 -> 4 -> 2
+	g.AddEdge(4, 4, unit{}) // self-loop
+
+	kinds := make(map[[2]int]EdgeKind)
+	var pre, post []int
+	completed := DFS(g.Neighbors, Visitor[int]{
+		PreVisit:  func(v int) bool { pre = append(pre, v); return true },
+		PostVisit: func(v int) bool { post = append(post, v); return true },
+		Edge: func(from, to int, kind EdgeKind) bool {
+			kinds[[2]int{from, to}] = kind
+			return true
+		},
+	}, 1, 5)
+	assert.True(t, completed)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, pre)
+	assert.Equal(t, []int{4, 3, 2, 1, 5}, post)
+	assert.Equal(t, map[[2]int]EdgeKind{
+		{1, 2}: TreeEdge,
+		{2, 3}: TreeEdge,
+		{3, 4}: TreeEdge,
+		{4, 2}: BackEdge,
+		{4, 4}: BackEdge,
+		{1, 3}: ForwardEdge,
+		{5, 4}: CrossEdge,
+	}, kinds)
+	assert.Equal(t, "cross", CrossEdge.String())
+}
+
+func TestDFS_Deep(t *testing.T) {
+	// A path this long would need a very deep call stack in a recursive implementation.
+	const n = 1_000_000
+	next := func(v int) []int {
+		if v+1 < n {
+			return []int{v + 1}
+		}
+		return nil
+	}
+	count := 0
+	last := -1
+	DFS(next, Visitor[int]{
+		PreVisit:  func(int) bool { count++; return true },
+		PostVisit: func(v int) bool { last = v; return true },
+	}, 0)
+	assert.Equal(t, n, count)
+	assert.Equal(t, 0, last)
+}
+
+func TestFindCycle(t *testing.T) {
+	g := sample()
+	_, ok := FindCycle(g.Vertices(), g.Neighbors)
+	assert.False(t, ok)
+
+	g.AddEdge(4, 2, unit{})
+	cycle, ok := FindCycle(g.Vertices(), g.Neighbors)
+	assert.True(t, ok)
+	assert.Equal(t, []int{2, 3, 4, 2}, cycle)
+}
+
+func TestTopologicalSort(t *testing.T) {
+	for name, sortFunc := range map[string]func([]int, func(int) []int) ([]int, error){
+		"kahn": func(v []int, n func(int) []int) ([]int, error) { return TopologicalSort(v, n) },
+		"dfs":  func(v []int, n func(int) []int) ([]int, error) { return TopologicalSortDFS(v, n) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			g := sample()
+			sorted, err := sortFunc(g.Vertices(), g.Neighbors)
+			assert.NoError(t, err)
+			assertTopological(t, g, sorted)
+
+			g.AddEdge(4, 2, unit{})
+			_, err = sortFunc(g.Vertices(), g.Neighbors)
+			assert.True(t, errors.Is(err, ErrCycle))
+			var cycleErr *CycleError[int]
+			assert.True(t, errors.As(err, &cycleErr))
+			assertCycle(t, g, cycleErr.Cycle)
+		})
+	}
+
+	g := sample()
+	sorted, _ := TopologicalSort(g.Vertices(), g.Neighbors)
+	assert.Equal(t, []int{1, 5, 2, 3, 4}, sorted)
+	sorted, _ = TopologicalSortDFS(g.Vertices(), g.Neighbors)
+	assert.Equal(t, []int{5, 1, 2, 3, 4}, sorted)
+}
+
+func TestTopologicalSort_RandomDAG(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	g := adjacencylist.NewDirected[int, unit]()
+	perm := r.Perm(200)
+	for _, v := range perm {
+		g.AddVertex(v)
+	}
+	for i := 0; i < 1000; i++ {
+		a, b := r.Intn(200), r.Intn(200)
+		if a < b {
+			g.AddEdge(perm[a], perm[b], unit{})
+		}
+	}
+	sorted, err := TopologicalSort(g.Vertices(), g.Neighbors)
+	assert.NoError(t, err)
+	assertTopological(t, g, sorted)
+	sorted, err = TopologicalSortDFS(g.Vertices(), g.Neighbors)
+	assert.NoError(t, err)
+	assertTopological(t, g, sorted)
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	// {1,2,3} -> {4,5} -> {6}, 7 alone with a self-loop.
+	g := adjacencylist.NewDirected[int, unit]()
+	for _, e := range [][2]int{{1, 2}, {2, 3}, {3, 1}, {3, 4}, {4, 5}, {5, 4}, {5, 6}, {7, 7}} {
+		g.AddEdge(e[0], e[1], unit{})
+	}
+	want := [][]int{{1, 2, 3}, {4, 5}, {6}, {7}}
+
+	tarjan := Tarjan(g.Vertices(), g.Neighbors)
+	kosaraju := Kosaraju(g.Vertices(), g.Neighbors)
+	// Tarjan yields sinks first, Kosaraju sources first.
+	assert.Equal(t, []int{6}, tarjan[0])
+	assert.Equal(t, []int{6}, kosaraju[len(kosaraju)-1])
+
+	assert.Equal(t, want, normalize(tarjan))
+	assert.Equal(t, want, normalize(kosaraju))
+
+	r := rand.New(rand.NewSource(2))
+	for round := 0; round < 20; round++ {
+		g := adjacencylist.NewDirected[int, unit]()
+		for v := 0; v < 50; v++ {
+			g.AddVertex(v)
+		}
+		for i := 0; i < 70; i++ {
+			g.AddEdge(r.Intn(50), r.Intn(50), unit{})
+		}
+		assert.Equal(t, normalize(Tarjan(g.Vertices(), g.Neighbors)), normalize(Kosaraju(g.Vertices(), g.Neighbors)))
+	}
+}
+
+func assertTopological(t *testing.T, g *adjacencylist.Graph[int, unit], sorted []int) {
+	assert.Len(t, sorted, g.Size())
+	position := make(map[int]int)
+	for i, v := range sorted {
+		position[v] = i
+	}
+	for _, e := range g.Edges() {
+		assert.Less(t, position[e.From], position[e.To])
+	}
+}
+
+func assertCycle(t *testing.T, g *adjacencylist.Graph[int, unit], cycle []int) {
+	assert.Greater(t, len(cycle), 1)
+	assert.Equal(t, cycle[0], cycle[len(cycle)-1])
+	for i := 0; i+1 < len(cycle); i++ {
+		assert.True(t, g.HasEdge(cycle[i], cycle[i+1]))
+	}
+}
+
+// normalize sorts the vertices of each component and the components by their smallest vertex.
+func normalize(components [][]int) [][]int {
+	for _, c := range components {
+		sort.Ints(c)
+	}
+	sort.Slice(components, func(i, j int) bool { return components[i][0] < components[j][0] })
+	return components
+}
@@ -47,3 +47,26 @@ type List[T any] interface {
 	// SubList returns a subsection of the list, between two indices.
 	SubList(fromIndex int, toIndex int) ([]T, error)
 }
+
+// Reader is the read-only part of List. Immutable sequences implement it too.
+type Reader[T any] interface {
+	// Empty returns true if the sequence holds no elements.
+	Empty() bool
+
+	// Size returns the number of elements.
+	Size() int
+
+	// Get retrieves an element at a specific position in the sequence.
+	Get(index int) (T, error)
+
+	// IndexOf finds the first occurrence of an element in the sequence, returning its index.
+	// If the element is not present, it returns -1.
+	IndexOf(element T) int
+
+	// LastIndexOf finds the last occurrence of an element in the sequence, returning its index.
+	// If the element is not present, it returns -1.
+	LastIndexOf(element T) int
+
+	// SubList returns the elements between two indices.
+	SubList(fromIndex int, toIndex int) ([]T, error)
+}
@@ -0,0 +1,385 @@
+// Package rope implements a rope: a sequence stored as a balanced tree of chunks, so that inserting,
+// removing, splitting and concatenating at arbitrary positions take O(log n) instead of moving the tail.
+//
+// The tree is an implicit treap: nodes are ordered by position rather than by key, every node holds
+// a chunk of up to chunkSize consecutive elements, and random priorities keep the expected depth at
+// O(log n). Single-element edits inside a chunk with room are done in place. Text wraps a rope of runes
+// for text buffers.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Rope_(data_structure), https://en.wikipedia.org/wiki/Treap#Implicit_treap
+package rope
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/kwstars/goads/lists"
+	"github.com/kwstars/goads/pkg/common"
+)
+
+var (
+	ErrIndexOutOfRange                = errors.New("index out of range")
+	ErrFormIndexMustBeLessThanToIndex = errors.New("fromIndex must be less than or equal to toIndex")
+)
+
+var _ lists.List[int] = (*Rope[int])(nil)
+
+// chunkSize is the largest number of elements a node holds.
+const chunkSize = 128
+
+type node[T any] struct {
+	chunk       []T
+	left, right *node[T]
+	size        int // number of elements in the subtree
+	priority    uint64
+}
+
+func size[T any](n *node[T]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *node[T]) update() {
+	n.size = size(n.left) + size(n.right) + len(n.chunk)
+}
+
+// Option is a function that can be passed to New to customize the Rope.
+type Option[T any] func(*Rope[T])
+
+// WithSeed makes the shape of the tree depend only on seed and the operations.
+func WithSeed[T any](seed int64) Option[T] {
+	return func(r *Rope[T]) {
+		r.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// Rope is a sequence of elements of type T.
+type Rope[T any] struct {
+	root *node[T]
+	cmp  common.Comparator[T, T]
+	rng  *rand.Rand
+}
+
+// New returns a rope holding a copy of elements. cmp is only used by IndexOf and LastIndexOf.
+func New[T any](cmp common.Comparator[T, T], elements []T, opts ...Option[T]) *Rope[T] {
+	r := &Rope[T]{cmp: cmp}
+	for _, option := range opts {
+		option(r)
+	}
+	if r.rng == nil {
+		r.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	r.root = r.build(elements)
+	return r
+}
+
+// build returns a tree holding a copy of elements.
+func (r *Rope[T]) build(elements []T) *node[T] {
+	var root *node[T]
+	for len(elements) > 0 {
+		n := chunkSize
+		if n > len(elements) {
+			n = len(elements)
+		}
+		root = merge(root, r.newNode(append([]T(nil), elements[:n]...)))
+		elements = elements[n:]
+	}
+	return root
+}
+
+func (r *Rope[T]) newNode(chunk []T) *node[T] {
+	return &node[T]{chunk: chunk, size: len(chunk), priority: r.rng.Uint64()}
+}
+
+// split divides the subtree of n into its first k elements and the rest.
+func (r *Rope[T]) split(n *node[T], k int) (first, rest *node[T]) {
+	if n == nil {
+		return nil, nil
+	}
+	ls := size(n.left)
+	switch {
+	case k <= ls:
+		first, n.left = r.split(n.left, k)
+		n.update()
+		return first, n
+	case k >= ls+len(n.chunk):
+		n.right, rest = r.split(n.right, k-ls-len(n.chunk))
+		n.update()
+		return n, rest
+	}
+	// The cut falls inside the chunk: the tail becomes a node of its own in front of the right subtree.
+	i := k - ls
+	tail := r.newNode(append([]T(nil), n.chunk[i:]...))
+	rest = merge(tail, n.right)
+	n.chunk = n.chunk[:i:i]
+	n.right = nil
+	n.update()
+	return n, rest
+}
+
+// merge joins two subtrees, the elements of a before those of b.
+func merge[T any](a, b *node[T]) *node[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.priority > b.priority {
+		a.right = merge(a.right, b)
+		a.update()
+		return a
+	}
+	b.left = merge(a, b.left)
+	b.update()
+	return b
+}
+
+// locate returns the path to the node holding index and the offset of index in its chunk.
+// With end set, an index just past the end of a chunk also selects that chunk.
+func (r *Rope[T]) locate(index int, end bool) (path []*node[T], offset int) {
+	for n := r.root; n != nil; {
+		path = append(path, n)
+		ls := size(n.left)
+		switch {
+		case index < ls:
+			n = n.left
+		case index < ls+len(n.chunk) || end && index == ls+len(n.chunk):
+			return path, index - ls
+		default:
+			index -= ls + len(n.chunk)
+			n = n.right
+		}
+	}
+	return nil, 0
+}
+
+func (r *Rope[T]) checkIndex(index, limit int) error {
+	if index < 0 || index > limit {
+		return fmt.Errorf("%w: %d, size: %d", ErrIndexOutOfRange, index, r.Size())
+	}
+	return nil
+}
+
+func (r *Rope[T]) checkRange(fromIndex, toIndex int) error {
+	if fromIndex < 0 || toIndex < 0 || fromIndex > r.Size() || toIndex > r.Size() {
+		return fmt.Errorf("%w, fromIndex: %d, toIndex: %d, size: %d", ErrIndexOutOfRange, fromIndex, toIndex, r.Size())
+	}
+	if fromIndex > toIndex {
+		return fmt.Errorf("%w, fromIndex: %d, toIndex: %d", ErrFormIndexMustBeLessThanToIndex, fromIndex, toIndex)
+	}
+	return nil
+}
+
+// Empty returns true if the rope is empty.
+func (r *Rope[T]) Empty() bool {
+	return r.root == nil
+}
+
+// Size returns the number of elements in the rope.
+func (r *Rope[T]) Size() int {
+	return size(r.root)
+}
+
+// Clear removes all elements from the rope.
+func (r *Rope[T]) Clear() {
+	r.root = nil
+}
+
+// Append adds an element to the end of the rope.
+func (r *Rope[T]) Append(element T) {
+	_ = r.Insert(r.Size(), element)
+}
+
+// Prepend adds an element to the front of the rope.
+func (r *Rope[T]) Prepend(element T) {
+	_ = r.Insert(0, element)
+}
+
+// Insert inserts an element at the specified index.
+func (r *Rope[T]) Insert(index int, element T) error {
+	if err := r.checkIndex(index, r.Size()); err != nil {
+		return err
+	}
+	if path, offset := r.locate(index, true); path != nil {
+		if n := path[len(path)-1]; len(n.chunk) < chunkSize {
+			var zero T
+			n.chunk = append(n.chunk, zero)
+			copy(n.chunk[offset+1:], n.chunk[offset:])
+			n.chunk[offset] = element
+			for _, p := range path {
+				p.size++
+			}
+			return nil
+		}
+	}
+	return r.InsertAll(index, []T{element})
+}
+
+// InsertAll inserts multiple elements at a specific position in the rope.
+func (r *Rope[T]) InsertAll(index int, elements []T) error {
+	if err := r.checkIndex(index, r.Size()); err != nil {
+		return err
+	}
+	first, rest := r.split(r.root, index)
+	r.root = merge(merge(first, r.build(elements)), rest)
+	return nil
+}
+
+// Get retrieves an element at a specific position in the rope.
+func (r *Rope[T]) Get(index int) (element T, err error) {
+	if err := r.checkIndex(index, r.Size()-1); err != nil {
+		return element, err
+	}
+	path, offset := r.locate(index, false)
+	return path[len(path)-1].chunk[offset], nil
+}
+
+// Set replaces the element at the specified index.
+func (r *Rope[T]) Set(index int, element T) error {
+	if err := r.checkIndex(index, r.Size()-1); err != nil {
+		return err
+	}
+	path, offset := r.locate(index, false)
+	path[len(path)-1].chunk[offset] = element
+	return nil
+}
+
+// Remove removes the element at the specified index.
+func (r *Rope[T]) Remove(index int) error {
+	if err := r.checkIndex(index, r.Size()-1); err != nil {
+		return err
+	}
+	path, offset := r.locate(index, false)
+	if n := path[len(path)-1]; len(n.chunk) > 1 {
+		copy(n.chunk[offset:], n.chunk[offset+1:])
+		var zero T
+		n.chunk[len(n.chunk)-1] = zero
+		n.chunk = n.chunk[:len(n.chunk)-1]
+		for _, p := range path {
+			p.size--
+		}
+		return nil
+	}
+	return r.RemoveRange(index, index+1)
+}
+
+// RemoveRange removes the elements whose index is between fromIndex, inclusive, and toIndex, exclusive.
+func (r *Rope[T]) RemoveRange(fromIndex int, toIndex int) error {
+	if err := r.checkRange(fromIndex, toIndex); err != nil {
+		return err
+	}
+	first, rest := r.split(r.root, fromIndex)
+	_, last := r.split(rest, toIndex-fromIndex)
+	r.root = merge(first, last)
+	return nil
+}
+
+// SubList returns a copy of the elements between fromIndex, inclusive, and toIndex, exclusive.
+func (r *Rope[T]) SubList(fromIndex int, toIndex int) ([]T, error) {
+	if err := r.checkRange(fromIndex, toIndex); err != nil {
+		return nil, err
+	}
+	elements := make([]T, 0, toIndex-fromIndex)
+	r.each(r.root, 0, fromIndex, toIndex, func(chunk []T) {
+		elements = append(elements, chunk...)
+	})
+	return elements, nil
+}
+
+// each calls fn, in order, with the parts of the chunks of the subtree of n (whose first element has
+// index offset) that lie between from and to.
+func (r *Rope[T]) each(n *node[T], offset, from, to int, fn func(chunk []T)) {
+	if n == nil || from >= to || to <= offset || offset+n.size <= from {
+		return
+	}
+	r.each(n.left, offset, from, to, fn)
+	start := offset + size(n.left)
+	lo, hi := from-start, to-start
+	if lo < 0 {
+		lo = 0
+	}
+	if hi > len(n.chunk) {
+		hi = len(n.chunk)
+	}
+	if lo < hi {
+		fn(n.chunk[lo:hi])
+	}
+	r.each(n.right, start+len(n.chunk), from, to, fn)
+}
+
+// Values returns a copy of all elements in order.
+func (r *Rope[T]) Values() []T {
+	elements, _ := r.SubList(0, r.Size())
+	return elements
+}
+
+// IndexOf finds the first occurrence of an element in the rope, returning its index.
+func (r *Rope[T]) IndexOf(element T) int {
+	index, found := 0, -1
+	var visit func(n *node[T]) bool
+	visit = func(n *node[T]) bool {
+		if n == nil {
+			return true
+		}
+		if !visit(n.left) {
+			return false
+		}
+		for i, v := range n.chunk {
+			if r.cmp(v, element) == 0 {
+				found = index + i
+				return false
+			}
+		}
+		index += len(n.chunk)
+		return visit(n.right)
+	}
+	visit(r.root)
+	return found
+}
+
+// LastIndexOf finds the last occurrence of an element in the rope, returning its index.
+func (r *Rope[T]) LastIndexOf(element T) int {
+	end, found := r.Size(), -1
+	var visit func(n *node[T]) bool
+	visit = func(n *node[T]) bool {
+		if n == nil {
+			return true
+		}
+		if !visit(n.right) {
+			return false
+		}
+		end -= len(n.chunk)
+		for i := len(n.chunk) - 1; i >= 0; i-- {
+			if r.cmp(n.chunk[i], element) == 0 {
+				found = end + i
+				return false
+			}
+		}
+		return visit(n.left)
+	}
+	visit(r.root)
+	return found
+}
+
+// Concat moves all elements of other to the end of r, leaving other empty.
+func (r *Rope[T]) Concat(other *Rope[T]) {
+	r.root = merge(r.root, other.root)
+	other.root = nil
+}
+
+// Split moves the elements from index on into a new rope and returns it.
+func (r *Rope[T]) Split(index int) (*Rope[T], error) {
+	if err := r.checkIndex(index, r.Size()); err != nil {
+		return nil, err
+	}
+	var rest *node[T]
+	r.root, rest = r.split(r.root, index)
+	return &Rope[T]{root: rest, cmp: r.cmp, rng: r.rng}, nil
+}
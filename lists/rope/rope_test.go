@@ -0,0 +1,148 @@
+package rope
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRope(t *testing.T) {
+	r := New(common.IntComparator, []int{1, 2, 3}, WithSeed[int](1))
+	assert.Equal(t, 3, r.Size())
+
+	r.Append(4)
+	r.Prepend(0)
+	assert.NoError(t, r.Insert(2, 9))
+	assert.NoError(t, r.InsertAll(6, []int{5, 6}))
+	assert.Equal(t, []int{0, 1, 9, 2, 3, 4, 5, 6}, r.Values())
+
+	v, err := r.Get(2)
+	assert.NoError(t, err)
+	assert.Equal(t, 9, v)
+	assert.NoError(t, r.Set(2, 1))
+	assert.Equal(t, 1, r.IndexOf(1))
+	assert.Equal(t, 2, r.LastIndexOf(1))
+	assert.Equal(t, -1, r.IndexOf(7))
+
+	assert.NoError(t, r.Remove(0))
+	assert.NoError(t, r.RemoveRange(1, 3))
+	assert.Equal(t, []int{1, 3, 4, 5, 6}, r.Values())
+
+	sub, err := r.SubList(1, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5}, sub)
+
+	_, err = r.Get(5)
+	assert.True(t, errors.Is(err, ErrIndexOutOfRange))
+	assert.True(t, errors.Is(r.Insert(6, 0), ErrIndexOutOfRange))
+	assert.True(t, errors.Is(r.Remove(-1), ErrIndexOutOfRange))
+	assert.True(t, errors.Is(r.RemoveRange(3, 2), ErrFormIndexMustBeLessThanToIndex))
+	_, err = r.SubList(0, 6)
+	assert.True(t, errors.Is(err, ErrIndexOutOfRange))
+
+	rest, err := r.Split(2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, r.Values())
+	assert.Equal(t, []int{4, 5, 6}, rest.Values())
+	rest.Concat(r)
+	assert.True(t, r.Empty())
+	assert.Equal(t, []int{4, 5, 6, 1, 3}, rest.Values())
+
+	rest.Clear()
+	assert.True(t, rest.Empty())
+	assert.Empty(t, rest.Values())
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	r := New[int](common.IntComparator, nil, WithSeed[int](1))
+	var want []int
+
+	for i := 0; i < 5000; i++ {
+		switch op := rng.Intn(10); {
+		case op < 4:
+			index := rng.Intn(len(want) + 1)
+			assert.NoError(t, r.Insert(index, i))
+			want = append(want[:index], append([]int{i}, want[index:]...)...)
+		case op < 5:
+			index := rng.Intn(len(want) + 1)
+			elements := make([]int, rng.Intn(300))
+			for j := range elements {
+				elements[j] = -j
+			}
+			assert.NoError(t, r.InsertAll(index, elements))
+			want = append(want[:index], append(elements, want[index:]...)...)
+		case op < 7 && len(want) > 0:
+			index := rng.Intn(len(want))
+			assert.NoError(t, r.Remove(index))
+			want = append(want[:index], want[index+1:]...)
+		case op < 8:
+			from := rng.Intn(len(want) + 1)
+			to := from + rng.Intn(len(want)-from+1)
+			assert.NoError(t, r.RemoveRange(from, to))
+			want = append(want[:from], want[to:]...)
+		case op < 9:
+			// Split and concatenate again, possibly in a new place.
+			index := rng.Intn(len(want) + 1)
+			rest, err := r.Split(index)
+			assert.NoError(t, err)
+			rest.Concat(r)
+			r = rest
+			want = append(append([]int(nil), want[index:]...), want[:index]...)
+		default:
+			if len(want) > 0 {
+				index := rng.Intn(len(want))
+				v, err := r.Get(index)
+				assert.NoError(t, err)
+				assert.Equal(t, want[index], v)
+			}
+		}
+		assert.Equal(t, len(want), r.Size())
+	}
+	assert.Equal(t, want, r.Values())
+	from := len(want) / 3
+	sub, err := r.SubList(from, 2*from)
+	assert.NoError(t, err)
+	assert.Equal(t, want[from:2*from], sub)
+}
+
+func TestText(t *testing.T) {
+	text := NewText("hello world", WithSeed[rune](1))
+	assert.Equal(t, 11, text.Len())
+
+	assert.NoError(t, text.Insert(5, ","))
+	assert.NoError(t, text.Insert(text.Len(), "! ☺"))
+	assert.Equal(t, "hello, world! ☺", text.String())
+	r, err := text.RuneAt(14)
+	assert.NoError(t, err)
+	assert.Equal(t, '☺', r)
+
+	assert.NoError(t, text.Delete(0, 7))
+	assert.Equal(t, "world! ☺", text.String())
+	s, err := text.Slice(0, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, "world", s)
+	_, err = text.Slice(5, 100)
+	assert.True(t, errors.Is(err, ErrIndexOutOfRange))
+
+	rest, err := text.Split(5)
+	assert.NoError(t, err)
+	assert.Equal(t, "world", text.String())
+	assert.Equal(t, "! ☺", rest.String())
+	rest.Concat(text)
+	assert.Equal(t, "! ☺world", rest.String())
+	assert.Equal(t, 0, text.Len())
+
+	// Typing into the middle of a large buffer.
+	big := NewText(strings.Repeat("ab", 50000))
+	for i := 0; i < 1000; i++ {
+		assert.NoError(t, big.Insert(50000+i, "x"))
+	}
+	s, _ = big.Slice(49999, 50002)
+	assert.Equal(t, "bxx", s)
+	assert.Equal(t, 101000, big.Len())
+}
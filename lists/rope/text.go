@@ -0,0 +1,83 @@
+package rope
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Text is a text buffer backed by a rope of runes. Positions count runes, not bytes.
+type Text struct {
+	runes *Rope[rune]
+}
+
+// NewText returns a text buffer holding s.
+func NewText(s string, opts ...Option[rune]) *Text {
+	return &Text{runes: New(compareRunes, []rune(s), opts...)}
+}
+
+func compareRunes(a, b rune) int8 {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// Len returns the number of runes.
+func (t *Text) Len() int {
+	return t.runes.Size()
+}
+
+// RuneAt returns the rune at position pos.
+func (t *Text) RuneAt(pos int) (rune, error) {
+	return t.runes.Get(pos)
+}
+
+// Insert inserts s before position pos. Pos may equal Len to append.
+func (t *Text) Insert(pos int, s string) error {
+	if utf8.RuneCountInString(s) == 1 {
+		r, _ := utf8.DecodeRuneInString(s)
+		return t.runes.Insert(pos, r)
+	}
+	return t.runes.InsertAll(pos, []rune(s))
+}
+
+// Delete removes the runes between from, inclusive, and to, exclusive.
+func (t *Text) Delete(from, to int) error {
+	return t.runes.RemoveRange(from, to)
+}
+
+// Slice returns the text between from, inclusive, and to, exclusive.
+func (t *Text) Slice(from, to int) (string, error) {
+	if err := t.runes.checkRange(from, to); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	t.runes.each(t.runes.root, 0, from, to, func(chunk []rune) {
+		for _, r := range chunk {
+			b.WriteRune(r)
+		}
+	})
+	return b.String(), nil
+}
+
+// String returns the whole text.
+func (t *Text) String() string {
+	s, _ := t.Slice(0, t.Len())
+	return s
+}
+
+// Concat appends the text of other to t, leaving other empty.
+func (t *Text) Concat(other *Text) {
+	t.runes.Concat(other.runes)
+}
+
+// Split moves the text from position pos on into a new buffer and returns it.
+func (t *Text) Split(pos int) (*Text, error) {
+	rest, err := t.runes.Split(pos)
+	if err != nil {
+		return nil, err
+	}
+	return &Text{runes: rest}, nil
+}
@@ -0,0 +1,148 @@
+// Package bimap implements a bidirectional map.
+//
+// A bidirectional map keeps a one-to-one relation between keys and values, so
+// values are unique as well as keys and a value can be used to look up its key.
+// Putting a pair whose value is already bound to another key replaces that binding.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Bidirectional_map
+package bimap
+
+import (
+	"github.com/kwstars/goads/maps"
+)
+
+// Verify that Map implements the maps.Map interface.
+var _ maps.Map[int, string] = (*Map[int, string])(nil)
+
+// Option is a function than can be passed to New to customize the Map.
+type Option[K comparable, V comparable] func(*Map[K, V])
+
+// WithInitialCapacity sets the initial capacity of both directions of the Map.
+func WithInitialCapacity[K comparable, V comparable](capacity int) Option[K, V] {
+	return func(m *Map[K, V]) {
+		m.forward = make(map[K]V, capacity)
+		m.inverse = make(map[V]K, capacity)
+	}
+}
+
+// Map is a bidirectional map in which both keys and values are unique.
+type Map[K comparable, V comparable] struct {
+	forward map[K]V // key -> value
+	inverse map[V]K // value -> key
+}
+
+// New returns a new bidirectional map.
+func New[K comparable, V comparable](opts ...Option[K, V]) *Map[K, V] {
+	m := &Map[K, V]{}
+
+	for _, option := range opts {
+		option(m)
+	}
+
+	if m.forward == nil {
+		m.forward = make(map[K]V)
+		m.inverse = make(map[V]K)
+	}
+
+	return m
+}
+
+// Put binds key to value.
+// Any previous value of key and any previous key of value are unbound first,
+// so the map stays one-to-one.
+func (m *Map[K, V]) Put(key K, value V) {
+	if oldValue, ok := m.forward[key]; ok {
+		delete(m.inverse, oldValue)
+	}
+	if oldKey, ok := m.inverse[value]; ok {
+		delete(m.forward, oldKey)
+	}
+	m.forward[key] = value
+	m.inverse[value] = key
+}
+
+// Get returns the value associated with the given key.
+func (m *Map[K, V]) Get(key K) (value V, found bool) {
+	value, found = m.forward[key]
+	return value, found
+}
+
+// GetKey returns the key associated with the given value.
+func (m *Map[K, V]) GetKey(value V) (key K, found bool) {
+	key, found = m.inverse[value]
+	return key, found
+}
+
+// Remove removes the pair associated with the given key.
+func (m *Map[K, V]) Remove(key K) {
+	if value, ok := m.forward[key]; ok {
+		delete(m.forward, key)
+		delete(m.inverse, value)
+	}
+}
+
+// RemoveValue removes the pair associated with the given value.
+func (m *Map[K, V]) RemoveValue(value V) {
+	if key, ok := m.inverse[value]; ok {
+		delete(m.inverse, value)
+		delete(m.forward, key)
+	}
+}
+
+// Inverse returns the value-to-key view of the map.
+// The view shares its storage with m, so changes made through either of them are visible in both.
+func (m *Map[K, V]) Inverse() *Map[V, K] {
+	return &Map[V, K]{forward: m.inverse, inverse: m.forward}
+}
+
+// Empty returns true if the map is empty, false otherwise.
+func (m *Map[K, V]) Empty() bool {
+	return len(m.forward) == 0
+}
+
+// Size returns the number of pairs in the map.
+func (m *Map[K, V]) Size() int {
+	return len(m.forward)
+}
+
+// Clear removes all pairs from the map.
+func (m *Map[K, V]) Clear() {
+	for k := range m.forward {
+		delete(m.forward, k)
+	}
+	for v := range m.inverse {
+		delete(m.inverse, v)
+	}
+}
+
+// Contains returns true if the map holds the given key.
+func (m *Map[K, V]) Contains(key K) bool {
+	_, found := m.forward[key]
+	return found
+}
+
+// ContainsValue returns true if the map holds the given value.
+func (m *Map[K, V]) ContainsValue(value V) bool {
+	_, found := m.inverse[value]
+	return found
+}
+
+// Keys returns all keys of the map in no particular order.
+func (m *Map[K, V]) Keys() []K {
+	keys := make([]K, 0, len(m.forward))
+	for k := range m.forward {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Values returns all values of the map in no particular order.
+func (m *Map[K, V]) Values() []V {
+	values := make([]V, 0, len(m.inverse))
+	for v := range m.inverse {
+		values = append(values, v)
+	}
+	return values
+}
@@ -0,0 +1,118 @@
+package bimap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMap_PutAndGet(t *testing.T) {
+	m := New[string, int]()
+	m.Put("one", 1)
+	m.Put("two", 2)
+
+	v, found := m.Get("one")
+	assert.True(t, found)
+	assert.Equal(t, 1, v)
+
+	k, found := m.GetKey(2)
+	assert.True(t, found)
+	assert.Equal(t, "two", k)
+
+	_, found = m.GetKey(3)
+	assert.False(t, found)
+	assert.Equal(t, 2, m.Size())
+}
+
+func TestMap_PutKeepsOneToOne(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		value       int
+		wantForward map[string]int
+		wantInverse map[int]string
+	}{
+		{
+			name:        "rebind existing key to a new value",
+			key:         "one",
+			value:       10,
+			wantForward: map[string]int{"one": 10, "two": 2},
+			wantInverse: map[int]string{10: "one", 2: "two"},
+		},
+		{
+			name:        "bind existing value to a new key",
+			key:         "uno",
+			value:       1,
+			wantForward: map[string]int{"uno": 1, "two": 2},
+			wantInverse: map[int]string{1: "uno", 2: "two"},
+		},
+		{
+			name:        "bind existing key to the value of another key",
+			key:         "one",
+			value:       2,
+			wantForward: map[string]int{"one": 2},
+			wantInverse: map[int]string{2: "one"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New[string, int](WithInitialCapacity[string, int](4))
+			m.Put("one", 1)
+			m.Put("two", 2)
+
+			m.Put(tt.key, tt.value)
+			assert.Equal(t, tt.wantForward, m.forward)
+			assert.Equal(t, tt.wantInverse, m.inverse)
+		})
+	}
+}
+
+func TestMap_Remove(t *testing.T) {
+	m := New[string, int]()
+	m.Put("one", 1)
+	m.Put("two", 2)
+
+	m.Remove("one")
+	_, found := m.GetKey(1)
+	assert.False(t, found)
+
+	m.RemoveValue(2)
+	_, found = m.Get("two")
+	assert.False(t, found)
+	assert.True(t, m.Empty())
+
+	m.Remove("missing")
+	m.RemoveValue(42)
+	assert.Equal(t, 0, m.Size())
+}
+
+func TestMap_Inverse(t *testing.T) {
+	m := New[string, int]()
+	m.Put("one", 1)
+
+	inv := m.Inverse()
+	k, found := inv.Get(1)
+	assert.True(t, found)
+	assert.Equal(t, "one", k)
+
+	inv.Put(2, "two")
+	v, found := m.Get("two")
+	assert.True(t, found)
+	assert.Equal(t, 2, v)
+
+	inv.Remove(1)
+	_, found = m.Get("one")
+	assert.False(t, found)
+	assert.Equal(t, 1, m.Size())
+}
+
+func TestMap_Clear(t *testing.T) {
+	m := New[string, int]()
+	m.Put("one", 1)
+	m.Put("two", 2)
+
+	m.Clear()
+	assert.True(t, m.Empty())
+	assert.Empty(t, m.inverse)
+}
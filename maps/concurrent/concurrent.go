@@ -0,0 +1,235 @@
+// Package concurrent implements a hash map that is safe for concurrent use.
+//
+// The keys are spread over a fixed number of shards by hash, and every shard is a builtin map
+// guarded by its own read-write mutex (lock striping). Goroutines working on keys of different
+// shards never contend with each other, and the atomic operations (LoadOrStore, Compute,
+// CompareAndSwap, ...) only hold the lock of the shard that owns the key.
+//
+// References: https://en.wikipedia.org/wiki/Lock_(computer_science)#Granularity
+package concurrent
+
+import (
+	"sync"
+
+	"github.com/kwstars/goads/maps"
+	"github.com/kwstars/goads/pkg/common"
+)
+
+// Verify that Map implements the maps.Map interface.
+var _ maps.Map[int, int] = (*Map[int, int])(nil)
+
+const defaultShardCount = 32
+
+// Option is a function than can be passed to New to customize the Map.
+type Option[K comparable, V any] func(*Map[K, V])
+
+// WithShardCount sets the number of shards. It is rounded up to a power of two.
+func WithShardCount[K comparable, V any](count int) Option[K, V] {
+	return func(m *Map[K, V]) {
+		n := 1
+		for n < count {
+			n <<= 1
+		}
+		m.shards = make([]shard[K, V], n)
+	}
+}
+
+// shard is a builtin map guarded by its own lock.
+type shard[K comparable, V any] struct {
+	sync.RWMutex
+	m map[K]V
+}
+
+// Map is a sharded hash map that is safe for concurrent use.
+type Map[K comparable, V any] struct {
+	shards []shard[K, V]
+	hasher common.Hasher[K]
+}
+
+// New returns a new concurrent map that distributes keys over shards with hasher.
+func New[K comparable, V any](hasher common.Hasher[K], opts ...Option[K, V]) *Map[K, V] {
+	m := &Map[K, V]{hasher: hasher}
+
+	for _, option := range opts {
+		option(m)
+	}
+
+	if m.shards == nil {
+		m.shards = make([]shard[K, V], defaultShardCount)
+	}
+	for i := range m.shards {
+		m.shards[i].m = make(map[K]V)
+	}
+
+	return m
+}
+
+// shardFor returns the shard that owns key.
+func (m *Map[K, V]) shardFor(key K) *shard[K, V] {
+	return &m.shards[m.hasher(key)&uint64(len(m.shards)-1)]
+}
+
+// Put inserts a key-value pair into the map.
+func (m *Map[K, V]) Put(key K, value V) {
+	s := m.shardFor(key)
+	s.Lock()
+	s.m[key] = value
+	s.Unlock()
+}
+
+// Get returns the value associated with the given key.
+func (m *Map[K, V]) Get(key K) (value V, found bool) {
+	s := m.shardFor(key)
+	s.RLock()
+	value, found = s.m[key]
+	s.RUnlock()
+	return value, found
+}
+
+// Remove removes the key-value pair associated with the given key.
+func (m *Map[K, V]) Remove(key K) {
+	s := m.shardFor(key)
+	s.Lock()
+	delete(s.m, key)
+	s.Unlock()
+}
+
+// Contains returns true if the map holds the given key.
+func (m *Map[K, V]) Contains(key K) bool {
+	_, found := m.Get(key)
+	return found
+}
+
+// Keys returns a snapshot of all keys in no particular order.
+func (m *Map[K, V]) Keys() []K {
+	var keys []K
+	m.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Values returns a snapshot of all values in no particular order.
+func (m *Map[K, V]) Values() []V {
+	var values []V
+	m.Range(func(_ K, value V) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// LoadOrStore returns the existing value for key if present.
+// Otherwise, it stores and returns the given value.
+// The loaded result is true if the value was loaded, false if stored.
+func (m *Map[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	s := m.shardFor(key)
+	s.Lock()
+	defer s.Unlock()
+	if actual, loaded = s.m[key]; loaded {
+		return actual, true
+	}
+	s.m[key] = value
+	return value, false
+}
+
+// LoadAndDelete removes the value for key, returning the previous value if any.
+func (m *Map[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	s := m.shardFor(key)
+	s.Lock()
+	defer s.Unlock()
+	if value, loaded = s.m[key]; loaded {
+		delete(s.m, key)
+	}
+	return value, loaded
+}
+
+// Compute atomically replaces the value of key with the result of fn.
+// fn receives the current value and whether it was present; if fn returns false as second result
+// the key is removed. Compute returns the new value and whether the key is now present.
+// fn runs with the shard locked, so it must not access the map.
+func (m *Map[K, V]) Compute(key K, fn func(old V, ok bool) (V, bool)) (V, bool) {
+	s := m.shardFor(key)
+	s.Lock()
+	defer s.Unlock()
+	old, ok := s.m[key]
+	value, keep := fn(old, ok)
+	if !keep {
+		delete(s.m, key)
+		var zero V
+		return zero, false
+	}
+	s.m[key] = value
+	return value, true
+}
+
+// CompareAndSwap swaps the old and new values for key if the value stored in the map is equal to old.
+// The old value must be of a comparable type, otherwise CompareAndSwap panics.
+func (m *Map[K, V]) CompareAndSwap(key K, old, new V) bool {
+	s := m.shardFor(key)
+	s.Lock()
+	defer s.Unlock()
+	current, ok := s.m[key]
+	if !ok || any(current) != any(old) {
+		return false
+	}
+	s.m[key] = new
+	return true
+}
+
+// Range calls fn for each key-value pair in the map in no particular order.
+// Each shard is copied under its read lock and fn runs on the copy, so fn sees a consistent
+// snapshot of every shard and may modify the map. If fn returns false, Range stops the iteration.
+func (m *Map[K, V]) Range(fn func(key K, value V) bool) {
+	type entry struct {
+		key   K
+		value V
+	}
+	var snapshot []entry
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.RLock()
+		snapshot = snapshot[:0]
+		for k, v := range s.m {
+			snapshot = append(snapshot, entry{k, v})
+		}
+		s.RUnlock()
+
+		for _, e := range snapshot {
+			if !fn(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Empty returns true if the map is empty, false otherwise.
+func (m *Map[K, V]) Empty() bool {
+	return m.Size() == 0
+}
+
+// Size returns the number of elements in the map.
+// Shards are counted one after another, so concurrent updates may or may not be reflected.
+func (m *Map[K, V]) Size() int {
+	size := 0
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.RLock()
+		size += len(s.m)
+		s.RUnlock()
+	}
+	return size
+}
+
+// Clear removes all elements from the map.
+func (m *Map[K, V]) Clear() {
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.Lock()
+		for k := range s.m {
+			delete(s.m, k)
+		}
+		s.Unlock()
+	}
+}
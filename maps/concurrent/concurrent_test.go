@@ -0,0 +1,168 @@
+package concurrent
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWithShardCount(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+		want  int
+	}{
+		{name: "power of two", count: 8, want: 8},
+		{name: "rounded up", count: 5, want: 8},
+		{name: "single shard", count: 0, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New[int, int](common.IntHasher, WithShardCount[int, int](tt.count))
+			assert.Len(t, m.shards, tt.want)
+		})
+	}
+}
+
+func TestMap_Basic(t *testing.T) {
+	m := New[string, int](common.StringHasher)
+	assert.True(t, m.Empty())
+
+	m.Put("a", 1)
+	m.Put("b", 2)
+	v, found := m.Get("a")
+	assert.True(t, found)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, 2, m.Size())
+
+	m.Remove("a")
+	_, found = m.Get("a")
+	assert.False(t, found)
+
+	v, loaded := m.LoadAndDelete("b")
+	assert.True(t, loaded)
+	assert.Equal(t, 2, v)
+	_, loaded = m.LoadAndDelete("b")
+	assert.False(t, loaded)
+	assert.True(t, m.Empty())
+}
+
+func TestMap_LoadOrStore(t *testing.T) {
+	m := New[string, int](common.StringHasher)
+
+	actual, loaded := m.LoadOrStore("a", 1)
+	assert.False(t, loaded)
+	assert.Equal(t, 1, actual)
+
+	actual, loaded = m.LoadOrStore("a", 2)
+	assert.True(t, loaded)
+	assert.Equal(t, 1, actual)
+}
+
+func TestMap_Compute(t *testing.T) {
+	m := New[string, int](common.StringHasher)
+	inc := func(old int, ok bool) (int, bool) { return old + 1, true }
+
+	v, ok := m.Compute("a", inc)
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	v, _ = m.Compute("a", inc)
+	assert.Equal(t, 2, v)
+
+	_, ok = m.Compute("a", func(old int, ok bool) (int, bool) { return 0, false })
+	assert.False(t, ok)
+	_, found := m.Get("a")
+	assert.False(t, found)
+}
+
+func TestMap_CompareAndSwap(t *testing.T) {
+	m := New[string, int](common.StringHasher)
+	assert.False(t, m.CompareAndSwap("a", 0, 1))
+
+	m.Put("a", 1)
+	assert.False(t, m.CompareAndSwap("a", 2, 3))
+	assert.True(t, m.CompareAndSwap("a", 1, 3))
+	v, _ := m.Get("a")
+	assert.Equal(t, 3, v)
+}
+
+func TestMap_RangeAndClear(t *testing.T) {
+	m := New[int, int](common.IntHasher, WithShardCount[int, int](4))
+	for i := 0; i < 100; i++ {
+		m.Put(i, i*i)
+	}
+
+	got := make(map[int]int)
+	m.Range(func(k, v int) bool {
+		got[k] = v
+		// Modifying the map during Range must not deadlock and must not affect the snapshot.
+		m.Put(k, -1)
+		return true
+	})
+	assert.Len(t, got, 100)
+	for k, v := range got {
+		assert.Equal(t, k*k, v)
+	}
+
+	calls := 0
+	m.Range(func(k, v int) bool {
+		calls++
+		return false
+	})
+	assert.Equal(t, 1, calls)
+
+	m.Clear()
+	assert.True(t, m.Empty())
+}
+
+func TestMap_ConcurrentStress(t *testing.T) {
+	const (
+		goroutines = 16
+		iterations = 2000
+		keys       = 64
+	)
+	m := New[int, int](common.IntHasher, WithShardCount[int, int](8))
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				key := (g + i) % keys
+				m.Compute(key, func(old int, ok bool) (int, bool) { return old + 1, true })
+				for {
+					old, _ := m.Get(-key - 1)
+					if m.CompareAndSwap(-key-1, old, old+1) {
+						break
+					}
+					if _, loaded := m.LoadOrStore(-key-1, 1); !loaded {
+						break
+					}
+				}
+				m.Get(key)
+				if i%100 == 0 {
+					m.Range(func(k, v int) bool { return true })
+					m.Size()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	computed, swapped := 0, 0
+	m.Range(func(k, v int) bool {
+		if k >= 0 {
+			computed += v
+		} else {
+			swapped += v
+		}
+		return true
+	})
+	assert.Equal(t, goroutines*iterations, computed)
+	assert.Equal(t, goroutines*iterations, swapped)
+	assert.Equal(t, 2*keys, m.Size())
+}
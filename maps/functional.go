@@ -0,0 +1,91 @@
+package maps
+
+// Entries returns all key-value pairs of m, in the order of m.Keys.
+// Keys that are removed concurrently between Keys and Get are skipped.
+func Entries[K comparable, V any](m Map[K, V]) []Entry[K, V] {
+	keys := m.Keys()
+	entries := make([]Entry[K, V], 0, len(keys))
+	for _, k := range keys {
+		if v, found := m.Get(k); found {
+			entries = append(entries, Entry[K, V]{Key: k, Value: v})
+		}
+	}
+	return entries
+}
+
+// PutIfAbsent stores value under key unless key is already present.
+// It returns the value stored under key afterwards and true if value was inserted.
+// The check and the insertion are two separate calls, so it is not atomic even for concurrent maps.
+func PutIfAbsent[K comparable, V any](m Map[K, V], key K, value V) (actual V, inserted bool) {
+	if v, found := m.Get(key); found {
+		return v, false
+	}
+	m.Put(key, value)
+	return value, true
+}
+
+// Merge copies all pairs of src into dst and returns dst.
+// When a key exists in both maps, resolve decides the stored value; a nil resolve lets src win.
+func Merge[K comparable, V any](dst, src Map[K, V], resolve func(key K, dstValue, srcValue V) V) Map[K, V] {
+	for _, e := range Entries(src) {
+		if resolve != nil {
+			if old, found := dst.Get(e.Key); found {
+				dst.Put(e.Key, resolve(e.Key, old, e.Value))
+				continue
+			}
+		}
+		dst.Put(e.Key, e.Value)
+	}
+	return dst
+}
+
+// Filter puts the pairs of src that satisfy predicate into dst and returns dst.
+// Passing src as dst is not supported; use RemoveIf to filter in place.
+func Filter[K comparable, V any](dst, src Map[K, V], predicate func(key K, value V) bool) Map[K, V] {
+	for _, e := range Entries(src) {
+		if predicate(e.Key, e.Value) {
+			dst.Put(e.Key, e.Value)
+		}
+	}
+	return dst
+}
+
+// RemoveIf removes all pairs of m that satisfy predicate and returns the number of removed pairs.
+func RemoveIf[K comparable, V any](m Map[K, V], predicate func(key K, value V) bool) int {
+	removed := 0
+	for _, e := range Entries(m) {
+		if predicate(e.Key, e.Value) {
+			m.Remove(e.Key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// MapValues puts every key of src into dst with its value transformed by fn, and returns dst.
+func MapValues[K comparable, V any, W any](dst Map[K, W], src Map[K, V], fn func(key K, value V) W) Map[K, W] {
+	for _, e := range Entries(src) {
+		dst.Put(e.Key, fn(e.Key, e.Value))
+	}
+	return dst
+}
+
+// Equal returns true if a and b hold the same keys with equal values.
+// Maps of different implementations can be compared.
+func Equal[K comparable, V comparable](a, b Map[K, V]) bool {
+	return EqualFunc(a, b, func(x, y V) bool { return x == y })
+}
+
+// EqualFunc is like Equal, but compares values with eq.
+func EqualFunc[K comparable, V1 any, V2 any](a Map[K, V1], b Map[K, V2], eq func(V1, V2) bool) bool {
+	if a.Size() != b.Size() {
+		return false
+	}
+	for _, e := range Entries(a) {
+		v, found := b.Get(e.Key)
+		if !found || !eq(e.Value, v) {
+			return false
+		}
+	}
+	return true
+}
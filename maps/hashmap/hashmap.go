@@ -69,3 +69,27 @@ func (m *Map[K, V]) Clear() {
 		delete(m.m, k)
 	}
 }
+
+// Contains returns true if the hash map holds the given key.
+func (m *Map[K, V]) Contains(key K) bool {
+	_, found := m.m[key]
+	return found
+}
+
+// Keys returns all keys of the hash map in no particular order.
+func (m *Map[K, V]) Keys() []K {
+	keys := make([]K, 0, len(m.m))
+	for k := range m.m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Values returns all values of the hash map in no particular order.
+func (m *Map[K, V]) Values() []V {
+	values := make([]V, 0, len(m.m))
+	for _, v := range m.m {
+		values = append(values, v)
+	}
+	return values
+}
@@ -10,4 +10,48 @@ type Map[K comparable, V any] interface {
 	Get(key K) (value V, found bool)
 	// Remove removes the key-value pair associated with the given key.
 	Remove(key K)
+	// Contains returns true if the map holds the given key.
+	Contains(key K) bool
+	// Keys returns all keys of the map. The order is defined by the implementation.
+	Keys() []K
+	// Values returns all values of the map. The order is defined by the implementation.
+	Values() []V
+}
+
+// Reader is the read-only part of Map. Immutable maps implement it too.
+type Reader[K comparable, V any] interface {
+	// Empty returns true if the map holds no pairs.
+	Empty() bool
+	// Size returns the number of pairs.
+	Size() int
+	// Get returns the value associated with the given key.
+	Get(key K) (value V, found bool)
+	// Contains returns true if the map holds the given key.
+	Contains(key K) bool
+	// Keys returns all keys of the map. The order is defined by the implementation.
+	Keys() []K
+	// Values returns all values of the map. The order is defined by the implementation.
+	Values() []V
+}
+
+// Entry is a key-value pair of a map.
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// SortedMap is a Map that keeps its keys in ascending order. Keys and Values return them in that order.
+type SortedMap[K comparable, V any] interface {
+	Map[K, V]
+	// Min returns the smallest key and its value.
+	Min() (key K, value V, found bool)
+	// Max returns the largest key and its value.
+	Max() (key K, value V, found bool)
+	// Floor returns the largest key less than or equal to the given key, and its value.
+	Floor(key K) (floor K, value V, found bool)
+	// Ceiling returns the smallest key greater than or equal to the given key, and its value.
+	Ceiling(key K) (ceiling K, value V, found bool)
+	// Range calls fn for each key-value pair in ascending key order.
+	// If fn returns false, Range stops the iteration.
+	Range(fn func(key K, value V) bool)
 }
@@ -0,0 +1,179 @@
+package maps_test
+
+import (
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/kwstars/goads/maps"
+	"github.com/kwstars/goads/maps/bimap"
+	"github.com/kwstars/goads/maps/concurrent"
+	"github.com/kwstars/goads/maps/hashmap"
+	"github.com/kwstars/goads/maps/robinhood"
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/trees/splaytree"
+	"github.com/kwstars/goads/trees/treap"
+	"github.com/stretchr/testify/assert"
+)
+
+// implementations returns a constructor for every maps.Map in the repository,
+// so that each helper is checked against all of them.
+func implementations() map[string]func() maps.Map[int, int] {
+	return map[string]func() maps.Map[int, int]{
+		"hashmap": func() maps.Map[int, int] { return hashmap.New[int, int]() },
+		"bimap":   func() maps.Map[int, int] { return bimap.New[int, int]() },
+		"robinhood": func() maps.Map[int, int] {
+			return robinhood.New[int, int](common.IntHasher, func(a, b int) bool { return a == b })
+		},
+		"concurrent": func() maps.Map[int, int] { return concurrent.New[int, int](common.IntHasher) },
+		"treap":      func() maps.Map[int, int] { return treap.New[int, int](common.IntComparator) },
+		"splaytree":  func() maps.Map[int, int] { return splaytree.New[int, int](common.IntComparator) },
+	}
+}
+
+// fill puts key -> key*10 for every key.
+func fill(m maps.Map[int, int], keys ...int) maps.Map[int, int] {
+	for _, k := range keys {
+		m.Put(k, k*10)
+	}
+	return m
+}
+
+func sorted(s []int) []int {
+	sort.Ints(s)
+	return s
+}
+
+func TestKeysValuesContains(t *testing.T) {
+	for name, newMap := range implementations() {
+		t.Run(name, func(t *testing.T) {
+			m := fill(newMap(), 3, 1, 2)
+			assert.Equal(t, []int{1, 2, 3}, sorted(m.Keys()))
+			assert.Equal(t, []int{10, 20, 30}, sorted(m.Values()))
+			assert.True(t, m.Contains(2))
+			assert.False(t, m.Contains(4))
+
+			empty := newMap()
+			assert.Empty(t, empty.Keys())
+			assert.Empty(t, empty.Values())
+		})
+	}
+}
+
+func TestEntries(t *testing.T) {
+	for name, newMap := range implementations() {
+		t.Run(name, func(t *testing.T) {
+			entries := maps.Entries(fill(newMap(), 2, 1))
+			sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+			assert.Equal(t, []maps.Entry[int, int]{{Key: 1, Value: 10}, {Key: 2, Value: 20}}, entries)
+		})
+	}
+}
+
+// vanishingMap simulates a concurrent removal: Keys still lists gone, but Get no longer finds it.
+type vanishingMap struct {
+	maps.Map[int, int]
+	gone int
+}
+
+func (m vanishingMap) Get(key int) (int, bool) {
+	if key == m.gone {
+		return 0, false
+	}
+	return m.Map.Get(key)
+}
+
+func TestEntriesSkipsRemovedKeys(t *testing.T) {
+	m := vanishingMap{Map: fill(hashmap.New[int, int](), 1, 2), gone: 2}
+	assert.Equal(t, []maps.Entry[int, int]{{Key: 1, Value: 10}}, maps.Entries[int, int](m))
+}
+
+func TestPutIfAbsent(t *testing.T) {
+	for name, newMap := range implementations() {
+		t.Run(name, func(t *testing.T) {
+			m := fill(newMap(), 1)
+
+			actual, inserted := maps.PutIfAbsent(m, 1, 100)
+			assert.False(t, inserted)
+			assert.Equal(t, 10, actual)
+
+			actual, inserted = maps.PutIfAbsent(m, 2, 200)
+			assert.True(t, inserted)
+			assert.Equal(t, 200, actual)
+			assert.Equal(t, 2, m.Size())
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	for name, newMap := range implementations() {
+		t.Run(name, func(t *testing.T) {
+			dst := fill(newMap(), 1, 2)
+			src := newMap()
+			src.Put(2, 7)
+			src.Put(3, 8)
+
+			maps.Merge(dst, src, nil)
+			v, _ := dst.Get(2)
+			assert.Equal(t, 7, v)
+
+			dst = fill(newMap(), 1, 2)
+			maps.Merge(dst, src, func(key, dstValue, srcValue int) int { return dstValue + srcValue })
+			v, _ = dst.Get(2)
+			assert.Equal(t, 27, v)
+			v, _ = dst.Get(3)
+			assert.Equal(t, 8, v)
+			assert.Equal(t, 3, dst.Size())
+		})
+	}
+}
+
+func TestFilterAndRemoveIf(t *testing.T) {
+	even := func(key, value int) bool { return key%2 == 0 }
+	for name, newMap := range implementations() {
+		t.Run(name, func(t *testing.T) {
+			src := fill(newMap(), 1, 2, 3, 4)
+
+			dst := maps.Filter(newMap(), src, even)
+			assert.Equal(t, []int{2, 4}, sorted(dst.Keys()))
+			assert.Equal(t, 4, src.Size())
+
+			assert.Equal(t, 2, maps.RemoveIf(src, even))
+			assert.Equal(t, []int{1, 3}, sorted(src.Keys()))
+		})
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	for name, newMap := range implementations() {
+		t.Run(name, func(t *testing.T) {
+			src := fill(newMap(), 1, 2)
+			dst := maps.MapValues[int, int, string](hashmap.New[int, string](), src, func(key, value int) string {
+				return strconv.Itoa(key) + ":" + strconv.Itoa(value)
+			})
+			v, found := dst.Get(2)
+			assert.True(t, found)
+			assert.Equal(t, "2:20", v)
+			assert.Equal(t, 2, dst.Size())
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	impls := implementations()
+	for nameA, newA := range impls {
+		for nameB, newB := range impls {
+			t.Run(nameA+"/"+nameB, func(t *testing.T) {
+				assert.True(t, maps.Equal(newA(), newB()))
+				assert.True(t, maps.Equal(fill(newA(), 1, 2), fill(newB(), 2, 1)))
+				assert.False(t, maps.Equal(fill(newA(), 1, 2), fill(newB(), 1)))
+				assert.False(t, maps.Equal(fill(newA(), 1, 2), fill(newB(), 1, 3)))
+
+				b := fill(newB(), 1, 2)
+				b.Put(2, 0)
+				assert.False(t, maps.Equal(fill(newA(), 1, 2), b))
+				assert.True(t, maps.EqualFunc(fill(newA(), 1, 2), b, func(x, y int) bool { return x >= y }))
+			})
+		}
+	}
+}
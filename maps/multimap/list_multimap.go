@@ -0,0 +1,113 @@
+package multimap
+
+var _ MultiMap[int, int] = (*ListMultimap[int, int])(nil)
+
+// ListMultimap is a multimap that stores the values of a key in a list.
+// Values keep their insertion order and duplicates are allowed.
+type ListMultimap[K comparable, V comparable] struct {
+	m    map[K][]V
+	size int // size is the number of key-value pairs.
+}
+
+// NewListMultimap returns a new list-valued multimap.
+func NewListMultimap[K comparable, V comparable]() *ListMultimap[K, V] {
+	return &ListMultimap[K, V]{m: make(map[K][]V)}
+}
+
+// Put appends value to the values of key.
+func (mm *ListMultimap[K, V]) Put(key K, value V) {
+	mm.m[key] = append(mm.m[key], value)
+	mm.size++
+}
+
+// PutAll appends values to the values of key.
+func (mm *ListMultimap[K, V]) PutAll(key K, values ...V) {
+	if len(values) == 0 {
+		return
+	}
+	mm.m[key] = append(mm.m[key], values...)
+	mm.size += len(values)
+}
+
+// GetAll returns a copy of the values of key in insertion order.
+func (mm *ListMultimap[K, V]) GetAll(key K) []V {
+	values, ok := mm.m[key]
+	if !ok {
+		return nil
+	}
+	dst := make([]V, len(values))
+	copy(dst, values)
+	return dst
+}
+
+// Remove removes key together with all its values.
+func (mm *ListMultimap[K, V]) Remove(key K) {
+	mm.size -= len(mm.m[key])
+	delete(mm.m, key)
+}
+
+// RemoveValue removes the first occurrence of value from key.
+func (mm *ListMultimap[K, V]) RemoveValue(key K, value V) bool {
+	values := mm.m[key]
+	for i, v := range values {
+		if v != value {
+			continue
+		}
+		if len(values) == 1 {
+			delete(mm.m, key)
+		} else {
+			mm.m[key] = append(values[:i], values[i+1:]...)
+		}
+		mm.size--
+		return true
+	}
+	return false
+}
+
+// ContainsKey returns true if key has at least one value.
+func (mm *ListMultimap[K, V]) ContainsKey(key K) bool {
+	_, ok := mm.m[key]
+	return ok
+}
+
+// ContainsEntry returns true if value is associated with key.
+func (mm *ListMultimap[K, V]) ContainsEntry(key K, value V) bool {
+	for _, v := range mm.m[key] {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of values associated with key, duplicates included.
+func (mm *ListMultimap[K, V]) Count(key K) int {
+	return len(mm.m[key])
+}
+
+// Keys returns the distinct keys of the multimap in no particular order.
+func (mm *ListMultimap[K, V]) Keys() []K {
+	keys := make([]K, 0, len(mm.m))
+	for k := range mm.m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Empty returns true if the multimap holds no pairs.
+func (mm *ListMultimap[K, V]) Empty() bool {
+	return mm.size == 0
+}
+
+// Size returns the number of key-value pairs in the multimap.
+func (mm *ListMultimap[K, V]) Size() int {
+	return mm.size
+}
+
+// Clear removes all pairs from the multimap.
+func (mm *ListMultimap[K, V]) Clear() {
+	for k := range mm.m {
+		delete(mm.m, k)
+	}
+	mm.size = 0
+}
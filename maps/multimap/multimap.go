@@ -0,0 +1,38 @@
+// Package multimap implements maps that associate a key with several values.
+//
+// Two variants are provided:
+//   - ListMultimap keeps the values of a key in insertion order and allows duplicates.
+//   - SetMultimap keeps the values of a key unique, in no particular order.
+//
+// Size of a multimap is the number of key-value pairs, not the number of distinct keys.
+//
+// Structures are not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Multimap
+package multimap
+
+import "github.com/kwstars/goads/containers"
+
+// MultiMap is a map in which a key may be associated with several values.
+type MultiMap[K comparable, V comparable] interface {
+	containers.Container[K]
+	// Put associates value with key.
+	Put(key K, value V)
+	// PutAll associates all values with key.
+	PutAll(key K, values ...V)
+	// GetAll returns the values associated with key, or nil if there are none.
+	GetAll(key K) []V
+	// Remove removes key together with all its values.
+	Remove(key K)
+	// RemoveValue removes a single occurrence of value from key.
+	// It returns true if the pair was present.
+	RemoveValue(key K, value V) bool
+	// ContainsKey returns true if key has at least one value.
+	ContainsKey(key K) bool
+	// ContainsEntry returns true if value is associated with key.
+	ContainsEntry(key K, value V) bool
+	// Count returns the number of values associated with key.
+	Count(key K) int
+	// Keys returns the distinct keys of the multimap.
+	Keys() []K
+}
@@ -0,0 +1,93 @@
+package multimap
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListMultimap(t *testing.T) {
+	mm := NewListMultimap[string, int]()
+	assert.True(t, mm.Empty())
+
+	mm.Put("a", 1)
+	mm.PutAll("a", 2, 1)
+	mm.PutAll("b")
+	mm.Put("b", 3)
+
+	assert.Equal(t, []int{1, 2, 1}, mm.GetAll("a"))
+	assert.Nil(t, mm.GetAll("c"))
+	assert.Equal(t, 4, mm.Size())
+	assert.Equal(t, 3, mm.Count("a"))
+	assert.True(t, mm.ContainsKey("b"))
+	assert.True(t, mm.ContainsEntry("a", 2))
+	assert.False(t, mm.ContainsEntry("b", 2))
+
+	keys := mm.Keys()
+	sort.Strings(keys)
+	assert.Equal(t, []string{"a", "b"}, keys)
+
+	assert.True(t, mm.RemoveValue("a", 1))
+	assert.Equal(t, []int{2, 1}, mm.GetAll("a"))
+	assert.False(t, mm.RemoveValue("a", 5))
+
+	assert.True(t, mm.RemoveValue("b", 3))
+	assert.False(t, mm.ContainsKey("b"))
+	assert.Equal(t, 2, mm.Size())
+
+	mm.Remove("a")
+	assert.True(t, mm.Empty())
+
+	mm.PutAll("x", 1, 2, 3)
+	mm.Clear()
+	assert.Equal(t, 0, mm.Size())
+	assert.Empty(t, mm.Keys())
+}
+
+func TestListMultimap_GetAllReturnsCopy(t *testing.T) {
+	mm := NewListMultimap[string, int]()
+	mm.PutAll("a", 1, 2)
+
+	values := mm.GetAll("a")
+	values[0] = 100
+	assert.Equal(t, []int{1, 2}, mm.GetAll("a"))
+}
+
+func TestSetMultimap(t *testing.T) {
+	mm := NewSetMultimap[string, int]()
+	assert.True(t, mm.Empty())
+
+	mm.Put("a", 1)
+	mm.PutAll("a", 2, 1, 2)
+	mm.Put("b", 3)
+
+	values := mm.GetAll("a")
+	sort.Ints(values)
+	assert.Equal(t, []int{1, 2}, values)
+	assert.Nil(t, mm.GetAll("c"))
+	assert.Equal(t, 3, mm.Size())
+	assert.Equal(t, 2, mm.Count("a"))
+	assert.Equal(t, 0, mm.Count("c"))
+	assert.True(t, mm.ContainsEntry("a", 2))
+	assert.False(t, mm.ContainsEntry("c", 2))
+
+	keys := mm.Keys()
+	sort.Strings(keys)
+	assert.Equal(t, []string{"a", "b"}, keys)
+
+	assert.True(t, mm.RemoveValue("a", 1))
+	assert.False(t, mm.RemoveValue("a", 1))
+	assert.False(t, mm.RemoveValue("c", 1))
+	assert.True(t, mm.RemoveValue("a", 2))
+	assert.False(t, mm.ContainsKey("a"))
+	assert.Equal(t, 1, mm.Size())
+
+	mm.Remove("b")
+	assert.True(t, mm.Empty())
+
+	mm.PutAll("x", 1, 2, 3)
+	mm.Clear()
+	assert.Equal(t, 0, mm.Size())
+	assert.Empty(t, mm.Keys())
+}
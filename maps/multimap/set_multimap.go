@@ -0,0 +1,115 @@
+package multimap
+
+var _ MultiMap[int, int] = (*SetMultimap[int, int])(nil)
+
+// SetMultimap is a multimap that stores the values of a key in a set.
+// A value is associated with a key at most once and values have no particular order.
+type SetMultimap[K comparable, V comparable] struct {
+	m    map[K]map[V]struct{}
+	size int // size is the number of key-value pairs.
+}
+
+// NewSetMultimap returns a new set-valued multimap.
+func NewSetMultimap[K comparable, V comparable]() *SetMultimap[K, V] {
+	return &SetMultimap[K, V]{m: make(map[K]map[V]struct{})}
+}
+
+// Put associates value with key. It is a no-op if the pair is already present.
+func (mm *SetMultimap[K, V]) Put(key K, value V) {
+	values, ok := mm.m[key]
+	if !ok {
+		values = make(map[V]struct{})
+		mm.m[key] = values
+	}
+	if _, ok := values[value]; !ok {
+		values[value] = struct{}{}
+		mm.size++
+	}
+}
+
+// PutAll associates all values with key, skipping pairs that are already present.
+func (mm *SetMultimap[K, V]) PutAll(key K, values ...V) {
+	for _, v := range values {
+		mm.Put(key, v)
+	}
+}
+
+// GetAll returns the values of key in no particular order.
+func (mm *SetMultimap[K, V]) GetAll(key K) []V {
+	values, ok := mm.m[key]
+	if !ok {
+		return nil
+	}
+	dst := make([]V, 0, len(values))
+	for v := range values {
+		dst = append(dst, v)
+	}
+	return dst
+}
+
+// Remove removes key together with all its values.
+func (mm *SetMultimap[K, V]) Remove(key K) {
+	mm.size -= len(mm.m[key])
+	delete(mm.m, key)
+}
+
+// RemoveValue removes value from key.
+func (mm *SetMultimap[K, V]) RemoveValue(key K, value V) bool {
+	values, ok := mm.m[key]
+	if !ok {
+		return false
+	}
+	if _, ok := values[value]; !ok {
+		return false
+	}
+	delete(values, value)
+	if len(values) == 0 {
+		delete(mm.m, key)
+	}
+	mm.size--
+	return true
+}
+
+// ContainsKey returns true if key has at least one value.
+func (mm *SetMultimap[K, V]) ContainsKey(key K) bool {
+	_, ok := mm.m[key]
+	return ok
+}
+
+// ContainsEntry returns true if value is associated with key.
+func (mm *SetMultimap[K, V]) ContainsEntry(key K, value V) bool {
+	_, ok := mm.m[key][value]
+	return ok
+}
+
+// Count returns the number of distinct values associated with key.
+func (mm *SetMultimap[K, V]) Count(key K) int {
+	return len(mm.m[key])
+}
+
+// Keys returns the distinct keys of the multimap in no particular order.
+func (mm *SetMultimap[K, V]) Keys() []K {
+	keys := make([]K, 0, len(mm.m))
+	for k := range mm.m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Empty returns true if the multimap holds no pairs.
+func (mm *SetMultimap[K, V]) Empty() bool {
+	return mm.size == 0
+}
+
+// Size returns the number of key-value pairs in the multimap.
+func (mm *SetMultimap[K, V]) Size() int {
+	return mm.size
+}
+
+// Clear removes all pairs from the multimap.
+func (mm *SetMultimap[K, V]) Clear() {
+	for k := range mm.m {
+		delete(mm.m, k)
+	}
+	mm.size = 0
+}
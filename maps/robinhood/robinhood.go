@@ -0,0 +1,273 @@
+// Package robinhood implements an open-addressing hash map with Robin Hood hashing.
+//
+// Entries live directly in a power-of-two slot array and collisions are resolved by linear probing.
+// On insertion an entry that is further from its home slot than the resident entry takes the slot
+// ("steals from the rich"), which keeps probe sequences short and lets lookups stop early.
+// Removed entries leave tombstones behind; they are reused by later insertions and dropped
+// when the table is compacted or resized.
+//
+// Unlike hashmap.Map the key type is not required to be comparable:
+// hashing and equality are supplied by the caller.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Hash_table#Robin_Hood_hashing
+package robinhood
+
+import (
+	"github.com/kwstars/goads/maps"
+	"github.com/kwstars/goads/pkg/common"
+)
+
+// Verify that Map implements the maps.Map interface.
+var _ maps.Map[int, int] = (*Map[int, int])(nil)
+
+const (
+	minCapacity          = 8
+	defaultMaxLoadFactor = 0.85
+)
+
+// slot states
+const (
+	empty uint8 = iota
+	occupied
+	deleted
+)
+
+// slot is a single cell of the table.
+type slot[K any, V any] struct {
+	key   K
+	value V
+	hash  uint64
+	dist  int   // dist is the distance from the home slot; tombstones keep the distance of the removed entry.
+	state uint8 // state is one of empty, occupied or deleted.
+}
+
+// Option is a function than can be passed to New to customize the Map.
+type Option[K any, V any] func(*Map[K, V])
+
+// WithInitialCapacity sets the number of entries the Map can hold without resizing.
+func WithInitialCapacity[K any, V any](capacity int) Option[K, V] {
+	return func(m *Map[K, V]) {
+		m.initialCapacity = capacity
+	}
+}
+
+// WithMaxLoadFactor sets the ratio of used slots (entries and tombstones) to capacity that triggers a resize.
+// Values outside (0, 1) are ignored.
+func WithMaxLoadFactor[K any, V any](loadFactor float64) Option[K, V] {
+	return func(m *Map[K, V]) {
+		if loadFactor > 0 && loadFactor < 1 {
+			m.maxLoadFactor = loadFactor
+		}
+	}
+}
+
+// Map is an open-addressing hash map using Robin Hood hashing.
+type Map[K any, V any] struct {
+	slots      []slot[K, V]
+	size       int // size is the number of live entries.
+	tombstones int // tombstones is the number of deleted slots.
+
+	hasher common.Hasher[K]
+	equal  func(a, b K) bool
+
+	maxLoadFactor   float64
+	initialCapacity int
+}
+
+// New returns a new Robin Hood hash map that hashes keys with hasher and compares them with equal.
+func New[K any, V any](hasher common.Hasher[K], equal func(a, b K) bool, opts ...Option[K, V]) *Map[K, V] {
+	m := &Map[K, V]{
+		hasher:        hasher,
+		equal:         equal,
+		maxLoadFactor: defaultMaxLoadFactor,
+	}
+
+	for _, option := range opts {
+		option(m)
+	}
+
+	m.slots = make([]slot[K, V], m.capacityFor(m.initialCapacity))
+
+	return m
+}
+
+// capacityFor returns the smallest power-of-two capacity that holds n entries within the load factor.
+func (m *Map[K, V]) capacityFor(n int) int {
+	capacity := minCapacity
+	for float64(n) > m.maxLoadFactor*float64(capacity) {
+		capacity <<= 1
+	}
+	return capacity
+}
+
+// limit returns the maximum number of used slots before the table must be rebuilt.
+func (m *Map[K, V]) limit() int {
+	return int(m.maxLoadFactor * float64(len(m.slots)))
+}
+
+// find returns the slot index of key, or -1 if key is not present.
+func (m *Map[K, V]) find(key K) int {
+	h := m.hasher(key)
+	mask := len(m.slots) - 1
+	for i, d := int(h)&mask, 0; ; i, d = (i+1)&mask, d+1 {
+		s := &m.slots[i]
+		// Any entry of this key would have displaced a slot closer to its home than d.
+		if s.state == empty || s.dist < d {
+			return -1
+		}
+		if s.state == occupied && s.hash == h && m.equal(s.key, key) {
+			return i
+		}
+	}
+}
+
+// insert places a key that is known to be absent. The caller guarantees a free slot exists.
+func (m *Map[K, V]) insert(h uint64, key K, value V) {
+	e := slot[K, V]{key: key, value: value, hash: h, state: occupied}
+	mask := len(m.slots) - 1
+	for i := int(h) & mask; ; i = (i + 1) & mask {
+		s := &m.slots[i]
+		switch {
+		case s.state == empty:
+			*s = e
+			m.size++
+			return
+		case s.state == deleted && s.dist <= e.dist:
+			// The tombstone can be reused: every probe that passed it still passes e.
+			*s = e
+			m.tombstones--
+			m.size++
+			return
+		case s.state == occupied && s.dist < e.dist:
+			*s, e = e, *s
+		}
+		e.dist++
+	}
+}
+
+// resize rebuilds the table with the given capacity, dropping all tombstones.
+func (m *Map[K, V]) resize(capacity int) {
+	old := m.slots
+	m.slots = make([]slot[K, V], capacity)
+	m.size, m.tombstones = 0, 0
+	for i := range old {
+		if old[i].state == occupied {
+			m.insert(old[i].hash, old[i].key, old[i].value)
+		}
+	}
+}
+
+// Put inserts a key-value pair into the map, replacing the value of an existing key.
+func (m *Map[K, V]) Put(key K, value V) {
+	if i := m.find(key); i >= 0 {
+		m.slots[i].value = value
+		return
+	}
+
+	if m.size+m.tombstones+1 > m.limit() {
+		if m.size+1 > m.limit()/2 {
+			m.resize(len(m.slots) << 1)
+		} else {
+			// Mostly tombstones: compact in place instead of growing.
+			m.resize(len(m.slots))
+		}
+	}
+	m.insert(m.hasher(key), key, value)
+}
+
+// Get returns the value associated with the given key.
+func (m *Map[K, V]) Get(key K) (value V, found bool) {
+	if i := m.find(key); i >= 0 {
+		return m.slots[i].value, true
+	}
+	return value, false
+}
+
+// Remove removes the key-value pair associated with the given key.
+func (m *Map[K, V]) Remove(key K) {
+	i := m.find(key)
+	if i < 0 {
+		return
+	}
+
+	// Keep dist so that probes for other keys do not stop early at the tombstone.
+	m.slots[i] = slot[K, V]{dist: m.slots[i].dist, state: deleted}
+	m.size--
+	m.tombstones++
+
+	if m.tombstones > len(m.slots)>>2 {
+		m.resize(len(m.slots))
+	}
+}
+
+// Contains returns true if the map holds the given key.
+func (m *Map[K, V]) Contains(key K) bool {
+	return m.find(key) >= 0
+}
+
+// Keys returns all keys of the map in slot order.
+func (m *Map[K, V]) Keys() []K {
+	keys := make([]K, 0, m.size)
+	for i := range m.slots {
+		if m.slots[i].state == occupied {
+			keys = append(keys, m.slots[i].key)
+		}
+	}
+	return keys
+}
+
+// Values returns all values of the map in slot order, matching Keys.
+func (m *Map[K, V]) Values() []V {
+	values := make([]V, 0, m.size)
+	for i := range m.slots {
+		if m.slots[i].state == occupied {
+			values = append(values, m.slots[i].value)
+		}
+	}
+	return values
+}
+
+// Compact drops all tombstones and shrinks the table to the smallest capacity that fits the current entries.
+func (m *Map[K, V]) Compact() {
+	m.resize(m.capacityFor(m.size))
+}
+
+// Range calls fn for each key-value pair in the map in no particular order.
+// If fn returns false, Range stops the iteration.
+func (m *Map[K, V]) Range(fn func(key K, value V) bool) {
+	for i := range m.slots {
+		if m.slots[i].state == occupied && !fn(m.slots[i].key, m.slots[i].value) {
+			return
+		}
+	}
+}
+
+// Capacity returns the number of slots in the table.
+func (m *Map[K, V]) Capacity() int {
+	return len(m.slots)
+}
+
+// LoadFactor returns the ratio of live entries to capacity.
+func (m *Map[K, V]) LoadFactor() float64 {
+	return float64(m.size) / float64(len(m.slots))
+}
+
+// Empty returns true if the map is empty, false otherwise.
+func (m *Map[K, V]) Empty() bool {
+	return m.size == 0
+}
+
+// Size returns the number of elements in the map.
+func (m *Map[K, V]) Size() int {
+	return m.size
+}
+
+// Clear removes all elements from the map, keeping its capacity.
+func (m *Map[K, V]) Clear() {
+	for i := range m.slots {
+		m.slots[i] = slot[K, V]{}
+	}
+	m.size, m.tombstones = 0, 0
+}
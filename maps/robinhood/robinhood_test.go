@@ -0,0 +1,220 @@
+package robinhood
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/kwstars/goads/maps/hashmap"
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func intEqual(a, b int) bool { return a == b }
+
+func newIntMap(opts ...Option[int, int]) *Map[int, int] {
+	return New[int, int](common.IntHasher, intEqual, opts...)
+}
+
+func TestMap_PutGetRemove(t *testing.T) {
+	m := newIntMap()
+	assert.True(t, m.Empty())
+
+	for i := 0; i < 100; i++ {
+		m.Put(i, i*10)
+	}
+	m.Put(5, 500)
+	assert.Equal(t, 100, m.Size())
+
+	v, found := m.Get(5)
+	assert.True(t, found)
+	assert.Equal(t, 500, v)
+
+	_, found = m.Get(1000)
+	assert.False(t, found)
+
+	for i := 0; i < 100; i += 2 {
+		m.Remove(i)
+	}
+	m.Remove(1000)
+	assert.Equal(t, 50, m.Size())
+	for i := 0; i < 100; i++ {
+		_, found = m.Get(i)
+		assert.Equal(t, i%2 == 1, found, "key %d", i)
+	}
+}
+
+func TestMap_CustomHasherAndEquality(t *testing.T) {
+	// Slices are not comparable, so they cannot be keys of the builtin map.
+	hasher := func(k []byte) uint64 { return common.BytesHasher(k) }
+	equal := func(a, b []byte) bool { return string(a) == string(b) }
+	m := New[[]byte, string](hasher, equal)
+
+	m.Put([]byte("go"), "gopher")
+	v, found := m.Get([]byte("go"))
+	assert.True(t, found)
+	assert.Equal(t, "gopher", v)
+}
+
+func TestMap_Collisions(t *testing.T) {
+	// A constant hasher puts every key into one probe sequence.
+	m := New[int, int](func(int) uint64 { return 7 }, intEqual)
+	for i := 0; i < 20; i++ {
+		m.Put(i, i)
+	}
+	for i := 0; i < 20; i += 3 {
+		m.Remove(i)
+	}
+	for i := 0; i < 20; i++ {
+		v, found := m.Get(i)
+		if i%3 == 0 {
+			assert.False(t, found)
+		} else {
+			assert.True(t, found)
+			assert.Equal(t, i, v)
+		}
+	}
+}
+
+func TestMap_MatchesBuiltinMap(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	m := newIntMap(WithMaxLoadFactor[int, int](0.9))
+	want := make(map[int]int)
+
+	for i := 0; i < 20000; i++ {
+		k := r.Intn(512)
+		switch r.Intn(3) {
+		case 0, 1:
+			m.Put(k, i)
+			want[k] = i
+		case 2:
+			m.Remove(k)
+			delete(want, k)
+		}
+	}
+
+	assert.Equal(t, len(want), m.Size())
+	got := make(map[int]int)
+	m.Range(func(k, v int) bool {
+		got[k] = v
+		return true
+	})
+	assert.Equal(t, want, got)
+}
+
+func TestMap_TombstonesAndCompaction(t *testing.T) {
+	m := newIntMap(WithInitialCapacity[int, int](1000))
+	capacity := m.Capacity()
+	assert.GreaterOrEqual(t, float64(capacity)*defaultMaxLoadFactor, 1000.0)
+
+	for i := 0; i < 1000; i++ {
+		m.Put(i, i)
+	}
+	for i := 0; i < 990; i++ {
+		m.Remove(i)
+	}
+	assert.Equal(t, capacity, m.Capacity())
+	assert.LessOrEqual(t, m.tombstones, capacity/4)
+
+	m.Compact()
+	assert.Equal(t, 0, m.tombstones)
+	assert.Equal(t, 16, m.Capacity())
+	for i := 990; i < 1000; i++ {
+		v, found := m.Get(i)
+		assert.True(t, found)
+		assert.Equal(t, i, v)
+	}
+}
+
+func TestMap_RangeStops(t *testing.T) {
+	m := newIntMap()
+	for i := 0; i < 10; i++ {
+		m.Put(i, i)
+	}
+	calls := 0
+	m.Range(func(k, v int) bool {
+		calls++
+		return calls < 3
+	})
+	assert.Equal(t, 3, calls)
+}
+
+func TestMap_Clear(t *testing.T) {
+	m := newIntMap()
+	for i := 0; i < 100; i++ {
+		m.Put(i, i)
+	}
+	capacity := m.Capacity()
+	m.Clear()
+	assert.True(t, m.Empty())
+	assert.Equal(t, capacity, m.Capacity())
+	assert.Equal(t, 0.0, m.LoadFactor())
+	_, found := m.Get(1)
+	assert.False(t, found)
+}
+
+const benchSize = 1 << 16
+
+func benchKeys() []string {
+	keys := make([]string, benchSize)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	return keys
+}
+
+func BenchmarkPut(b *testing.B) {
+	keys := benchKeys()
+	b.Run("robinhood", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m := New[string, int](common.StringHasher, func(a, b string) bool { return a == b })
+			for j, k := range keys {
+				m.Put(k, j)
+			}
+		}
+	})
+	b.Run("hashmap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m := hashmap.New[string, int]()
+			for j, k := range keys {
+				m.Put(k, j)
+			}
+		}
+	})
+	b.Run("builtin", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m := make(map[string]int)
+			for j, k := range keys {
+				m[k] = j
+			}
+		}
+	})
+}
+
+func BenchmarkGet(b *testing.B) {
+	keys := benchKeys()
+	rh := New[string, int](common.StringHasher, func(a, b string) bool { return a == b })
+	hm := hashmap.New[string, int]()
+	builtin := make(map[string]int)
+	for j, k := range keys {
+		rh.Put(k, j)
+		hm.Put(k, j)
+		builtin[k] = j
+	}
+
+	b.Run("robinhood", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rh.Get(keys[i&(benchSize-1)])
+		}
+	})
+	b.Run("hashmap", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			hm.Get(keys[i&(benchSize-1)])
+		}
+	})
+	b.Run("builtin", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = builtin[keys[i&(benchSize-1)]]
+		}
+	})
+}
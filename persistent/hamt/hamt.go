@@ -0,0 +1,334 @@
+// Package hamt implements a persistent hash map as a hash array mapped trie (HAMT): an immutable map
+// whose updates return a new version that shares all unchanged nodes with the old one.
+//
+// Each level of the trie consumes 5 bits of the key hash. A node stores a 32-bit bitmap of the
+// occupied slots and a compact array with one entry per set bit, so sparse nodes stay small.
+// Keys whose full 64-bit hashes collide share a collision node at the bottom.
+// Get, Put and Remove take O(log₃₂ n).
+//
+// A Map is never modified, so it is safe for concurrent use and can be passed between goroutines
+// without copying. For a batch of updates, Transient returns a mutable copy that changes in place the
+// nodes it has already copied; it is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Hash_array_mapped_trie
+package hamt
+
+import (
+	"math/bits"
+
+	"github.com/kwstars/goads/maps"
+	"github.com/kwstars/goads/pkg/common"
+)
+
+var (
+	_ maps.Reader[int, int] = (*Map[int, int])(nil)
+	_ maps.Reader[int, int] = (*Transient[int, int])(nil)
+)
+
+const (
+	levelBits = 5
+	levelMask = 1<<levelBits - 1
+	maxShift  = 64 // below this the hash is exhausted and nodes are collision nodes
+)
+
+// owner marks the nodes a transient may modify in place. It must not be a zero-size type,
+// because distinct pointers to zero-size values may be equal.
+type owner struct{ _ byte }
+
+// entry is a key-value pair, or a child node if child is set.
+type entry[K comparable, V any] struct {
+	hash  uint64
+	key   K
+	value V
+	child *node[K, V]
+}
+
+// node is a bitmap-indexed node, or a collision node holding entries with equal hashes if shift >= maxShift.
+type node[K comparable, V any] struct {
+	edit    *owner
+	bitmap  uint32
+	entries []entry[K, V]
+}
+
+func (n *node[K, V]) editable(edit *owner) *node[K, V] {
+	if edit != nil && n.edit == edit {
+		return n
+	}
+	return &node[K, V]{edit: edit, bitmap: n.bitmap, entries: append([]entry[K, V](nil), n.entries...)}
+}
+
+// slot returns the bit of hash at shift and the position of its entry in the compact array.
+func (n *node[K, V]) slot(hash uint64, shift uint) (bit uint32, pos int) {
+	bit = 1 << ((hash >> shift) & levelMask)
+	return bit, bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+func get[K comparable, V any](n *node[K, V], hash uint64, key K) (value V, found bool) {
+	for shift := uint(0); ; shift += levelBits {
+		if shift >= maxShift {
+			for _, e := range n.entries {
+				if e.key == key {
+					return e.value, true
+				}
+			}
+			return value, false
+		}
+		bit, pos := n.slot(hash, shift)
+		if n.bitmap&bit == 0 {
+			return value, false
+		}
+		e := &n.entries[pos]
+		if e.child == nil {
+			if e.hash == hash && e.key == key {
+				return e.value, true
+			}
+			return value, false
+		}
+		n = e.child
+	}
+}
+
+// put returns n with key set to value and whether the key was added.
+func put[K comparable, V any](edit *owner, n *node[K, V], shift uint, e entry[K, V]) (*node[K, V], bool) {
+	if shift >= maxShift {
+		for i := range n.entries {
+			if n.entries[i].key == e.key {
+				n = n.editable(edit)
+				n.entries[i].value = e.value
+				return n, false
+			}
+		}
+		n = n.editable(edit)
+		n.entries = append(n.entries, e)
+		return n, true
+	}
+
+	bit, pos := n.slot(e.hash, shift)
+	if n.bitmap&bit == 0 {
+		n = n.editable(edit)
+		n.bitmap |= bit
+		n.entries = append(n.entries, entry[K, V]{})
+		copy(n.entries[pos+1:], n.entries[pos:])
+		n.entries[pos] = e
+		return n, true
+	}
+
+	old := n.entries[pos]
+	var replacement entry[K, V]
+	added := true
+	switch {
+	case old.child != nil:
+		var child *node[K, V]
+		child, added = put(edit, old.child, shift+levelBits, e)
+		if child == old.child {
+			return n, added
+		}
+		replacement = entry[K, V]{child: child}
+	case old.hash == e.hash && old.key == e.key:
+		replacement, added = e, false
+	default:
+		// Two keys share this slot: push both one level down.
+		child := &node[K, V]{edit: edit}
+		child, _ = put(edit, child, shift+levelBits, old)
+		child, _ = put(edit, child, shift+levelBits, e)
+		replacement = entry[K, V]{child: child}
+	}
+	n = n.editable(edit)
+	n.entries[pos] = replacement
+	return n, added
+}
+
+// remove returns n without key, or nil if n becomes empty, and whether the key was present.
+func remove[K comparable, V any](edit *owner, n *node[K, V], shift uint, hash uint64, key K) (*node[K, V], bool) {
+	if shift >= maxShift {
+		for i := range n.entries {
+			if n.entries[i].key == key {
+				return removeAt(edit, n, i, 0), true
+			}
+		}
+		return n, false
+	}
+
+	bit, pos := n.slot(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	e := n.entries[pos]
+	if e.child == nil {
+		if e.hash != hash || e.key != key {
+			return n, false
+		}
+		return removeAt(edit, n, pos, bit), true
+	}
+
+	child, removed := remove(edit, e.child, shift+levelBits, hash, key)
+	if !removed {
+		return n, false
+	}
+	n = n.editable(edit)
+	switch {
+	case child == nil:
+		return removeAt(edit, n, pos, bit), true
+	case len(child.entries) == 1 && child.entries[0].child == nil:
+		// Pull a lone pair up so that the trie stays as shallow as its keys require.
+		n.entries[pos] = child.entries[0]
+	default:
+		n.entries[pos] = entry[K, V]{child: child}
+	}
+	return n, true
+}
+
+// removeAt removes the entry at pos and its bitmap bit, returning nil if n becomes empty.
+func removeAt[K comparable, V any](edit *owner, n *node[K, V], pos int, bit uint32) *node[K, V] {
+	if len(n.entries) == 1 {
+		return nil
+	}
+	n = n.editable(edit)
+	n.bitmap &^= bit
+	copy(n.entries[pos:], n.entries[pos+1:])
+	n.entries[len(n.entries)-1] = entry[K, V]{}
+	n.entries = n.entries[:len(n.entries)-1]
+	return n
+}
+
+func each[K comparable, V any](n *node[K, V], fn func(key K, value V) bool) bool {
+	for _, e := range n.entries {
+		if e.child != nil {
+			if !each(e.child, fn) {
+				return false
+			}
+		} else if !fn(e.key, e.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// hamt is the state shared by Map and Transient.
+type hamt[K comparable, V any] struct {
+	root   *node[K, V]
+	size   int
+	hasher common.Hasher[K]
+}
+
+// Get returns the value associated with the given key.
+func (h *hamt[K, V]) Get(key K) (value V, found bool) {
+	return get(h.root, h.hasher(key), key)
+}
+
+// Contains returns true if the map holds the given key.
+func (h *hamt[K, V]) Contains(key K) bool {
+	_, found := h.Get(key)
+	return found
+}
+
+// Range calls fn for each key-value pair in hash order. If fn returns false, Range stops the iteration.
+func (h *hamt[K, V]) Range(fn func(key K, value V) bool) {
+	each(h.root, fn)
+}
+
+// Keys returns all keys in hash order.
+func (h *hamt[K, V]) Keys() []K {
+	keys := make([]K, 0, h.size)
+	h.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Values returns all values in the order of Keys.
+func (h *hamt[K, V]) Values() []V {
+	values := make([]V, 0, h.size)
+	h.Range(func(_ K, value V) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// Empty returns true if the map holds no pairs.
+func (h *hamt[K, V]) Empty() bool {
+	return h.size == 0
+}
+
+// Size returns the number of pairs.
+func (h *hamt[K, V]) Size() int {
+	return h.size
+}
+
+func (h *hamt[K, V]) put(edit *owner, key K, value V) {
+	var added bool
+	h.root, added = put(edit, h.root, 0, entry[K, V]{hash: h.hasher(key), key: key, value: value})
+	if added {
+		h.size++
+	}
+}
+
+func (h *hamt[K, V]) remove(edit *owner, key K) bool {
+	root, removed := remove(edit, h.root, 0, h.hasher(key), key)
+	if !removed {
+		return false
+	}
+	if root == nil {
+		root = &node[K, V]{edit: edit}
+	}
+	h.root = root
+	h.size--
+	return true
+}
+
+// Map is an immutable hash map.
+type Map[K comparable, V any] struct {
+	hamt[K, V]
+}
+
+// New returns an empty map that hashes keys with hasher.
+func New[K comparable, V any](hasher common.Hasher[K]) *Map[K, V] {
+	return &Map[K, V]{hamt[K, V]{root: &node[K, V]{}, hasher: hasher}}
+}
+
+// Put returns a map in which key is associated with value.
+func (m *Map[K, V]) Put(key K, value V) *Map[K, V] {
+	n := &Map[K, V]{m.hamt}
+	n.put(nil, key, value)
+	return n
+}
+
+// Remove returns a map without key. It returns m itself if key is not present.
+func (m *Map[K, V]) Remove(key K) *Map[K, V] {
+	n := &Map[K, V]{m.hamt}
+	if !n.remove(nil, key) {
+		return m
+	}
+	return n
+}
+
+// Transient returns a mutable copy of m for batch updates. m is not affected by them.
+func (m *Map[K, V]) Transient() *Transient[K, V] {
+	return &Transient[K, V]{hamt: m.hamt, edit: new(owner)}
+}
+
+// Transient is a mutable hash map that shares structure with the Map it came from
+// and copies a node only the first time it changes it.
+type Transient[K comparable, V any] struct {
+	hamt[K, V]
+	edit *owner
+}
+
+// Put associates key with value.
+func (t *Transient[K, V]) Put(key K, value V) {
+	t.put(t.edit, key, value)
+}
+
+// Remove removes key. It returns false if key is not present.
+func (t *Transient[K, V]) Remove(key K) bool {
+	return t.remove(t.edit, key)
+}
+
+// Persistent returns an immutable map with the current pairs. The transient stays usable,
+// but stops modifying the nodes it shares with the returned map.
+func (t *Transient[K, V]) Persistent() *Map[K, V] {
+	t.edit = new(owner)
+	return &Map[K, V]{t.hamt}
+}
@@ -0,0 +1,119 @@
+package hamt
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMap(t *testing.T) {
+	empty := New[string, int](common.StringHasher)
+	m1 := empty.Put("a", 1).Put("b", 2)
+	m2 := m1.Put("a", 10).Put("c", 3)
+	m3 := m2.Remove("b")
+
+	assert.True(t, empty.Empty())
+	assert.Equal(t, 2, m1.Size())
+	v, ok := m1.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	v, _ = m2.Get("a")
+	assert.Equal(t, 10, v)
+	assert.Equal(t, 3, m2.Size())
+	assert.False(t, m3.Contains("b"))
+	assert.True(t, m2.Contains("b"))
+	assert.Equal(t, 2, m3.Size())
+	assert.Same(t, m3, m3.Remove("x"))
+
+	keys := m2.Keys()
+	sort.Strings(keys)
+	assert.Equal(t, []string{"a", "b", "c"}, keys)
+	assert.ElementsMatch(t, []int{10, 2, 3}, m2.Values())
+}
+
+func TestCollisions(t *testing.T) {
+	// Only four distinct hashes, so most keys end up in collision nodes.
+	hasher := func(k int) uint64 { return uint64(k % 4) }
+	m := New[int, int](hasher)
+	for i := 0; i < 100; i++ {
+		m = m.Put(i, i*i)
+	}
+	assert.Equal(t, 100, m.Size())
+	for i := 0; i < 100; i++ {
+		v, ok := m.Get(i)
+		assert.True(t, ok)
+		assert.Equal(t, i*i, v)
+	}
+	for i := 0; i < 100; i += 2 {
+		m = m.Remove(i)
+	}
+	assert.Equal(t, 50, m.Size())
+	assert.False(t, m.Contains(10))
+	assert.True(t, m.Contains(11))
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	m := New[int, int](common.IntHasher)
+	want := make(map[int]int)
+	type snapshot struct {
+		m    *Map[int, int]
+		want map[int]int
+	}
+	var snapshots []snapshot
+
+	tr := m.Transient()
+	for i := 0; i < 20000; i++ {
+		k := rng.Intn(3000)
+		transient := i%2 == 0
+		if rng.Intn(3) == 0 {
+			_, found := want[k]
+			if transient {
+				assert.Equal(t, found, tr.Remove(k))
+			} else {
+				m = m.Remove(k)
+			}
+			delete(want, k)
+		} else {
+			if transient {
+				tr.Put(k, i)
+			} else {
+				m = m.Put(k, i)
+			}
+			want[k] = i
+		}
+		// Alternate between the persistent map and a transient on top of it.
+		if transient {
+			m = tr.Persistent()
+		} else {
+			tr = m.Transient()
+		}
+		if i%2000 == 0 {
+			copied := make(map[int]int, len(want))
+			for k, v := range want {
+				copied[k] = v
+			}
+			snapshots = append(snapshots, snapshot{m, copied})
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, s := range snapshots {
+		wg.Add(1)
+		go func(s snapshot) {
+			defer wg.Done()
+			assert.Equal(t, len(s.want), s.m.Size())
+			for k, v := range s.want {
+				got, ok := s.m.Get(k)
+				assert.True(t, ok)
+				assert.Equal(t, v, got)
+			}
+			assert.Len(t, s.m.Keys(), len(s.want))
+		}(s)
+	}
+	wg.Wait()
+}
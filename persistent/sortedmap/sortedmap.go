@@ -0,0 +1,359 @@
+// Package sortedmap implements a persistent sorted map: an immutable map ordered by key whose updates
+// return a new version that shares all unchanged nodes with the old one.
+//
+// It is an AVL tree updated by path copying: an update copies only the O(log n) nodes on the path
+// from the root to the changed key, rebalancing the copies on the way back up.
+//
+// A Map is never modified, so it is safe for concurrent use and can be passed between goroutines
+// without copying. For a batch of updates, Transient returns a mutable copy that changes in place the
+// nodes it has already copied; it is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Persistent_data_structure#Trees, https://en.wikipedia.org/wiki/AVL_tree
+package sortedmap
+
+import (
+	"github.com/kwstars/goads/maps"
+	"github.com/kwstars/goads/pkg/common"
+)
+
+var (
+	_ maps.Reader[int, int] = (*Map[int, int])(nil)
+	_ maps.Reader[int, int] = (*Transient[int, int])(nil)
+)
+
+// owner marks the nodes a transient may modify in place. It must not be a zero-size type,
+// because distinct pointers to zero-size values may be equal.
+type owner struct{ _ byte }
+
+type node[K any, V any] struct {
+	edit        *owner
+	key         K
+	value       V
+	left, right *node[K, V]
+	height      int
+	size        int
+}
+
+func height[K any, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func size[K any, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *node[K, V]) editable(edit *owner) *node[K, V] {
+	if edit != nil && n.edit == edit {
+		return n
+	}
+	c := *n
+	c.edit = edit
+	return &c
+}
+
+func (n *node[K, V]) update() {
+	n.height = height(n.left)
+	if h := height(n.right); h > n.height {
+		n.height = h
+	}
+	n.height++
+	n.size = size(n.left) + size(n.right) + 1
+}
+
+func rotateRight[K any, V any](edit *owner, n *node[K, V]) *node[K, V] {
+	l := n.left.editable(edit)
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
+
+func rotateLeft[K any, V any](edit *owner, n *node[K, V]) *node[K, V] {
+	r := n.right.editable(edit)
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+// rebalance restores the AVL property at n, which edit must own, after one of its subtrees changed.
+func rebalance[K any, V any](edit *owner, n *node[K, V]) *node[K, V] {
+	n.update()
+	switch bf := height(n.left) - height(n.right); {
+	case bf > 1:
+		if height(n.left.left) < height(n.left.right) {
+			n.left = rotateLeft(edit, n.left.editable(edit))
+		}
+		return rotateRight(edit, n)
+	case bf < -1:
+		if height(n.right.right) < height(n.right.left) {
+			n.right = rotateRight(edit, n.right.editable(edit))
+		}
+		return rotateLeft(edit, n)
+	}
+	return n
+}
+
+// tree is the state shared by Map and Transient.
+type tree[K any, V any] struct {
+	root *node[K, V]
+	cmp  common.Comparator[K, K]
+}
+
+func (t *tree[K, V]) put(edit *owner, n *node[K, V], key K, value V) *node[K, V] {
+	if n == nil {
+		return &node[K, V]{edit: edit, key: key, value: value, height: 1, size: 1}
+	}
+	c := t.cmp(key, n.key)
+	n = n.editable(edit)
+	switch {
+	case c < 0:
+		n.left = t.put(edit, n.left, key, value)
+	case c > 0:
+		n.right = t.put(edit, n.right, key, value)
+	default:
+		n.value = value
+		return n
+	}
+	return rebalance(edit, n)
+}
+
+// remove returns the subtree of n without key. The caller has checked that key is present.
+func (t *tree[K, V]) remove(edit *owner, n *node[K, V], key K) *node[K, V] {
+	c := t.cmp(key, n.key)
+	switch {
+	case c < 0:
+		n = n.editable(edit)
+		n.left = t.remove(edit, n.left, key)
+	case c > 0:
+		n = n.editable(edit)
+		n.right = t.remove(edit, n.right, key)
+	default:
+		if n.left == nil {
+			return n.right
+		}
+		if n.right == nil {
+			return n.left
+		}
+		// Replace n by its successor.
+		succ := n.right
+		for succ.left != nil {
+			succ = succ.left
+		}
+		n = n.editable(edit)
+		n.key, n.value = succ.key, succ.value
+		n.right = t.remove(edit, n.right, succ.key)
+	}
+	return rebalance(edit, n)
+}
+
+func (t *tree[K, V]) find(key K) *node[K, V] {
+	for n := t.root; n != nil; {
+		switch c := t.cmp(key, n.key); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// Get returns the value associated with the given key.
+func (t *tree[K, V]) Get(key K) (value V, found bool) {
+	if n := t.find(key); n != nil {
+		return n.value, true
+	}
+	return value, false
+}
+
+// Contains returns true if the map holds the given key.
+func (t *tree[K, V]) Contains(key K) bool {
+	return t.find(key) != nil
+}
+
+// Min returns the smallest key and its value.
+func (t *tree[K, V]) Min() (key K, value V, found bool) {
+	n := t.root
+	if n == nil {
+		return key, value, false
+	}
+	for n.left != nil {
+		n = n.left
+	}
+	return n.key, n.value, true
+}
+
+// Max returns the largest key and its value.
+func (t *tree[K, V]) Max() (key K, value V, found bool) {
+	n := t.root
+	if n == nil {
+		return key, value, false
+	}
+	for n.right != nil {
+		n = n.right
+	}
+	return n.key, n.value, true
+}
+
+// Floor returns the largest key less than or equal to the given key, and its value.
+func (t *tree[K, V]) Floor(key K) (floor K, value V, found bool) {
+	var best *node[K, V]
+	for n := t.root; n != nil; {
+		c := t.cmp(key, n.key)
+		if c < 0 {
+			n = n.left
+			continue
+		}
+		best = n
+		if c == 0 {
+			break
+		}
+		n = n.right
+	}
+	if best == nil {
+		return floor, value, false
+	}
+	return best.key, best.value, true
+}
+
+// Ceiling returns the smallest key greater than or equal to the given key, and its value.
+func (t *tree[K, V]) Ceiling(key K) (ceiling K, value V, found bool) {
+	var best *node[K, V]
+	for n := t.root; n != nil; {
+		c := t.cmp(key, n.key)
+		if c > 0 {
+			n = n.right
+			continue
+		}
+		best = n
+		if c == 0 {
+			break
+		}
+		n = n.left
+	}
+	if best == nil {
+		return ceiling, value, false
+	}
+	return best.key, best.value, true
+}
+
+// Rank returns the number of keys less than key.
+func (t *tree[K, V]) Rank(key K) int {
+	rank := 0
+	for n := t.root; n != nil; {
+		if c := t.cmp(key, n.key); c <= 0 {
+			n = n.left
+		} else {
+			rank += size(n.left) + 1
+			n = n.right
+		}
+	}
+	return rank
+}
+
+// Range calls fn for each key-value pair in ascending key order.
+// If fn returns false, Range stops the iteration.
+func (t *tree[K, V]) Range(fn func(key K, value V) bool) {
+	var visit func(n *node[K, V]) bool
+	visit = func(n *node[K, V]) bool {
+		return n == nil || visit(n.left) && fn(n.key, n.value) && visit(n.right)
+	}
+	visit(t.root)
+}
+
+// Keys returns all keys in ascending order.
+func (t *tree[K, V]) Keys() []K {
+	keys := make([]K, 0, size(t.root))
+	t.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Values returns all values in ascending key order.
+func (t *tree[K, V]) Values() []V {
+	values := make([]V, 0, size(t.root))
+	t.Range(func(_ K, value V) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// Empty returns true if the map holds no pairs.
+func (t *tree[K, V]) Empty() bool {
+	return t.root == nil
+}
+
+// Size returns the number of pairs.
+func (t *tree[K, V]) Size() int {
+	return size(t.root)
+}
+
+// Map is an immutable sorted map.
+type Map[K any, V any] struct {
+	tree[K, V]
+}
+
+// New returns an empty map ordered by cmp.
+func New[K any, V any](cmp common.Comparator[K, K]) *Map[K, V] {
+	return &Map[K, V]{tree[K, V]{cmp: cmp}}
+}
+
+// Put returns a map in which key is associated with value.
+func (m *Map[K, V]) Put(key K, value V) *Map[K, V] {
+	return &Map[K, V]{tree[K, V]{root: m.put(nil, m.root, key, value), cmp: m.cmp}}
+}
+
+// Remove returns a map without key. It returns m itself if key is not present.
+func (m *Map[K, V]) Remove(key K) *Map[K, V] {
+	if !m.Contains(key) {
+		return m
+	}
+	return &Map[K, V]{tree[K, V]{root: m.remove(nil, m.root, key), cmp: m.cmp}}
+}
+
+// Transient returns a mutable copy of m for batch updates. m is not affected by them.
+func (m *Map[K, V]) Transient() *Transient[K, V] {
+	return &Transient[K, V]{tree: m.tree, edit: new(owner)}
+}
+
+// Transient is a mutable sorted map that shares structure with the Map it came from
+// and copies a node only the first time it changes it.
+type Transient[K any, V any] struct {
+	tree[K, V]
+	edit *owner
+}
+
+// Put associates key with value.
+func (t *Transient[K, V]) Put(key K, value V) {
+	t.root = t.put(t.edit, t.root, key, value)
+}
+
+// Remove removes key. It returns false if key is not present.
+func (t *Transient[K, V]) Remove(key K) bool {
+	if !t.Contains(key) {
+		return false
+	}
+	t.root = t.remove(t.edit, t.root, key)
+	return true
+}
+
+// Persistent returns an immutable map with the current pairs. The transient stays usable,
+// but stops modifying the nodes it shares with the returned map.
+func (t *Transient[K, V]) Persistent() *Map[K, V] {
+	t.edit = new(owner)
+	return &Map[K, V]{t.tree}
+}
@@ -0,0 +1,117 @@
+package sortedmap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMap(t *testing.T) {
+	empty := New[int, string](common.IntComparator)
+	m1 := empty.Put(20, "b").Put(10, "a").Put(30, "c")
+	m2 := m1.Put(20, "B").Put(40, "d")
+	m3 := m2.Remove(10)
+
+	assert.True(t, empty.Empty())
+	assert.Equal(t, []int{10, 20, 30}, m1.Keys())
+	assert.Equal(t, []string{"a", "b", "c"}, m1.Values())
+	assert.Equal(t, []string{"a", "B", "c", "d"}, m2.Values())
+	assert.Equal(t, []int{20, 30, 40}, m3.Keys())
+	assert.Same(t, m3, m3.Remove(10))
+
+	k, v, ok := m2.Floor(25)
+	assert.True(t, ok)
+	assert.Equal(t, 20, k)
+	assert.Equal(t, "B", v)
+	k, _, _ = m2.Ceiling(25)
+	assert.Equal(t, 30, k)
+	_, _, ok = m2.Ceiling(41)
+	assert.False(t, ok)
+	_, _, ok = m3.Floor(19)
+	assert.False(t, ok)
+	k, _, _ = m3.Min()
+	assert.Equal(t, 20, k)
+	k, _, _ = m3.Max()
+	assert.Equal(t, 40, k)
+	_, _, ok = empty.Min()
+	assert.False(t, ok)
+	assert.Equal(t, 2, m2.Rank(25))
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	m := New[int, int](common.IntComparator)
+	want := make(map[int]int)
+	var snapshots []*Map[int, int]
+	var snapshotKeys [][]int
+
+	tr := m.Transient()
+	for i := 0; i < 10000; i++ {
+		k := rng.Intn(2000)
+		transient := i%3 != 0
+		if rng.Intn(3) == 0 {
+			_, found := want[k]
+			if transient {
+				assert.Equal(t, found, tr.Remove(k))
+			} else {
+				m = m.Remove(k)
+			}
+			delete(want, k)
+		} else {
+			if transient {
+				tr.Put(k, i)
+			} else {
+				m = m.Put(k, i)
+			}
+			want[k] = i
+		}
+		if transient {
+			m = tr.Persistent()
+		} else {
+			tr = m.Transient()
+		}
+		if i%1000 == 0 {
+			snapshots = append(snapshots, m)
+			snapshotKeys = append(snapshotKeys, sortedKeys(want))
+			assertBalanced(t, m.root)
+		}
+	}
+
+	for i, s := range snapshots {
+		assert.Equal(t, snapshotKeys[i], s.Keys())
+	}
+	assert.Equal(t, sortedKeys(want), m.Keys())
+	for k, v := range want {
+		got, ok := m.Get(k)
+		assert.True(t, ok)
+		assert.Equal(t, v, got)
+	}
+}
+
+func sortedKeys(m map[int]int) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func assertBalanced(t *testing.T, n *node[int, int]) int {
+	if n == nil {
+		return 0
+	}
+	l, r := assertBalanced(t, n.left), assertBalanced(t, n.right)
+	assert.LessOrEqual(t, l-r, 1)
+	assert.LessOrEqual(t, r-l, 1)
+	assert.Equal(t, size(n.left)+size(n.right)+1, n.size)
+	h := l
+	if r > h {
+		h = r
+	}
+	assert.Equal(t, h+1, n.height)
+	return h + 1
+}
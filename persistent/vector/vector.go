@@ -0,0 +1,392 @@
+// Package vector implements a persistent vector: an immutable sequence whose updates return a new
+// version that shares all unchanged parts with the old one.
+//
+// The elements live in a trie with 32 children per node, so Get and Set take O(log₃₂ n), which is at
+// most 7 steps for any practical size. The last, partially filled block of up to 32 elements is kept
+// outside the trie as the tail, which makes Append and Pop amortized O(1).
+//
+// A Vector is never modified, so it is safe for concurrent use and can be passed between goroutines
+// without copying. For a batch of updates, Transient returns a mutable copy that changes in place the
+// nodes it has already copied; it is not thread safe.
+//
+// References: https://hypirion.com/musings/understanding-persistent-vector-pt-1
+package vector
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kwstars/goads/lists"
+	"github.com/kwstars/goads/pkg/common"
+)
+
+var (
+	ErrIndexOutOfRange                = errors.New("index out of range")
+	ErrFormIndexMustBeLessThanToIndex = errors.New("fromIndex must be less than or equal to toIndex")
+	ErrEmpty                          = errors.New("vector is empty")
+)
+
+var (
+	_ lists.Reader[int] = (*Vector[int])(nil)
+	_ lists.Reader[int] = (*Transient[int])(nil)
+)
+
+const (
+	bits  = 5
+	width = 1 << bits
+	mask  = width - 1
+)
+
+// owner marks the nodes a transient may modify in place. It must not be a zero-size type,
+// because distinct pointers to zero-size values may be equal.
+type owner struct{ _ byte }
+
+// node is an inner node holding children or a leaf holding values.
+type node[T any] struct {
+	edit     *owner
+	children []*node[T]
+	values   []T
+}
+
+// editable returns n if edit owns it, or a copy owned by edit. A nil edit always copies.
+func (n *node[T]) editable(edit *owner) *node[T] {
+	if edit != nil && n.edit == edit {
+		return n
+	}
+	c := &node[T]{edit: edit}
+	if n.children != nil {
+		c.children = append([]*node[T](nil), n.children...)
+	} else {
+		c.values = append([]T(nil), n.values...)
+	}
+	return c
+}
+
+// trie is the state shared by Vector and Transient.
+type trie[T any] struct {
+	size  int
+	shift uint // bits consumed above the leaves
+	root  *node[T]
+	tail  []T
+	cmp   common.Comparator[T, T]
+}
+
+// tailOffset returns the index of the first element in the tail.
+func (t *trie[T]) tailOffset() int {
+	if t.size < width {
+		return 0
+	}
+	return ((t.size - 1) >> bits) << bits
+}
+
+// leafFor returns the block holding index i, which must be in range.
+func (t *trie[T]) leafFor(i int) []T {
+	if i >= t.tailOffset() {
+		return t.tail
+	}
+	n := t.root
+	for level := t.shift; level > 0; level -= bits {
+		n = n.children[(i>>level)&mask]
+	}
+	return n.values
+}
+
+// pushTail returns the tree with the full tail appended as a leaf, making room with a new root if needed.
+func (t *trie[T]) pushTail(edit *owner) {
+	leaf := &node[T]{edit: edit, values: t.tail}
+	if (t.size >> bits) > (1 << t.shift) {
+		// The trie is full: grow a level.
+		root := &node[T]{edit: edit, children: make([]*node[T], width)}
+		root.children[0] = t.root
+		root.children[1] = newPath(edit, t.shift, leaf)
+		t.root = root
+		t.shift += bits
+		return
+	}
+	t.root = t.pushLeaf(edit, t.shift, t.root, leaf)
+}
+
+func (t *trie[T]) pushLeaf(edit *owner, level uint, parent, leaf *node[T]) *node[T] {
+	n := parent.editable(edit)
+	i := ((t.size - 1) >> level) & mask
+	if level == bits {
+		n.children[i] = leaf
+	} else if child := n.children[i]; child != nil {
+		n.children[i] = t.pushLeaf(edit, level-bits, child, leaf)
+	} else {
+		n.children[i] = newPath(edit, level-bits, leaf)
+	}
+	return n
+}
+
+// newPath returns a chain of inner nodes from level down to leaf.
+func newPath[T any](edit *owner, level uint, leaf *node[T]) *node[T] {
+	if level == 0 {
+		return leaf
+	}
+	n := &node[T]{edit: edit, children: make([]*node[T], width)}
+	n.children[0] = newPath(edit, level-bits, leaf)
+	return n
+}
+
+func (t *trie[T]) set(edit *owner, level uint, n *node[T], i int, v T) *node[T] {
+	n = n.editable(edit)
+	if level == 0 {
+		n.values[i&mask] = v
+	} else {
+		j := (i >> level) & mask
+		n.children[j] = t.set(edit, level-bits, n.children[j], i, v)
+	}
+	return n
+}
+
+// popLeaf removes the last leaf, returning nil if the subtree becomes empty.
+func (t *trie[T]) popLeaf(edit *owner, level uint, n *node[T]) *node[T] {
+	i := ((t.size - 2) >> level) & mask
+	if level > bits {
+		child := t.popLeaf(edit, level-bits, n.children[i])
+		if child == nil && i == 0 {
+			return nil
+		}
+		n = n.editable(edit)
+		n.children[i] = child
+		return n
+	}
+	if i == 0 {
+		return nil
+	}
+	n = n.editable(edit)
+	n.children[i] = nil
+	return n
+}
+
+// pop removes the last element. The caller has checked that the trie is not empty and
+// replaces the tail slice before modifying it unless it owns it.
+func (t *trie[T]) pop(edit *owner) {
+	switch {
+	case t.size == 1:
+		*t = trie[T]{shift: bits, root: emptyRoot[T](edit), cmp: t.cmp}
+		return
+	case t.size-t.tailOffset() > 1:
+		t.tail = t.tail[:len(t.tail)-1]
+		t.size--
+		return
+	}
+	t.tail = t.leafFor(t.size - 2)
+	root := t.popLeaf(edit, t.shift, t.root)
+	if root == nil {
+		root = emptyRoot[T](edit)
+	}
+	if t.shift > bits && root.children[1] == nil {
+		root = root.children[0]
+		t.shift -= bits
+	}
+	t.root = root
+	t.size--
+}
+
+func emptyRoot[T any](edit *owner) *node[T] {
+	return &node[T]{edit: edit, children: make([]*node[T], width)}
+}
+
+func (t *trie[T]) checkIndex(index int) error {
+	if index < 0 || index >= t.size {
+		return fmt.Errorf("%w: %d, size: %d", ErrIndexOutOfRange, index, t.size)
+	}
+	return nil
+}
+
+// Empty returns true if the vector holds no elements.
+func (t *trie[T]) Empty() bool {
+	return t.size == 0
+}
+
+// Size returns the number of elements.
+func (t *trie[T]) Size() int {
+	return t.size
+}
+
+// Get returns the element at index.
+func (t *trie[T]) Get(index int) (element T, err error) {
+	if err := t.checkIndex(index); err != nil {
+		return element, err
+	}
+	return t.leafFor(index)[index&mask], nil
+}
+
+// IndexOf returns the index of the first element equal to element, or -1.
+func (t *trie[T]) IndexOf(element T) int {
+	for i := 0; i < t.size; i += width {
+		for j, v := range t.leafFor(i) {
+			if t.cmp(v, element) == 0 {
+				return i + j
+			}
+		}
+	}
+	return -1
+}
+
+// LastIndexOf returns the index of the last element equal to element, or -1.
+func (t *trie[T]) LastIndexOf(element T) int {
+	for i := t.tailOffset(); i >= 0; i -= width {
+		leaf := t.leafFor(i)
+		for j := len(leaf) - 1; j >= 0; j-- {
+			if t.cmp(leaf[j], element) == 0 {
+				return i + j
+			}
+		}
+	}
+	return -1
+}
+
+// SubList returns a copy of the elements between fromIndex, inclusive, and toIndex, exclusive.
+func (t *trie[T]) SubList(fromIndex int, toIndex int) ([]T, error) {
+	if fromIndex < 0 || toIndex < 0 || fromIndex > t.size || toIndex > t.size {
+		return nil, fmt.Errorf("%w, fromIndex: %d, toIndex: %d, size: %d", ErrIndexOutOfRange, fromIndex, toIndex, t.size)
+	}
+	if fromIndex > toIndex {
+		return nil, fmt.Errorf("%w, fromIndex: %d, toIndex: %d", ErrFormIndexMustBeLessThanToIndex, fromIndex, toIndex)
+	}
+	elements := make([]T, 0, toIndex-fromIndex)
+	for i := fromIndex; i < toIndex; {
+		leaf := t.leafFor(i)
+		end := i - i&mask + len(leaf)
+		if end > toIndex {
+			end = toIndex
+		}
+		elements = append(elements, leaf[i&mask:end-(i-i&mask)]...)
+		i = end
+	}
+	return elements, nil
+}
+
+// Values returns a copy of all elements in order.
+func (t *trie[T]) Values() []T {
+	elements, _ := t.SubList(0, t.size)
+	return elements
+}
+
+// Vector is an immutable sequence of elements of type T.
+type Vector[T any] struct {
+	trie[T]
+}
+
+// New returns a vector holding elements. cmp is only used by IndexOf and LastIndexOf.
+func New[T any](cmp common.Comparator[T, T], elements ...T) *Vector[T] {
+	t := (&Vector[T]{trie[T]{shift: bits, root: emptyRoot[T](nil), cmp: cmp}}).Transient()
+	for _, e := range elements {
+		t.Append(e)
+	}
+	return t.Persistent()
+}
+
+// Append returns a vector with element added at the end.
+func (v *Vector[T]) Append(element T) *Vector[T] {
+	w := &Vector[T]{v.trie}
+	if w.size-w.tailOffset() < width {
+		w.tail = append(w.tail[:len(w.tail):len(w.tail)], element)
+	} else {
+		w.pushTail(nil)
+		w.tail = []T{element}
+	}
+	w.size++
+	return w
+}
+
+// Set returns a vector with the element at index replaced.
+func (v *Vector[T]) Set(index int, element T) (*Vector[T], error) {
+	if err := v.checkIndex(index); err != nil {
+		return nil, err
+	}
+	w := &Vector[T]{v.trie}
+	if index >= w.tailOffset() {
+		w.tail = append([]T(nil), w.tail...)
+		w.tail[index&mask] = element
+	} else {
+		w.root = w.set(nil, w.shift, w.root, index, element)
+	}
+	return w, nil
+}
+
+// Pop returns a vector without the last element.
+func (v *Vector[T]) Pop() (*Vector[T], error) {
+	if v.size == 0 {
+		return nil, ErrEmpty
+	}
+	w := &Vector[T]{v.trie}
+	w.pop(nil)
+	return w, nil
+}
+
+// Transient returns a mutable copy of v for batch updates. v is not affected by them.
+func (v *Vector[T]) Transient() *Transient[T] {
+	return &Transient[T]{trie: v.trie, edit: new(owner)}
+}
+
+// Transient is a mutable vector that shares structure with the Vector it came from
+// and copies a node only the first time it changes it.
+type Transient[T any] struct {
+	trie[T]
+	edit      *owner
+	ownedTail bool // the tail slice was allocated by this transient
+}
+
+// Append adds element at the end.
+func (t *Transient[T]) Append(element T) {
+	switch {
+	case t.size-t.tailOffset() == width:
+		if !t.ownedTail {
+			// The full tail is shared with a Vector: the leaf made from it must not be editable in place.
+			t.tail = append(make([]T, 0, width), t.tail...)
+		}
+		t.pushTail(t.edit)
+		t.tail = make([]T, 0, width)
+		t.ownedTail = true
+	case !t.ownedTail:
+		t.tail = append(make([]T, 0, width), t.tail...)
+		t.ownedTail = true
+	}
+	t.tail = append(t.tail, element)
+	t.size++
+}
+
+// Set replaces the element at index.
+func (t *Transient[T]) Set(index int, element T) error {
+	if err := t.checkIndex(index); err != nil {
+		return err
+	}
+	if index >= t.tailOffset() {
+		if !t.ownedTail {
+			t.tail = append(make([]T, 0, width), t.tail...)
+			t.ownedTail = true
+		}
+		t.tail[index&mask] = element
+	} else {
+		t.root = t.set(t.edit, t.shift, t.root, index, element)
+	}
+	return nil
+}
+
+// Pop removes the last element.
+func (t *Transient[T]) Pop() error {
+	if t.size == 0 {
+		return ErrEmpty
+	}
+	tail := t.tailOffset()
+	t.pop(t.edit)
+	// A new tail taken from a leaf belongs to the trie; truncating an owned tail keeps it owned.
+	if t.tailOffset() != tail {
+		t.ownedTail = false
+	}
+	return nil
+}
+
+// Persistent returns an immutable vector with the current elements. The transient stays usable,
+// but stops modifying the nodes it shares with the returned vector.
+func (t *Transient[T]) Persistent() *Vector[T] {
+	t.edit = new(owner)
+	t.ownedTail = false
+	v := &Vector[T]{t.trie}
+	v.tail = v.tail[:len(v.tail):len(v.tail)]
+	return v
+}
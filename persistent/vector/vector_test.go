@@ -0,0 +1,195 @@
+package vector
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVector(t *testing.T) {
+	empty := New[int](common.IntComparator)
+	assert.True(t, empty.Empty())
+	_, err := empty.Pop()
+	assert.True(t, errors.Is(err, ErrEmpty))
+	_, err = empty.Get(0)
+	assert.True(t, errors.Is(err, ErrIndexOutOfRange))
+
+	v1 := empty.Append(1).Append(2).Append(3)
+	v2, err := v1.Set(1, 20)
+	assert.NoError(t, err)
+	v3, err := v2.Pop()
+	assert.NoError(t, err)
+	v4 := v3.Append(4)
+
+	// Every version keeps its elements.
+	assert.Empty(t, empty.Values())
+	assert.Equal(t, []int{1, 2, 3}, v1.Values())
+	assert.Equal(t, []int{1, 20, 3}, v2.Values())
+	assert.Equal(t, []int{1, 20}, v3.Values())
+	assert.Equal(t, []int{1, 20, 4}, v4.Values())
+
+	assert.Equal(t, 1, v2.IndexOf(20))
+	assert.Equal(t, -1, v1.IndexOf(20))
+	_, err = v1.Set(3, 0)
+	assert.True(t, errors.Is(err, ErrIndexOutOfRange))
+	_, err = v1.SubList(2, 1)
+	assert.True(t, errors.Is(err, ErrFormIndexMustBeLessThanToIndex))
+}
+
+func TestLarge(t *testing.T) {
+	// Enough elements for a trie of three levels below the root.
+	const n = 40000
+	var versions []*Vector[int]
+	v := New[int](common.IntComparator)
+	for i := 0; i < n; i++ {
+		v = v.Append(i)
+		if i%5000 == 0 {
+			versions = append(versions, v)
+		}
+	}
+	assert.Equal(t, n, v.Size())
+	for i := 0; i < n; i += 97 {
+		got, err := v.Get(i)
+		assert.NoError(t, err)
+		assert.Equal(t, i, got)
+	}
+	for k, old := range versions {
+		assert.Equal(t, k*5000+1, old.Size())
+		last, _ := old.Get(old.Size() - 1)
+		assert.Equal(t, k*5000, last)
+	}
+
+	sub, err := v.SubList(1000, 1100)
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, sub[0])
+	assert.Equal(t, 1099, sub[99])
+	assert.Equal(t, 33333, v.LastIndexOf(33333))
+
+	w, _ := v.Set(12345, -1)
+	got, _ := w.Get(12345)
+	assert.Equal(t, -1, got)
+	got, _ = v.Get(12345)
+	assert.Equal(t, 12345, got)
+
+	for i := 0; i < n; i++ {
+		v, err = v.Pop()
+		assert.NoError(t, err)
+	}
+	assert.True(t, v.Empty())
+}
+
+func TestTransient(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	base := New(common.IntComparator, 1, 2, 3)
+	tr := base.Transient()
+	var want []int
+	want = append(want, 1, 2, 3)
+
+	for i := 0; i < 5000; i++ {
+		switch op := rng.Intn(10); {
+		case op < 6:
+			tr.Append(i)
+			want = append(want, i)
+		case op < 8 && len(want) > 0:
+			j := rng.Intn(len(want))
+			assert.NoError(t, tr.Set(j, -i))
+			want[j] = -i
+		case len(want) > 0:
+			assert.NoError(t, tr.Pop())
+			want = want[:len(want)-1]
+		}
+		if i%500 == 0 {
+			// Freezing a version must not stop later edits or leak them into it.
+			snapshot := tr.Persistent()
+			frozen := append([]int(nil), want...)
+			tr.Append(-1)
+			if len(want) > 0 {
+				assert.NoError(t, tr.Set(0, -2))
+			}
+			assert.Equal(t, frozen, snapshot.Values())
+			assert.NoError(t, tr.Pop())
+			if len(want) > 0 {
+				assert.NoError(t, tr.Set(0, want[0]))
+			}
+		}
+	}
+	assert.Equal(t, want, tr.Values())
+	assert.Equal(t, want, tr.Persistent().Values())
+	assert.Equal(t, []int{1, 2, 3}, base.Values())
+}
+
+func TestTransient_FullSharedTail(t *testing.T) {
+	// With a size that is a multiple of 32 the tail is full, so the transient's first Append
+	// pushes the tail it shares with the source vector into the trie.
+	for _, n := range []int{32, 64, 32 * 33, 32 * 34} {
+		elements := make([]int, n)
+		for i := range elements {
+			elements[i] = i
+		}
+		v := New(common.IntComparator, elements...)
+		tr := v.Transient()
+		tr.Append(100)
+		for i := 0; i < n; i += 7 {
+			assert.NoError(t, tr.Set(i, -i-1))
+		}
+		assert.NoError(t, tr.Set(n-1, -1))
+		assert.Equal(t, elements, v.Values(), "n=%d", n)
+
+		// The same must hold for a vector frozen from a transient.
+		frozen := tr.Persistent()
+		want := frozen.Values()
+		for tr.Size() > 0 && tr.Size()%32 != 0 {
+			assert.NoError(t, tr.Pop())
+		}
+		snapshot := tr.Persistent()
+		before := snapshot.Values()
+		tr.Append(7)
+		for i := 0; i < tr.Size(); i += 5 {
+			assert.NoError(t, tr.Set(i, 9))
+		}
+		assert.Equal(t, before, snapshot.Values(), "n=%d", n)
+		assert.Equal(t, want, frozen.Values(), "n=%d", n)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	type version struct {
+		v    *Vector[int]
+		want []int
+	}
+	versions := []version{{New[int](common.IntComparator), nil}}
+
+	for i := 0; i < 5000; i++ {
+		// Branch off a random earlier version, so that versions share tails and nodes.
+		base := versions[rng.Intn(len(versions))]
+		if rng.Intn(4) != 0 {
+			base = versions[len(versions)-1]
+		}
+		next := version{want: append([]int(nil), base.want...)}
+		switch op := rng.Intn(10); {
+		case op < 6:
+			next.v = base.v.Append(i)
+			next.want = append(next.want, i)
+		case op < 8 && len(base.want) > 0:
+			j := rng.Intn(len(base.want))
+			next.v, _ = base.v.Set(j, -i)
+			next.want[j] = -i
+		case len(base.want) > 0:
+			next.v, _ = base.v.Pop()
+			next.want = next.want[:len(next.want)-1]
+		default:
+			continue
+		}
+		versions = append(versions, next)
+	}
+	for _, ver := range versions {
+		assert.Equal(t, len(ver.want), ver.v.Size())
+		if len(ver.want) > 0 {
+			assert.Equal(t, ver.want, ver.v.Values())
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package common
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// Hasher is a function that maps a value to a 64-bit hash.
+// Equal values must produce equal hashes.
+type Hasher[T any] func(T) uint64
+
+// Mix64 scrambles the bits of x so that every input bit affects every output bit.
+// It is the finalizer of SplitMix64 and is used to spread weak hashes over all 64 bits.
+func Mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// IntegerHasher hashes any integer type.
+func IntegerHasher[T Integer](v T) uint64 {
+	return Mix64(uint64(v))
+}
+
+var (
+	IntHasher = func(v int) uint64 {
+		return Mix64(uint64(v))
+	}
+	StringHasher = func(s string) uint64 {
+		h := uint64(fnvOffset64)
+		for i := 0; i < len(s); i++ {
+			h ^= uint64(s[i])
+			h *= fnvPrime64
+		}
+		return Mix64(h)
+	}
+	BytesHasher = func(b []byte) uint64 {
+		h := uint64(fnvOffset64)
+		for _, c := range b {
+			h ^= uint64(c)
+			h *= fnvPrime64
+		}
+		return Mix64(h)
+	}
+)
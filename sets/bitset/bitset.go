@@ -0,0 +1,227 @@
+// Package bitset implements a growable set of non-negative integers backed by a bit array.
+//
+// Bit i of the set lives in bit i%64 of word i/64, so membership costs one word access and
+// the set operations work on whole 64-bit words. Memory is proportional to the largest
+// element, which makes the structure ideal for dense integer IDs.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Bit_array
+package bitset
+
+import "math/bits"
+
+const (
+	wordSize = 64
+	log2Word = 6
+)
+
+// BitSet is a growable bit array.
+type BitSet struct {
+	words []uint64
+}
+
+// New returns a new bit set with room for bits 0 to n-1 before it has to grow.
+func New(n uint) *BitSet {
+	return &BitSet{words: make([]uint64, wordsFor(n))}
+}
+
+// From returns a new bit set with the given bits set.
+func From(indexes ...uint) *BitSet {
+	b := &BitSet{}
+	for _, i := range indexes {
+		b.Set(i)
+	}
+	return b
+}
+
+// wordsFor returns the number of words needed to hold n bits.
+func wordsFor(n uint) int {
+	return int((n + wordSize - 1) >> log2Word)
+}
+
+// grow makes sure bit i is addressable.
+func (b *BitSet) grow(i uint) {
+	need := int(i>>log2Word) + 1
+	if need <= len(b.words) {
+		return
+	}
+	if need <= cap(b.words) {
+		b.words = b.words[:need]
+		return
+	}
+	words := make([]uint64, need, need+need>>1)
+	copy(words, b.words)
+	b.words = words
+}
+
+// Set sets bit i, growing the set if needed.
+func (b *BitSet) Set(i uint) {
+	b.grow(i)
+	b.words[i>>log2Word] |= 1 << (i & (wordSize - 1))
+}
+
+// Clear clears bit i.
+func (b *BitSet) Clear(i uint) {
+	if w := int(i >> log2Word); w < len(b.words) {
+		b.words[w] &^= 1 << (i & (wordSize - 1))
+	}
+}
+
+// Flip toggles bit i, growing the set if needed.
+func (b *BitSet) Flip(i uint) {
+	b.grow(i)
+	b.words[i>>log2Word] ^= 1 << (i & (wordSize - 1))
+}
+
+// Test returns true if bit i is set.
+func (b *BitSet) Test(i uint) bool {
+	w := int(i >> log2Word)
+	return w < len(b.words) && b.words[w]&(1<<(i&(wordSize-1))) != 0
+}
+
+// Count returns the number of set bits.
+func (b *BitSet) Count() int {
+	count := 0
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// Len returns the number of addressable bits, a multiple of 64.
+func (b *BitSet) Len() uint {
+	return uint(len(b.words)) << log2Word
+}
+
+// Empty returns true if no bit is set.
+func (b *BitSet) Empty() bool {
+	for _, w := range b.words {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ClearAll clears every bit, keeping the allocated words.
+func (b *BitSet) ClearAll() {
+	for i := range b.words {
+		b.words[i] = 0
+	}
+}
+
+// NextSet returns the first set bit at or after i.
+// It returns false if there is no such bit.
+//
+// Iterate over all set bits with:
+//
+//	for i, ok := b.NextSet(0); ok; i, ok = b.NextSet(i + 1) {
+//		...
+//	}
+func (b *BitSet) NextSet(i uint) (uint, bool) {
+	w := int(i >> log2Word)
+	if w >= len(b.words) {
+		return 0, false
+	}
+	// Drop the bits below i in the first word.
+	word := b.words[w] >> (i & (wordSize - 1))
+	if word != 0 {
+		return i + uint(bits.TrailingZeros64(word)), true
+	}
+	for w++; w < len(b.words); w++ {
+		if b.words[w] != 0 {
+			return uint(w)<<log2Word + uint(bits.TrailingZeros64(b.words[w])), true
+		}
+	}
+	return 0, false
+}
+
+// Indexes returns all set bits in ascending order.
+func (b *BitSet) Indexes() []uint {
+	indexes := make([]uint, 0, b.Count())
+	for i, ok := b.NextSet(0); ok; i, ok = b.NextSet(i + 1) {
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// Clone returns a copy of the bit set.
+func (b *BitSet) Clone() *BitSet {
+	words := make([]uint64, len(b.words))
+	copy(words, b.words)
+	return &BitSet{words: words}
+}
+
+// Equal returns true if both sets contain the same bits, regardless of their lengths.
+func (b *BitSet) Equal(other *BitSet) bool {
+	short, long := b.words, other.words
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	for i, w := range short {
+		if w != long[i] {
+			return false
+		}
+	}
+	for _, w := range long[len(short):] {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// And returns the intersection of b and other.
+func (b *BitSet) And(other *BitSet) *BitSet {
+	n := len(b.words)
+	if len(other.words) < n {
+		n = len(other.words)
+	}
+	result := &BitSet{words: make([]uint64, n)}
+	for i := 0; i < n; i++ {
+		result.words[i] = b.words[i] & other.words[i]
+	}
+	return result
+}
+
+// Or returns the union of b and other.
+func (b *BitSet) Or(other *BitSet) *BitSet {
+	long, short := b.words, other.words
+	if len(short) > len(long) {
+		long, short = short, long
+	}
+	result := &BitSet{words: make([]uint64, len(long))}
+	copy(result.words, long)
+	for i, w := range short {
+		result.words[i] |= w
+	}
+	return result
+}
+
+// Xor returns the symmetric difference of b and other.
+func (b *BitSet) Xor(other *BitSet) *BitSet {
+	long, short := b.words, other.words
+	if len(short) > len(long) {
+		long, short = short, long
+	}
+	result := &BitSet{words: make([]uint64, len(long))}
+	copy(result.words, long)
+	for i, w := range short {
+		result.words[i] ^= w
+	}
+	return result
+}
+
+// AndNot returns the bits of b that are not set in other.
+func (b *BitSet) AndNot(other *BitSet) *BitSet {
+	result := b.Clone()
+	n := len(result.words)
+	if len(other.words) < n {
+		n = len(other.words)
+	}
+	for i := 0; i < n; i++ {
+		result.words[i] &^= other.words[i]
+	}
+	return result
+}
@@ -0,0 +1,104 @@
+package bitset
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitSet_SetClearTest(t *testing.T) {
+	b := New(10)
+	assert.Equal(t, uint(64), b.Len())
+	assert.True(t, b.Empty())
+
+	b.Set(3)
+	b.Set(64)
+	b.Set(1000)
+	assert.True(t, b.Test(3))
+	assert.True(t, b.Test(64))
+	assert.True(t, b.Test(1000))
+	assert.False(t, b.Test(4))
+	assert.False(t, b.Test(5000))
+	assert.Equal(t, 3, b.Count())
+	assert.Equal(t, uint(1024), b.Len())
+
+	b.Clear(64)
+	b.Clear(100000)
+	assert.False(t, b.Test(64))
+	assert.Equal(t, 2, b.Count())
+
+	b.Flip(3)
+	b.Flip(4)
+	assert.Equal(t, []uint{4, 1000}, b.Indexes())
+
+	b.ClearAll()
+	assert.True(t, b.Empty())
+}
+
+func TestBitSet_NextSet(t *testing.T) {
+	b := From(0, 63, 64, 130)
+
+	tests := []struct {
+		from  uint
+		want  uint
+		found bool
+	}{
+		{from: 0, want: 0, found: true},
+		{from: 1, want: 63, found: true},
+		{from: 64, want: 64, found: true},
+		{from: 65, want: 130, found: true},
+		{from: 131, found: false},
+		{from: 1 << 20, found: false},
+	}
+	for _, tt := range tests {
+		got, found := b.NextSet(tt.from)
+		assert.Equal(t, tt.found, found, "from %d", tt.from)
+		assert.Equal(t, tt.want, got, "from %d", tt.from)
+	}
+}
+
+func TestBitSet_Operations(t *testing.T) {
+	a := From(1, 2, 3, 200)
+	b := From(2, 3, 4)
+
+	assert.Equal(t, []uint{2, 3}, a.And(b).Indexes())
+	assert.Equal(t, []uint{1, 2, 3, 4, 200}, a.Or(b).Indexes())
+	assert.Equal(t, []uint{1, 4, 200}, a.Xor(b).Indexes())
+	assert.Equal(t, []uint{1, 200}, a.AndNot(b).Indexes())
+	assert.Equal(t, []uint{4}, b.AndNot(a).Indexes())
+	assert.Equal(t, []uint{1, 2, 3, 200}, a.Indexes(), "operands must not change")
+}
+
+func TestBitSet_Equal(t *testing.T) {
+	a := From(1, 70)
+	b := New(1024)
+	b.Set(1)
+	b.Set(70)
+	assert.True(t, a.Equal(b))
+	assert.True(t, b.Equal(a))
+
+	b.Set(900)
+	assert.False(t, a.Equal(b))
+	assert.True(t, a.Equal(a.Clone()))
+}
+
+func TestBitSet_MatchesMap(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	b := New(0)
+	want := make(map[uint]bool)
+	for i := 0; i < 10000; i++ {
+		x := uint(r.Intn(4096))
+		if r.Intn(3) == 0 {
+			b.Clear(x)
+			delete(want, x)
+		} else {
+			b.Set(x)
+			want[x] = true
+		}
+	}
+	assert.Equal(t, len(want), b.Count())
+	for _, x := range b.Indexes() {
+		assert.True(t, want[x])
+	}
+}
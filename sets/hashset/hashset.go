@@ -0,0 +1,127 @@
+// Package hashset implements a set backed by a hash map.
+//
+// Elements have no particular order; Add, Remove and Contains run in expected O(1).
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Set_(abstract_data_type)
+package hashset
+
+import "github.com/kwstars/goads/sets"
+
+var _ sets.Set[int] = (*Set[int])(nil)
+
+// Set is a hash set.
+type Set[T comparable] struct {
+	items map[T]struct{}
+}
+
+// New returns a new hash set holding the given items.
+func New[T comparable](items ...T) *Set[T] {
+	s := &Set[T]{items: make(map[T]struct{}, len(items))}
+	s.Add(items...)
+	return s
+}
+
+// Add adds the items to the set.
+func (s *Set[T]) Add(items ...T) {
+	for _, item := range items {
+		s.items[item] = struct{}{}
+	}
+}
+
+// Remove removes the items from the set.
+func (s *Set[T]) Remove(items ...T) {
+	for _, item := range items {
+		delete(s.items, item)
+	}
+}
+
+// Contains returns true if all items are present in the set.
+func (s *Set[T]) Contains(items ...T) bool {
+	for _, item := range items {
+		if _, ok := s.items[item]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Values returns all elements of the set in no particular order.
+func (s *Set[T]) Values() []T {
+	values := make([]T, 0, len(s.items))
+	for item := range s.items {
+		values = append(values, item)
+	}
+	return values
+}
+
+// Union returns a set with the elements that are in either set.
+func (s *Set[T]) Union(other sets.Set[T]) sets.Set[T] {
+	result := New[T](s.Values()...)
+	result.Add(other.Values()...)
+	return result
+}
+
+// Intersection returns a set with the elements that are in both sets.
+func (s *Set[T]) Intersection(other sets.Set[T]) sets.Set[T] {
+	result := New[T]()
+	for item := range s.items {
+		if other.Contains(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+// Difference returns a set with the elements of s that are not in other.
+func (s *Set[T]) Difference(other sets.Set[T]) sets.Set[T] {
+	result := New[T]()
+	for item := range s.items {
+		if !other.Contains(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+// SymmetricDifference returns a set with the elements that are in exactly one of the sets.
+func (s *Set[T]) SymmetricDifference(other sets.Set[T]) sets.Set[T] {
+	result := s.Difference(other)
+	for _, item := range other.Values() {
+		if !s.Contains(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+// IsSubset returns true if every element of s is in other.
+func (s *Set[T]) IsSubset(other sets.Set[T]) bool {
+	if s.Size() > other.Size() {
+		return false
+	}
+	for item := range s.items {
+		if !other.Contains(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// Empty returns true if the set is empty.
+func (s *Set[T]) Empty() bool {
+	return len(s.items) == 0
+}
+
+// Size returns the number of elements in the set.
+func (s *Set[T]) Size() int {
+	return len(s.items)
+}
+
+// Clear removes all elements from the set.
+func (s *Set[T]) Clear() {
+	for item := range s.items {
+		delete(s.items, item)
+	}
+}
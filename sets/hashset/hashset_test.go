@@ -0,0 +1,82 @@
+package hashset
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sorted(s []int) []int {
+	sort.Ints(s)
+	return s
+}
+
+func TestSet_AddRemoveContains(t *testing.T) {
+	s := New[int]()
+	assert.True(t, s.Empty())
+
+	s.Add(1, 2, 3, 2)
+	assert.Equal(t, 3, s.Size())
+	assert.True(t, s.Contains(1, 3))
+	assert.False(t, s.Contains(1, 4))
+	assert.True(t, s.Contains())
+
+	s.Remove(2, 5)
+	assert.Equal(t, []int{1, 3}, sorted(s.Values()))
+
+	s.Clear()
+	assert.True(t, s.Empty())
+}
+
+func TestSet_Operations(t *testing.T) {
+	tests := []struct {
+		name                string
+		a, b                []int
+		union, intersection []int
+		difference, symDiff []int
+		isSubset            bool
+	}{
+		{
+			name:         "overlapping",
+			a:            []int{1, 2, 3},
+			b:            []int{2, 3, 4},
+			union:        []int{1, 2, 3, 4},
+			intersection: []int{2, 3},
+			difference:   []int{1},
+			symDiff:      []int{1, 4},
+		},
+		{
+			name:         "subset",
+			a:            []int{1, 2},
+			b:            []int{1, 2, 3},
+			union:        []int{1, 2, 3},
+			intersection: []int{1, 2},
+			difference:   []int{},
+			symDiff:      []int{3},
+			isSubset:     true,
+		},
+		{
+			name:         "empty receiver",
+			a:            []int{},
+			b:            []int{1},
+			union:        []int{1},
+			intersection: []int{},
+			difference:   []int{},
+			symDiff:      []int{1},
+			isSubset:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := New(tt.a...), New(tt.b...)
+			assert.Equal(t, tt.union, sorted(a.Union(b).Values()))
+			assert.Equal(t, tt.intersection, sorted(a.Intersection(b).Values()))
+			assert.Equal(t, tt.difference, sorted(a.Difference(b).Values()))
+			assert.Equal(t, tt.symDiff, sorted(a.SymmetricDifference(b).Values()))
+			assert.Equal(t, tt.isSubset, a.IsSubset(b))
+			assert.Equal(t, tt.a, sorted(a.Values()), "receiver must not change")
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Package linkedhashset implements a set that remembers insertion order.
+//
+// A hash map gives O(1) membership tests and points into a doubly linked list
+// that keeps the elements in the order they were first added. Re-adding an element does not move it.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Set_(abstract_data_type)
+package linkedhashset
+
+import (
+	"container/list"
+
+	"github.com/kwstars/goads/sets"
+)
+
+var _ sets.Set[int] = (*Set[int])(nil)
+
+// Set is an insertion-ordered hash set.
+type Set[T comparable] struct {
+	items map[T]*list.Element // hashmap for quick lookups
+	order *list.List          // doubly linked list in insertion order
+}
+
+// New returns a new linked hash set holding the given items in order.
+func New[T comparable](items ...T) *Set[T] {
+	s := &Set[T]{
+		items: make(map[T]*list.Element, len(items)),
+		order: list.New(),
+	}
+	s.Add(items...)
+	return s
+}
+
+// Add appends the items that are not yet present to the end of the set.
+func (s *Set[T]) Add(items ...T) {
+	for _, item := range items {
+		if _, ok := s.items[item]; !ok {
+			s.items[item] = s.order.PushBack(item)
+		}
+	}
+}
+
+// Remove removes the items from the set.
+func (s *Set[T]) Remove(items ...T) {
+	for _, item := range items {
+		if element, ok := s.items[item]; ok {
+			s.order.Remove(element)
+			delete(s.items, item)
+		}
+	}
+}
+
+// Contains returns true if all items are present in the set.
+func (s *Set[T]) Contains(items ...T) bool {
+	for _, item := range items {
+		if _, ok := s.items[item]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Values returns all elements of the set in insertion order.
+func (s *Set[T]) Values() []T {
+	values := make([]T, 0, len(s.items))
+	for e := s.order.Front(); e != nil; e = e.Next() {
+		values = append(values, e.Value.(T))
+	}
+	return values
+}
+
+// Union returns a set with the elements of s followed by the new elements of other.
+func (s *Set[T]) Union(other sets.Set[T]) sets.Set[T] {
+	result := New[T](s.Values()...)
+	result.Add(other.Values()...)
+	return result
+}
+
+// Intersection returns a set with the elements that are in both sets, in the order of s.
+func (s *Set[T]) Intersection(other sets.Set[T]) sets.Set[T] {
+	result := New[T]()
+	for e := s.order.Front(); e != nil; e = e.Next() {
+		if other.Contains(e.Value.(T)) {
+			result.Add(e.Value.(T))
+		}
+	}
+	return result
+}
+
+// Difference returns a set with the elements of s that are not in other, in the order of s.
+func (s *Set[T]) Difference(other sets.Set[T]) sets.Set[T] {
+	result := New[T]()
+	for e := s.order.Front(); e != nil; e = e.Next() {
+		if !other.Contains(e.Value.(T)) {
+			result.Add(e.Value.(T))
+		}
+	}
+	return result
+}
+
+// SymmetricDifference returns the elements of s missing from other, followed by the elements of other missing from s.
+func (s *Set[T]) SymmetricDifference(other sets.Set[T]) sets.Set[T] {
+	result := s.Difference(other)
+	for _, item := range other.Values() {
+		if !s.Contains(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+// IsSubset returns true if every element of s is in other.
+func (s *Set[T]) IsSubset(other sets.Set[T]) bool {
+	if s.Size() > other.Size() {
+		return false
+	}
+	for item := range s.items {
+		if !other.Contains(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// Empty returns true if the set is empty.
+func (s *Set[T]) Empty() bool {
+	return len(s.items) == 0
+}
+
+// Size returns the number of elements in the set.
+func (s *Set[T]) Size() int {
+	return len(s.items)
+}
+
+// Clear removes all elements from the set.
+func (s *Set[T]) Clear() {
+	for item := range s.items {
+		delete(s.items, item)
+	}
+	s.order.Init()
+}
@@ -0,0 +1,44 @@
+package linkedhashset
+
+import (
+	"testing"
+
+	"github.com/kwstars/goads/sets/hashset"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet_InsertionOrder(t *testing.T) {
+	s := New(3, 1, 2)
+	s.Add(1, 5)
+	assert.Equal(t, []int{3, 1, 2, 5}, s.Values())
+
+	s.Remove(1, 9)
+	s.Add(1)
+	assert.Equal(t, []int{3, 2, 5, 1}, s.Values())
+	assert.True(t, s.Contains(5, 1))
+	assert.False(t, s.Contains(4))
+	assert.Equal(t, 4, s.Size())
+
+	s.Clear()
+	assert.True(t, s.Empty())
+	assert.Empty(t, s.Values())
+	s.Add(7)
+	assert.Equal(t, []int{7}, s.Values())
+}
+
+func TestSet_Operations(t *testing.T) {
+	a := New(4, 1, 3, 2)
+	b := New(5, 3, 4)
+
+	assert.Equal(t, []int{4, 1, 3, 2, 5}, a.Union(b).Values())
+	assert.Equal(t, []int{4, 3}, a.Intersection(b).Values())
+	assert.Equal(t, []int{1, 2}, a.Difference(b).Values())
+	assert.Equal(t, []int{1, 2, 5}, a.SymmetricDifference(b).Values())
+	assert.False(t, a.IsSubset(b))
+	assert.True(t, New(3, 4).IsSubset(a))
+
+	// Operands may be of a different implementation.
+	h := hashset.New(1, 2)
+	assert.Equal(t, []int{4, 3}, a.Difference(h).Values())
+	assert.True(t, New(2, 1).IsSubset(h))
+}
@@ -0,0 +1,309 @@
+package roaring
+
+import (
+	"math/bits"
+	"sort"
+)
+
+const (
+	// arrayMaxSize is the largest cardinality stored in an array container.
+	// Beyond it a bitmap container (8 KiB) is smaller than an array of uint16.
+	arrayMaxSize = 4096
+	bitmapWords  = 1 << 16 / 64
+)
+
+// words is the uncompressed form of a container: one bit for each of the 65536 low values.
+type words = [bitmapWords]uint64
+
+// container stores the low 16 bits of the values that share the same high 16 bits.
+// Mutating methods return the container to keep, which may have changed representation.
+type container interface {
+	add(x uint16) container
+	remove(x uint16) container
+	contains(x uint16) bool
+	cardinality() int
+	// each calls fn for every value in ascending order and stops when fn returns false.
+	// It reports whether the iteration ran to completion.
+	each(fn func(x uint16) bool) bool
+	toWords(w *words)
+	clone() container
+	// runs returns the number of runs of consecutive values.
+	runs() int
+}
+
+// fromWords returns the most compact array or bitmap container for w, or nil if w is empty.
+func fromWords(w *words) container {
+	card := 0
+	for _, word := range w {
+		card += bits.OnesCount64(word)
+	}
+	if card == 0 {
+		return nil
+	}
+	if card > arrayMaxSize {
+		return &bitmapContainer{words: *w, card: card}
+	}
+	a := make(arrayContainer, 0, card)
+	for i, word := range w {
+		for word != 0 {
+			a = append(a, uint16(i<<6+bits.TrailingZeros64(word)))
+			word &= word - 1
+		}
+	}
+	return &a
+}
+
+// optimize returns the smallest of the array, bitmap and run representations of c.
+func optimize(c container) container {
+	card, runs := c.cardinality(), c.runs()
+	arraySize, bitmapSize, runSize := 2*card, 8*bitmapWords, 2+4*runs
+	if runSize < arraySize && runSize < bitmapSize {
+		if _, ok := c.(*runContainer); ok {
+			return c
+		}
+		return newRunContainer(c)
+	}
+	var w words
+	c.toWords(&w)
+	return fromWords(&w)
+}
+
+// arrayContainer is a sorted array of values, used for sparse containers.
+type arrayContainer []uint16
+
+func (a *arrayContainer) search(x uint16) int {
+	s := *a
+	return sort.Search(len(s), func(i int) bool { return s[i] >= x })
+}
+
+func (a *arrayContainer) add(x uint16) container {
+	i := a.search(x)
+	if i < len(*a) && (*a)[i] == x {
+		return a
+	}
+	if len(*a) >= arrayMaxSize {
+		b := &bitmapContainer{}
+		a.toWords(&b.words)
+		b.card = len(*a)
+		return b.add(x)
+	}
+	*a = append(*a, 0)
+	copy((*a)[i+1:], (*a)[i:])
+	(*a)[i] = x
+	return a
+}
+
+func (a *arrayContainer) remove(x uint16) container {
+	i := a.search(x)
+	if i == len(*a) || (*a)[i] != x {
+		return a
+	}
+	*a = append((*a)[:i], (*a)[i+1:]...)
+	if len(*a) == 0 {
+		return nil
+	}
+	return a
+}
+
+func (a *arrayContainer) contains(x uint16) bool {
+	i := a.search(x)
+	return i < len(*a) && (*a)[i] == x
+}
+
+func (a *arrayContainer) cardinality() int {
+	return len(*a)
+}
+
+func (a *arrayContainer) each(fn func(x uint16) bool) bool {
+	for _, x := range *a {
+		if !fn(x) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *arrayContainer) toWords(w *words) {
+	for _, x := range *a {
+		w[x>>6] |= 1 << (x & 63)
+	}
+}
+
+func (a *arrayContainer) clone() container {
+	c := make(arrayContainer, len(*a))
+	copy(c, *a)
+	return &c
+}
+
+func (a *arrayContainer) runs() int {
+	runs := 0
+	for i, x := range *a {
+		if i == 0 || (*a)[i-1]+1 != x {
+			runs++
+		}
+	}
+	return runs
+}
+
+// bitmapContainer is an uncompressed bit array, used for dense containers.
+type bitmapContainer struct {
+	words words
+	card  int
+}
+
+func (b *bitmapContainer) add(x uint16) container {
+	mask := uint64(1) << (x & 63)
+	if b.words[x>>6]&mask == 0 {
+		b.words[x>>6] |= mask
+		b.card++
+	}
+	return b
+}
+
+func (b *bitmapContainer) remove(x uint16) container {
+	mask := uint64(1) << (x & 63)
+	if b.words[x>>6]&mask == 0 {
+		return b
+	}
+	b.words[x>>6] &^= mask
+	b.card--
+	if b.card <= arrayMaxSize {
+		return fromWords(&b.words)
+	}
+	return b
+}
+
+func (b *bitmapContainer) contains(x uint16) bool {
+	return b.words[x>>6]&(1<<(x&63)) != 0
+}
+
+func (b *bitmapContainer) cardinality() int {
+	return b.card
+}
+
+func (b *bitmapContainer) each(fn func(x uint16) bool) bool {
+	for i, word := range b.words {
+		for word != 0 {
+			if !fn(uint16(i<<6 + bits.TrailingZeros64(word))) {
+				return false
+			}
+			word &= word - 1
+		}
+	}
+	return true
+}
+
+func (b *bitmapContainer) toWords(w *words) {
+	for i, word := range b.words {
+		w[i] |= word
+	}
+}
+
+func (b *bitmapContainer) clone() container {
+	c := *b
+	return &c
+}
+
+func (b *bitmapContainer) runs() int {
+	runs := 0
+	for i, word := range b.words {
+		// A run starts at every set bit whose predecessor is clear.
+		carry := uint64(0)
+		if i > 0 {
+			carry = b.words[i-1] >> 63
+		}
+		runs += bits.OnesCount64(word &^ (word<<1 | carry))
+	}
+	return runs
+}
+
+// run is an interval [start, start+length] of consecutive values.
+type run struct {
+	start  uint16
+	length uint16
+}
+
+func (r run) last() uint16 {
+	return r.start + r.length
+}
+
+// runContainer is a sorted list of runs, used for containers made of long intervals.
+// It is produced by RunOptimize; modifying it converts it back to an array or bitmap container.
+type runContainer []run
+
+func newRunContainer(c container) *runContainer {
+	r := make(runContainer, 0, c.runs())
+	c.each(func(x uint16) bool {
+		if n := len(r); n > 0 && r[n-1].last()+1 == x {
+			r[n-1].length++
+		} else {
+			r = append(r, run{start: x})
+		}
+		return true
+	})
+	return &r
+}
+
+// expand converts the container into an array or bitmap container.
+func (r *runContainer) expand() container {
+	var w words
+	r.toWords(&w)
+	return fromWords(&w)
+}
+
+func (r *runContainer) add(x uint16) container {
+	if r.contains(x) {
+		return r
+	}
+	return r.expand().add(x)
+}
+
+func (r *runContainer) remove(x uint16) container {
+	if !r.contains(x) {
+		return r
+	}
+	return r.expand().remove(x)
+}
+
+func (r *runContainer) contains(x uint16) bool {
+	s := *r
+	i := sort.Search(len(s), func(i int) bool { return s[i].last() >= x })
+	return i < len(s) && s[i].start <= x
+}
+
+func (r *runContainer) cardinality() int {
+	card := 0
+	for _, rn := range *r {
+		card += int(rn.length) + 1
+	}
+	return card
+}
+
+func (r *runContainer) each(fn func(x uint16) bool) bool {
+	for _, rn := range *r {
+		for x := int(rn.start); x <= int(rn.last()); x++ {
+			if !fn(uint16(x)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (r *runContainer) toWords(w *words) {
+	for _, rn := range *r {
+		for x := int(rn.start); x <= int(rn.last()); x++ {
+			w[x>>6] |= 1 << (x & 63)
+		}
+	}
+}
+
+func (r *runContainer) clone() container {
+	c := make(runContainer, len(*r))
+	copy(c, *r)
+	return &c
+}
+
+func (r *runContainer) runs() int {
+	return len(*r)
+}
@@ -0,0 +1,243 @@
+// Package roaring implements a compressed bitmap of uint32 values (Roaring bitmap).
+//
+// The 32-bit space is split into chunks of 65536 values that share their high 16 bits.
+// Each non-empty chunk is stored in the container that suits its content best:
+//   - an array container, a sorted []uint16, while it holds at most 4096 values;
+//   - a bitmap container, a fixed 8 KiB bit array, when it is denser;
+//   - a run container, a list of intervals, after RunOptimize found it to be the smallest.
+//
+// Set operations combine containers chunk by chunk and pick the best representation for the result.
+//
+// Structure is not thread safe.
+//
+// References: https://roaringbitmap.org/ and https://arxiv.org/abs/1603.06549
+package roaring
+
+import (
+	"github.com/kwstars/goads/binarysearch"
+	"github.com/kwstars/goads/containers"
+)
+
+var _ containers.Container[uint32] = (*Bitmap)(nil)
+
+// Bitmap is a compressed set of uint32 values.
+type Bitmap struct {
+	keys       []uint16    // keys are the sorted high 16 bits of the chunks.
+	containers []container // containers[i] holds the low 16 bits of chunk keys[i].
+}
+
+var keyComparator = func(a, b uint16) int8 {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// New returns a new bitmap holding the given values.
+func New(values ...uint32) *Bitmap {
+	b := &Bitmap{}
+	for _, v := range values {
+		b.Add(v)
+	}
+	return b
+}
+
+func split(x uint32) (uint16, uint16) {
+	return uint16(x >> 16), uint16(x)
+}
+
+// index returns the position of key, or -1.
+func (b *Bitmap) index(key uint16) int {
+	return binarysearch.FindExact(b.keys, key, keyComparator)
+}
+
+// Add adds x to the bitmap.
+func (b *Bitmap) Add(x uint32) {
+	hi, lo := split(x)
+	if i := b.index(hi); i >= 0 {
+		b.containers[i] = b.containers[i].add(lo)
+		return
+	}
+
+	i := binarysearch.FindFirstGreaterOrEqual(b.keys, hi, keyComparator)
+	if i < 0 {
+		i = len(b.keys)
+	}
+	a := arrayContainer{lo}
+	b.keys = append(b.keys, 0)
+	copy(b.keys[i+1:], b.keys[i:])
+	b.keys[i] = hi
+	b.containers = append(b.containers, nil)
+	copy(b.containers[i+1:], b.containers[i:])
+	b.containers[i] = &a
+}
+
+// Remove removes x from the bitmap.
+func (b *Bitmap) Remove(x uint32) {
+	hi, lo := split(x)
+	i := b.index(hi)
+	if i < 0 {
+		return
+	}
+	if c := b.containers[i].remove(lo); c != nil {
+		b.containers[i] = c
+		return
+	}
+	b.keys = append(b.keys[:i], b.keys[i+1:]...)
+	b.containers = append(b.containers[:i], b.containers[i+1:]...)
+}
+
+// Contains returns true if x is in the bitmap.
+func (b *Bitmap) Contains(x uint32) bool {
+	hi, lo := split(x)
+	i := b.index(hi)
+	return i >= 0 && b.containers[i].contains(lo)
+}
+
+// Cardinality returns the number of values in the bitmap.
+func (b *Bitmap) Cardinality() uint64 {
+	var card uint64
+	for _, c := range b.containers {
+		card += uint64(c.cardinality())
+	}
+	return card
+}
+
+// Empty returns true if the bitmap holds no values.
+func (b *Bitmap) Empty() bool {
+	return len(b.keys) == 0
+}
+
+// Size returns the number of values in the bitmap.
+// Use Cardinality when the count may exceed the range of int.
+func (b *Bitmap) Size() int {
+	return int(b.Cardinality())
+}
+
+// Clear removes all values from the bitmap.
+func (b *Bitmap) Clear() {
+	b.keys = nil
+	b.containers = nil
+}
+
+// Each calls fn for every value in ascending order until fn returns false.
+func (b *Bitmap) Each(fn func(x uint32) bool) {
+	for i, c := range b.containers {
+		hi := uint32(b.keys[i]) << 16
+		if !c.each(func(lo uint16) bool { return fn(hi | uint32(lo)) }) {
+			return
+		}
+	}
+}
+
+// ToArray returns all values in ascending order.
+func (b *Bitmap) ToArray() []uint32 {
+	values := make([]uint32, 0, b.Cardinality())
+	b.Each(func(x uint32) bool {
+		values = append(values, x)
+		return true
+	})
+	return values
+}
+
+// Clone returns a deep copy of the bitmap.
+func (b *Bitmap) Clone() *Bitmap {
+	c := &Bitmap{
+		keys:       make([]uint16, len(b.keys)),
+		containers: make([]container, len(b.containers)),
+	}
+	copy(c.keys, b.keys)
+	for i, ct := range b.containers {
+		c.containers[i] = ct.clone()
+	}
+	return c
+}
+
+// Equal returns true if both bitmaps hold the same values.
+func (b *Bitmap) Equal(other *Bitmap) bool {
+	if len(b.keys) != len(other.keys) {
+		return false
+	}
+	for i := range b.keys {
+		if b.keys[i] != other.keys[i] {
+			return false
+		}
+		var x, y words
+		b.containers[i].toWords(&x)
+		other.containers[i].toWords(&y)
+		if x != y {
+			return false
+		}
+	}
+	return true
+}
+
+// RunOptimize converts every container to its smallest representation, using run containers
+// where values form long intervals.
+func (b *Bitmap) RunOptimize() {
+	for i, c := range b.containers {
+		b.containers[i] = optimize(c)
+	}
+}
+
+// combine merges b and other chunk by chunk.
+// op combines the words of chunks present in both bitmaps; onlyB and onlyOther tell whether chunks
+// present in only one of them are kept.
+func (b *Bitmap) combine(other *Bitmap, op func(x, y uint64) uint64, onlyB, onlyOther bool) *Bitmap {
+	result := &Bitmap{}
+	push := func(key uint16, c container) {
+		if c != nil {
+			result.keys = append(result.keys, key)
+			result.containers = append(result.containers, c)
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(b.keys) || j < len(other.keys) {
+		switch {
+		case j == len(other.keys) || (i < len(b.keys) && b.keys[i] < other.keys[j]):
+			if onlyB {
+				push(b.keys[i], b.containers[i].clone())
+			}
+			i++
+		case i == len(b.keys) || other.keys[j] < b.keys[i]:
+			if onlyOther {
+				push(other.keys[j], other.containers[j].clone())
+			}
+			j++
+		default:
+			var x, y words
+			b.containers[i].toWords(&x)
+			other.containers[j].toWords(&y)
+			for k := range x {
+				x[k] = op(x[k], y[k])
+			}
+			push(b.keys[i], fromWords(&x))
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// And returns the intersection of b and other.
+func (b *Bitmap) And(other *Bitmap) *Bitmap {
+	return b.combine(other, func(x, y uint64) uint64 { return x & y }, false, false)
+}
+
+// Or returns the union of b and other.
+func (b *Bitmap) Or(other *Bitmap) *Bitmap {
+	return b.combine(other, func(x, y uint64) uint64 { return x | y }, true, true)
+}
+
+// Xor returns the symmetric difference of b and other.
+func (b *Bitmap) Xor(other *Bitmap) *Bitmap {
+	return b.combine(other, func(x, y uint64) uint64 { return x ^ y }, true, true)
+}
+
+// AndNot returns the values of b that are not in other.
+func (b *Bitmap) AndNot(other *Bitmap) *Bitmap {
+	return b.combine(other, func(x, y uint64) uint64 { return x &^ y }, true, false)
+}
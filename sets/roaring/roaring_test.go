@@ -0,0 +1,222 @@
+package roaring
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// randomBitmap returns a bitmap mixing sparse, dense and interval chunks, and its expected content.
+func randomBitmap(r *rand.Rand) (*Bitmap, map[uint32]bool) {
+	b := New()
+	want := make(map[uint32]bool)
+	add := func(x uint32) {
+		b.Add(x)
+		want[x] = true
+	}
+	for i := 0; i < 1000; i++ {
+		add(r.Uint32()) // sparse
+	}
+	for i := 0; i < 20000; i++ {
+		add(1<<16 | uint32(r.Intn(1<<16))) // dense
+	}
+	for x := uint32(5 << 16); x < 5<<16+10000; x++ {
+		add(x) // one long run
+	}
+	return b, want
+}
+
+func keysOf(m map[uint32]bool) []uint32 {
+	keys := make([]uint32, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+func TestBitmap_AddRemoveContains(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	b, want := randomBitmap(r)
+
+	assert.Equal(t, uint64(len(want)), b.Cardinality())
+	assert.Equal(t, keysOf(want), b.ToArray())
+
+	// Remove values until the dense chunk falls back to an array container.
+	for x := range want {
+		if x>>16 == 1 && r.Intn(10) < 9 {
+			b.Remove(x)
+			delete(want, x)
+		}
+	}
+	b.Remove(0xffffffff)
+	i := b.index(1)
+	_, isArray := b.containers[i].(*arrayContainer)
+	assert.True(t, isArray)
+	assert.Equal(t, keysOf(want), b.ToArray())
+
+	for x := range want {
+		assert.True(t, b.Contains(x))
+	}
+	assert.False(t, b.Contains(7<<16))
+}
+
+func TestBitmap_ContainerTransitions(t *testing.T) {
+	b := New()
+	for x := uint32(0); x < arrayMaxSize; x++ {
+		b.Add(x * 2)
+	}
+	_, isArray := b.containers[0].(*arrayContainer)
+	assert.True(t, isArray)
+
+	b.Add(1)
+	_, isBitmap := b.containers[0].(*bitmapContainer)
+	assert.True(t, isBitmap)
+	assert.Equal(t, arrayMaxSize+1, b.Size())
+
+	b.Remove(1)
+	_, isArray = b.containers[0].(*arrayContainer)
+	assert.True(t, isArray)
+
+	for x := uint32(0); x < arrayMaxSize; x++ {
+		b.Remove(x * 2)
+	}
+	assert.True(t, b.Empty())
+	assert.Empty(t, b.keys)
+}
+
+func TestBitmap_RunOptimize(t *testing.T) {
+	b := New()
+	for x := uint32(100); x < 60000; x++ {
+		b.Add(x)
+	}
+	b.Add(70000)
+	before := b.ToArray()
+
+	b.RunOptimize()
+	_, isRun := b.containers[0].(*runContainer)
+	assert.True(t, isRun)
+	_, isArray := b.containers[1].(*arrayContainer)
+	assert.True(t, isArray)
+	assert.Equal(t, before, b.ToArray())
+	assert.True(t, b.Contains(100))
+	assert.False(t, b.Contains(99))
+	assert.Equal(t, uint64(len(before)), b.Cardinality())
+
+	// Mutating a run container converts it back.
+	b.Add(60001)
+	b.Remove(500)
+	assert.True(t, b.Contains(60001))
+	assert.False(t, b.Contains(500))
+	assert.Equal(t, uint64(len(before)), b.Cardinality())
+}
+
+func TestBitmap_Operations(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	a, wantA := randomBitmap(r)
+	b, wantB := randomBitmap(r)
+	b.RunOptimize()
+
+	and, or, xor, andNot := map[uint32]bool{}, map[uint32]bool{}, map[uint32]bool{}, map[uint32]bool{}
+	for x := range wantA {
+		or[x] = true
+		if wantB[x] {
+			and[x] = true
+		} else {
+			xor[x] = true
+			andNot[x] = true
+		}
+	}
+	for x := range wantB {
+		or[x] = true
+		if !wantA[x] {
+			xor[x] = true
+		}
+	}
+
+	assert.Equal(t, keysOf(and), a.And(b).ToArray())
+	assert.Equal(t, keysOf(or), a.Or(b).ToArray())
+	assert.Equal(t, keysOf(xor), a.Xor(b).ToArray())
+	assert.Equal(t, keysOf(andNot), a.AndNot(b).ToArray())
+	assert.Equal(t, keysOf(wantA), a.ToArray(), "operands must not change")
+	assert.True(t, a.Xor(a).Empty())
+}
+
+func TestBitmap_EqualCloneClear(t *testing.T) {
+	a := New(1, 2, 3, 1<<20)
+	c := a.Clone()
+	assert.True(t, a.Equal(c))
+	c.RunOptimize()
+	assert.True(t, a.Equal(c))
+
+	c.Add(4)
+	assert.False(t, a.Equal(c))
+	assert.False(t, a.Equal(New(1, 2, 3, 1<<21)))
+
+	c.Clear()
+	assert.True(t, c.Empty())
+	assert.Equal(t, 4, a.Size())
+
+	var visited []uint32
+	a.Each(func(x uint32) bool {
+		visited = append(visited, x)
+		return len(visited) < 2
+	})
+	assert.Equal(t, []uint32{1, 2}, visited)
+}
+
+func TestBitmap_MarshalBinary(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	b, _ := randomBitmap(r)
+	b.RunOptimize()
+
+	data, err := b.MarshalBinary()
+	assert.NoError(t, err)
+
+	decoded := New(42)
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.True(t, b.Equal(decoded))
+	for i := range b.containers {
+		assert.IsType(t, b.containers[i], decoded.containers[i])
+	}
+}
+
+func TestBitmap_StableEncoding(t *testing.T) {
+	data, err := New(1, 3, 1<<16|2).MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		0x52, 0x47, 0x42, 0x31, // cookie
+		0x02, 0x00, 0x00, 0x00, // two containers
+		0x00, 0x00, kindArray, 0x02, 0x00, 0x00, 0x00, 0x01, 0x00, 0x03, 0x00,
+		0x01, 0x00, kindArray, 0x01, 0x00, 0x00, 0x00, 0x02, 0x00,
+	}, data)
+}
+
+func TestBitmap_UnmarshalBinaryErrors(t *testing.T) {
+	valid, _ := New(1, 3).MarshalBinary()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "bad cookie", data: []byte{1, 2, 3, 4, 0, 0, 0, 0}},
+		{name: "truncated", data: valid[:len(valid)-1]},
+		{name: "trailing bytes", data: append(append([]byte{}, valid...), 0)},
+		{name: "unknown kind", data: []byte{0x52, 0x47, 0x42, 0x31, 1, 0, 0, 0, 0, 0, 9, 1, 0, 0, 0}},
+		{name: "too many runs", data: []byte{0x52, 0x47, 0x42, 0x31, 1, 0, 0, 0, 0, 0, kindRun, 0xff, 0xff, 0xff, 0xff}},
+		{name: "runs beyond data", data: []byte{0x52, 0x47, 0x42, 0x31, 1, 0, 0, 0, 0, 0, kindRun, 0x00, 0x80, 0, 0}},
+		{name: "unsorted array", data: []byte{0x52, 0x47, 0x42, 0x31, 1, 0, 0, 0, 0, 0, kindArray, 2, 0, 0, 0, 3, 0, 1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(7)
+			err := b.UnmarshalBinary(tt.data)
+			assert.True(t, errors.Is(err, ErrInvalidFormat), "got %v", err)
+			assert.Equal(t, []uint32{7}, b.ToArray(), "bitmap must not change on error")
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package roaring
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// The serialized format is little-endian and versioned by its cookie:
+//
+//	cookie      uint32  0x31424752 ("RGB1")
+//	count       uint32  number of containers
+//	count times:
+//	  key       uint16  high 16 bits of the chunk, strictly increasing
+//	  kind      uint8   1 = array, 2 = bitmap, 3 = run
+//	  n         uint32  array: number of values, bitmap: cardinality, run: number of runs
+//	  payload           array: n × uint16 values, bitmap: 1024 × uint64 words, run: n × (start, length uint16)
+//
+// The format is specific to this package and is not compatible with the portable Roaring format.
+const cookie uint32 = 0x31424752
+
+const (
+	kindArray uint8 = iota + 1
+	kindBitmap
+	kindRun
+)
+
+// maxRuns is the largest number of runs in a container: alternating values of a 16-bit chunk.
+const maxRuns = 1 << 16 / 2
+
+var ErrInvalidFormat = errors.New("invalid roaring bitmap encoding")
+
+// MarshalBinary encodes the bitmap, keeping the representation of every container.
+func (b *Bitmap) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, 8+len(b.keys)*16)
+	buf = appendUint32(buf, cookie)
+	buf = appendUint32(buf, uint32(len(b.keys)))
+
+	for i, c := range b.containers {
+		buf = appendUint16(buf, b.keys[i])
+		switch c := c.(type) {
+		case *arrayContainer:
+			buf = append(buf, kindArray)
+			buf = appendUint32(buf, uint32(len(*c)))
+			for _, x := range *c {
+				buf = appendUint16(buf, x)
+			}
+		case *bitmapContainer:
+			buf = append(buf, kindBitmap)
+			buf = appendUint32(buf, uint32(c.card))
+			for _, w := range c.words {
+				buf = appendUint64(buf, w)
+			}
+		case *runContainer:
+			buf = append(buf, kindRun)
+			buf = appendUint32(buf, uint32(len(*c)))
+			for _, r := range *c {
+				buf = appendUint16(buf, r.start)
+				buf = appendUint16(buf, r.length)
+			}
+		}
+	}
+	return buf, nil
+}
+
+func appendUint16(buf []byte, v uint16) []byte {
+	return append(buf, byte(v), byte(v>>8))
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	return appendUint32(appendUint32(buf, uint32(v)), uint32(v>>32))
+}
+
+// decoder reads little-endian values and remembers the first error.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) take(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if len(d.data) < n {
+		d.err = fmt.Errorf("%w: unexpected end of data", ErrInvalidFormat)
+		return nil
+	}
+	p := d.data[:n]
+	d.data = d.data[n:]
+	return p
+}
+
+func (d *decoder) uint8() uint8 {
+	if p := d.take(1); p != nil {
+		return p[0]
+	}
+	return 0
+}
+
+func (d *decoder) uint16() uint16 {
+	if p := d.take(2); p != nil {
+		return binary.LittleEndian.Uint16(p)
+	}
+	return 0
+}
+
+func (d *decoder) uint32() uint32 {
+	if p := d.take(4); p != nil {
+		return binary.LittleEndian.Uint32(p)
+	}
+	return 0
+}
+
+func (d *decoder) uint64() uint64 {
+	if p := d.take(8); p != nil {
+		return binary.LittleEndian.Uint64(p)
+	}
+	return 0
+}
+
+// UnmarshalBinary replaces the content of the bitmap with the decoded data.
+func (b *Bitmap) UnmarshalBinary(data []byte) error {
+	d := &decoder{data: data}
+	if d.uint32() != cookie {
+		if d.err != nil {
+			return d.err
+		}
+		return fmt.Errorf("%w: bad cookie", ErrInvalidFormat)
+	}
+	count := int(d.uint32())
+	if count > 1<<16 {
+		return fmt.Errorf("%w: %d containers", ErrInvalidFormat, count)
+	}
+
+	keys := make([]uint16, 0, count)
+	cs := make([]container, 0, count)
+	for i := 0; i < count && d.err == nil; i++ {
+		key, kind, n := d.uint16(), d.uint8(), int(d.uint32())
+		if i > 0 && key <= keys[i-1] {
+			return fmt.Errorf("%w: keys not increasing", ErrInvalidFormat)
+		}
+		c, err := decodeContainer(d, kind, n)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+		cs = append(cs, c)
+	}
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.data) != 0 {
+		return fmt.Errorf("%w: %d trailing bytes", ErrInvalidFormat, len(d.data))
+	}
+
+	b.keys, b.containers = keys, cs
+	return nil
+}
+
+func decodeContainer(d *decoder, kind uint8, n int) (container, error) {
+	switch kind {
+	case kindArray:
+		if n == 0 || n > arrayMaxSize {
+			return nil, fmt.Errorf("%w: array of %d values", ErrInvalidFormat, n)
+		}
+		a := make(arrayContainer, n)
+		for i := range a {
+			a[i] = d.uint16()
+			if i > 0 && a[i] <= a[i-1] && d.err == nil {
+				return nil, fmt.Errorf("%w: array values not increasing", ErrInvalidFormat)
+			}
+		}
+		return &a, d.err
+	case kindBitmap:
+		bc := &bitmapContainer{}
+		for i := range bc.words {
+			bc.words[i] = d.uint64()
+		}
+		if d.err != nil {
+			return nil, d.err
+		}
+		for _, w := range bc.words {
+			bc.card += bits.OnesCount64(w)
+		}
+		if bc.card != n || n <= arrayMaxSize {
+			return nil, fmt.Errorf("%w: bitmap cardinality mismatch", ErrInvalidFormat)
+		}
+		return bc, nil
+	case kindRun:
+		// Check the count before allocating: it comes straight from the input.
+		if n == 0 || n > maxRuns || n*4 > len(d.data) {
+			return nil, fmt.Errorf("%w: run container of %d runs", ErrInvalidFormat, n)
+		}
+		r := make(runContainer, n)
+		for i := range r {
+			r[i] = run{start: d.uint16(), length: d.uint16()}
+			if d.err != nil {
+				return nil, d.err
+			}
+			if int(r[i].start)+int(r[i].length) > 0xffff ||
+				(i > 0 && int(r[i].start) <= int(r[i-1].last())+1) {
+				return nil, fmt.Errorf("%w: invalid runs", ErrInvalidFormat)
+			}
+		}
+		return &r, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown container kind %d", ErrInvalidFormat, kind)
+	}
+}
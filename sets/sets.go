@@ -0,0 +1,30 @@
+package sets
+
+import "github.com/kwstars/goads/containers"
+
+// Set is a collection of unique elements.
+//
+// The set operations accept any Set implementation as argument and return a new set
+// of the same implementation (and configuration) as the receiver. Neither operand is modified.
+type Set[T any] interface {
+	containers.Container[T]
+	// Add adds the items to the set. Items already present are ignored.
+	Add(items ...T)
+	// Remove removes the items from the set. Items not present are ignored.
+	Remove(items ...T)
+	// Contains returns true if all items are present in the set.
+	Contains(items ...T) bool
+	// Values returns all elements of the set. The order is defined by the implementation.
+	Values() []T
+
+	// Union returns a set with the elements that are in either set.
+	Union(other Set[T]) Set[T]
+	// Intersection returns a set with the elements that are in both sets.
+	Intersection(other Set[T]) Set[T]
+	// Difference returns a set with the elements of this set that are not in other.
+	Difference(other Set[T]) Set[T]
+	// SymmetricDifference returns a set with the elements that are in exactly one of the sets.
+	SymmetricDifference(other Set[T]) Set[T]
+	// IsSubset returns true if every element of this set is in other.
+	IsSubset(other Set[T]) bool
+}
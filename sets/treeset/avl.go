@@ -0,0 +1,127 @@
+package treeset
+
+// node is a node of the AVL tree backing the set.
+type node[T any] struct {
+	value       T
+	left, right *node[T]
+	height      int
+}
+
+func height[T any](n *node[T]) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *node[T]) update() {
+	l, r := height(n.left), height(n.right)
+	if l > r {
+		n.height = l + 1
+	} else {
+		n.height = r + 1
+	}
+}
+
+func (n *node[T]) balanceFactor() int {
+	return height(n.left) - height(n.right)
+}
+
+/*
+	  n              l
+	 / \            / \
+	l   c   ==>    a   n
+   / \                / \
+  a   b              b   c
+*/
+
+// rotateRight lifts the left child of n and returns it as the new subtree root.
+func rotateRight[T any](n *node[T]) *node[T] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
+
+// rotateLeft lifts the right child of n and returns it as the new subtree root.
+func rotateLeft[T any](n *node[T]) *node[T] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+// rebalance restores the AVL property at n after one of its subtrees changed height by one.
+func rebalance[T any](n *node[T]) *node[T] {
+	n.update()
+	switch bf := n.balanceFactor(); {
+	case bf > 1:
+		if n.left.balanceFactor() < 0 {
+			n.left = rotateLeft(n.left)
+		}
+		return rotateRight(n)
+	case bf < -1:
+		if n.right.balanceFactor() > 0 {
+			n.right = rotateRight(n.right)
+		}
+		return rotateLeft(n)
+	}
+	return n
+}
+
+// insert adds value to the subtree rooted at n. It reports whether the value was new.
+func (s *Set[T]) insert(n *node[T], value T) (*node[T], bool) {
+	if n == nil {
+		return &node[T]{value: value, height: 1}, true
+	}
+	var added bool
+	switch c := s.cmp(value, n.value); {
+	case c < 0:
+		n.left, added = s.insert(n.left, value)
+	case c > 0:
+		n.right, added = s.insert(n.right, value)
+	default:
+		return n, false
+	}
+	if !added {
+		return n, false
+	}
+	return rebalance(n), true
+}
+
+// delete removes value from the subtree rooted at n. It reports whether the value was present.
+func (s *Set[T]) delete(n *node[T], value T) (*node[T], bool) {
+	if n == nil {
+		return nil, false
+	}
+	var removed bool
+	switch c := s.cmp(value, n.value); {
+	case c < 0:
+		n.left, removed = s.delete(n.left, value)
+	case c > 0:
+		n.right, removed = s.delete(n.right, value)
+	default:
+		if n.left == nil {
+			return n.right, true
+		}
+		if n.right == nil {
+			return n.left, true
+		}
+		// Replace the value with its in-order successor and delete the successor instead.
+		successor := n.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		n.value = successor.value
+		n.right, _ = s.delete(n.right, successor.value)
+		removed = true
+	}
+	if !removed {
+		return n, false
+	}
+	return rebalance(n), true
+}
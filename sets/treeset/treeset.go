@@ -0,0 +1,245 @@
+// Package treeset implements an ordered set backed by an AVL tree.
+//
+// Elements are kept sorted by the comparator given at construction time, so Values returns them
+// in ascending order and the set supports ordered queries such as Floor and Ceiling.
+// Add, Remove and Contains run in O(log n).
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/AVL_tree
+package treeset
+
+import (
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/sets"
+)
+
+var _ sets.Set[int] = (*Set[int])(nil)
+
+// Set is an ordered set.
+type Set[T any] struct {
+	root *node[T]
+	size int
+	cmp  common.Comparator[T, T] // cmp should return a negative number if a < b, zero if a == b, and a positive number if a > b.
+}
+
+// New returns a new tree set ordered by cmp and holding the given items.
+func New[T any](cmp common.Comparator[T, T], items ...T) *Set[T] {
+	s := &Set[T]{cmp: cmp}
+	s.Add(items...)
+	return s
+}
+
+// Add adds the items to the set.
+func (s *Set[T]) Add(items ...T) {
+	for _, item := range items {
+		var added bool
+		if s.root, added = s.insert(s.root, item); added {
+			s.size++
+		}
+	}
+}
+
+// Remove removes the items from the set.
+func (s *Set[T]) Remove(items ...T) {
+	for _, item := range items {
+		var removed bool
+		if s.root, removed = s.delete(s.root, item); removed {
+			s.size--
+		}
+	}
+}
+
+// Contains returns true if all items are present in the set.
+func (s *Set[T]) Contains(items ...T) bool {
+	for _, item := range items {
+		if s.lookup(item) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// lookup returns the node holding value, or nil.
+func (s *Set[T]) lookup(value T) *node[T] {
+	n := s.root
+	for n != nil {
+		switch c := s.cmp(value, n.value); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// Values returns all elements of the set in ascending order.
+func (s *Set[T]) Values() []T {
+	values := make([]T, 0, s.size)
+	s.Each(func(value T) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// Each calls fn for every element in ascending order until fn returns false.
+func (s *Set[T]) Each(fn func(value T) bool) {
+	// Iterative in-order traversal with an explicit stack; its depth is bounded by the tree height.
+	var stack []*node[T]
+	n := s.root
+	for n != nil || len(stack) > 0 {
+		for n != nil {
+			stack = append(stack, n)
+			n = n.left
+		}
+		n = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !fn(n.value) {
+			return
+		}
+		n = n.right
+	}
+}
+
+// First returns the smallest element of the set.
+func (s *Set[T]) First() (T, bool) {
+	if s.root == nil {
+		var zero T
+		return zero, false
+	}
+	n := s.root
+	for n.left != nil {
+		n = n.left
+	}
+	return n.value, true
+}
+
+// Last returns the largest element of the set.
+func (s *Set[T]) Last() (T, bool) {
+	if s.root == nil {
+		var zero T
+		return zero, false
+	}
+	n := s.root
+	for n.right != nil {
+		n = n.right
+	}
+	return n.value, true
+}
+
+// Floor returns the largest element less than or equal to value.
+func (s *Set[T]) Floor(value T) (T, bool) {
+	var floor *node[T]
+	for n := s.root; n != nil; {
+		switch c := s.cmp(value, n.value); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			floor = n
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+	if floor == nil {
+		var zero T
+		return zero, false
+	}
+	return floor.value, true
+}
+
+// Ceiling returns the smallest element greater than or equal to value.
+func (s *Set[T]) Ceiling(value T) (T, bool) {
+	var ceiling *node[T]
+	for n := s.root; n != nil; {
+		switch c := s.cmp(value, n.value); {
+		case c < 0:
+			ceiling = n
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+	if ceiling == nil {
+		var zero T
+		return zero, false
+	}
+	return ceiling.value, true
+}
+
+// Union returns a set with the elements that are in either set.
+func (s *Set[T]) Union(other sets.Set[T]) sets.Set[T] {
+	result := New[T](s.cmp, s.Values()...)
+	result.Add(other.Values()...)
+	return result
+}
+
+// Intersection returns a set with the elements that are in both sets.
+func (s *Set[T]) Intersection(other sets.Set[T]) sets.Set[T] {
+	result := New[T](s.cmp)
+	s.Each(func(value T) bool {
+		if other.Contains(value) {
+			result.Add(value)
+		}
+		return true
+	})
+	return result
+}
+
+// Difference returns a set with the elements of s that are not in other.
+func (s *Set[T]) Difference(other sets.Set[T]) sets.Set[T] {
+	result := New[T](s.cmp)
+	s.Each(func(value T) bool {
+		if !other.Contains(value) {
+			result.Add(value)
+		}
+		return true
+	})
+	return result
+}
+
+// SymmetricDifference returns a set with the elements that are in exactly one of the sets.
+func (s *Set[T]) SymmetricDifference(other sets.Set[T]) sets.Set[T] {
+	result := s.Difference(other)
+	for _, value := range other.Values() {
+		if !s.Contains(value) {
+			result.Add(value)
+		}
+	}
+	return result
+}
+
+// IsSubset returns true if every element of s is in other.
+func (s *Set[T]) IsSubset(other sets.Set[T]) bool {
+	if s.Size() > other.Size() {
+		return false
+	}
+	subset := true
+	s.Each(func(value T) bool {
+		subset = other.Contains(value)
+		return subset
+	})
+	return subset
+}
+
+// Empty returns true if the set is empty.
+func (s *Set[T]) Empty() bool {
+	return s.size == 0
+}
+
+// Size returns the number of elements in the set.
+func (s *Set[T]) Size() int {
+	return s.size
+}
+
+// Clear removes all elements from the set.
+func (s *Set[T]) Clear() {
+	s.root = nil
+	s.size = 0
+}
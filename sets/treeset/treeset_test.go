@@ -0,0 +1,112 @@
+package treeset
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/sets/hashset"
+	"github.com/stretchr/testify/assert"
+)
+
+// checkAVL verifies ordering, heights and balance of the subtree and returns its height.
+func checkAVL(t *testing.T, n *node[int], lo, hi int) int {
+	if n == nil {
+		return 0
+	}
+	assert.True(t, lo < n.value && n.value < hi, "value %d out of order", n.value)
+	l := checkAVL(t, n.left, lo, n.value)
+	r := checkAVL(t, n.right, n.value, hi)
+	assert.LessOrEqual(t, l-r, 1)
+	assert.GreaterOrEqual(t, l-r, -1)
+	h := l + 1
+	if r >= l {
+		h = r + 1
+	}
+	assert.Equal(t, h, n.height)
+	return h
+}
+
+func TestSet_RandomOperations(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := New[int](common.IntComparator)
+	want := make(map[int]struct{})
+
+	for i := 0; i < 5000; i++ {
+		v := r.Intn(300)
+		if r.Intn(3) == 0 {
+			s.Remove(v)
+			delete(want, v)
+		} else {
+			s.Add(v)
+			want[v] = struct{}{}
+		}
+	}
+
+	checkAVL(t, s.root, -1, 300)
+	wantValues := make([]int, 0, len(want))
+	for v := range want {
+		wantValues = append(wantValues, v)
+	}
+	sort.Ints(wantValues)
+	assert.Equal(t, wantValues, s.Values())
+	assert.Equal(t, len(want), s.Size())
+}
+
+func TestSet_OrderedQueries(t *testing.T) {
+	s := New(common.IntComparator, 10, 20, 30)
+
+	tests := []struct {
+		name           string
+		value          int
+		floor, ceiling int
+		hasFloor       bool
+		hasCeiling     bool
+	}{
+		{name: "exact", value: 20, floor: 20, ceiling: 20, hasFloor: true, hasCeiling: true},
+		{name: "between", value: 25, floor: 20, ceiling: 30, hasFloor: true, hasCeiling: true},
+		{name: "below all", value: 5, ceiling: 10, hasCeiling: true},
+		{name: "above all", value: 35, floor: 30, hasFloor: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			floor, ok := s.Floor(tt.value)
+			assert.Equal(t, tt.hasFloor, ok)
+			assert.Equal(t, tt.floor, floor)
+			ceiling, ok := s.Ceiling(tt.value)
+			assert.Equal(t, tt.hasCeiling, ok)
+			assert.Equal(t, tt.ceiling, ceiling)
+		})
+	}
+
+	first, ok := s.First()
+	assert.True(t, ok)
+	assert.Equal(t, 10, first)
+	last, ok := s.Last()
+	assert.True(t, ok)
+	assert.Equal(t, 30, last)
+
+	s.Clear()
+	_, ok = s.First()
+	assert.False(t, ok)
+	_, ok = s.Last()
+	assert.False(t, ok)
+}
+
+func TestSet_Operations(t *testing.T) {
+	a := New(common.IntComparator, 4, 1, 3, 2)
+	b := New(common.IntComparator, 5, 3, 4)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, a.Union(b).Values())
+	assert.Equal(t, []int{3, 4}, a.Intersection(b).Values())
+	assert.Equal(t, []int{1, 2}, a.Difference(b).Values())
+	assert.Equal(t, []int{1, 2, 5}, a.SymmetricDifference(b).Values())
+	assert.False(t, a.IsSubset(b))
+	assert.True(t, New(common.IntComparator, 3, 4).IsSubset(a))
+	assert.True(t, a.Contains(1, 4))
+
+	// Operands may be of a different implementation.
+	assert.Equal(t, []int{1, 2, 3, 4, 9}, a.Union(hashset.New(9, 1)).Values())
+}
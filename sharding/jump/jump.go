@@ -0,0 +1,32 @@
+// Package jump implements jump consistent hashing, which maps a key to one of n numbered buckets
+// using no memory and O(log n) time.
+//
+// When the number of buckets grows from n to n+1, exactly the keys that move go to the new bucket n,
+// about 1/(n+1) of them. Buckets can only be added or removed at the end, so the scheme fits
+// numbered shards or replicas rather than nodes that come and go arbitrarily.
+//
+// References: https://arxiv.org/abs/1406.2294
+package jump
+
+import "github.com/kwstars/goads/pkg/common"
+
+// Hash returns the bucket in [0, buckets) of a 64-bit key. The key should already be well mixed,
+// for example the output of a common.Hasher. If buckets is not positive, Hash returns -1.
+func Hash(key uint64, buckets int) int {
+	if buckets <= 0 {
+		return -1
+	}
+	b, j := int64(-1), int64(0)
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// Bucket hashes the key with hasher and returns its bucket in [0, buckets).
+// If buckets is not positive, Bucket returns -1.
+func Bucket[K any](hasher common.Hasher[K], key K, buckets int) int {
+	return Hash(hasher(key), buckets)
+}
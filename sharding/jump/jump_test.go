@@ -0,0 +1,44 @@
+package jump
+
+import (
+	"testing"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash(t *testing.T) {
+	assert.Equal(t, -1, Hash(1, 0))
+	assert.Equal(t, -1, Bucket(common.IntHasher, 1, -3))
+	for key := uint64(0); key < 100; key++ {
+		assert.Equal(t, 0, Hash(key, 1))
+	}
+
+	const keys = 100000
+	counts := make([]int, 10)
+	for k := 0; k < keys; k++ {
+		b := Bucket(common.IntHasher, k, 10)
+		assert.True(t, b >= 0 && b < 10)
+		counts[b]++
+	}
+	for _, count := range counts {
+		assert.InDelta(t, keys/10, count, 0.05*keys/10)
+	}
+}
+
+func TestMinimalMovement(t *testing.T) {
+	const keys = 20000
+	for n := 1; n < 50; n++ {
+		moved := 0
+		for k := 0; k < keys; k++ {
+			before, after := Bucket(common.IntHasher, k, n), Bucket(common.IntHasher, k, n+1)
+			if before != after {
+				// A key only ever moves to the new bucket.
+				assert.Equal(t, n, after)
+				moved++
+			}
+		}
+		expected := float64(keys) / float64(n+1)
+		assert.InDelta(t, expected, moved, 0.15*expected+50, "n=%d", n)
+	}
+}
@@ -0,0 +1,145 @@
+// Package rendezvous implements rendezvous or highest random weight (HRW) hashing: a key belongs to
+// the node with the highest score hash(key, node).
+//
+// Adding a node only moves the keys the new node wins, and removing a node only moves its own keys,
+// which is the least movement possible. The ranking of all nodes also gives a natural order for
+// placing replicas. A lookup takes O(n) for n nodes, which is fast for the few dozen nodes of a
+// typical cluster; for many nodes a consistent-hash ring is cheaper.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Rendezvous_hashing
+package rendezvous
+
+import (
+	"errors"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/sharding"
+	"github.com/kwstars/goads/sorting"
+)
+
+var _ sharding.Sharder[int, string] = (*Table[int, string])(nil)
+
+var ErrNoNodes = errors.New("table has no nodes")
+
+// Table maps keys to nodes by highest random weight.
+type Table[K any, N comparable] struct {
+	nodes      []N
+	hashes     []uint64 // hashes[i] is the hash of nodes[i]
+	index      map[N]int
+	keyHasher  common.Hasher[K]
+	nodeHasher common.Hasher[N]
+}
+
+// New returns an empty table that hashes keys with keyHasher and nodes with nodeHasher.
+func New[K any, N comparable](keyHasher common.Hasher[K], nodeHasher common.Hasher[N]) *Table[K, N] {
+	return &Table[K, N]{index: make(map[N]int), keyHasher: keyHasher, nodeHasher: nodeHasher}
+}
+
+// score combines the hashes of a key and a node into the weight of the node for the key.
+func score(key, node uint64) uint64 {
+	return common.Mix64(key ^ node)
+}
+
+// Add adds the nodes. Nodes that are already present are ignored.
+func (t *Table[K, N]) Add(nodes ...N) {
+	for _, node := range nodes {
+		if _, ok := t.index[node]; ok {
+			continue
+		}
+		t.index[node] = len(t.nodes)
+		t.nodes = append(t.nodes, node)
+		t.hashes = append(t.hashes, t.nodeHasher(node))
+	}
+}
+
+// Remove removes the node. It returns false if the node was not present.
+func (t *Table[K, N]) Remove(node N) bool {
+	i, ok := t.index[node]
+	if !ok {
+		return false
+	}
+	last := len(t.nodes) - 1
+	t.nodes[i], t.hashes[i] = t.nodes[last], t.hashes[last]
+	t.index[t.nodes[i]] = i
+	t.nodes, t.hashes = t.nodes[:last], t.hashes[:last]
+	delete(t.index, node)
+	return true
+}
+
+// Contains returns true if the node is in the table.
+func (t *Table[K, N]) Contains(node N) bool {
+	_, ok := t.index[node]
+	return ok
+}
+
+// Get returns the node with the highest score for the key. It returns ErrNoNodes if the table is empty.
+func (t *Table[K, N]) Get(key K) (N, error) {
+	if len(t.nodes) == 0 {
+		var zero N
+		return zero, ErrNoNodes
+	}
+	h := t.keyHasher(key)
+	best := 0
+	for i := 1; i < len(t.hashes); i++ {
+		if score(h, t.hashes[i]) > score(h, t.hashes[best]) {
+			best = i
+		}
+	}
+	return t.nodes[best], nil
+}
+
+// GetN returns the n nodes with the highest scores for the key, highest first.
+func (t *Table[K, N]) GetN(key K, n int) []N {
+	if len(t.nodes) == 0 || n <= 0 {
+		return nil
+	}
+	type scored struct {
+		node  N
+		score uint64
+	}
+	h := t.keyHasher(key)
+	ranked := make([]scored, len(t.nodes))
+	for i, node := range t.nodes {
+		ranked[i] = scored{node: node, score: score(h, t.hashes[i])}
+	}
+	sorting.PDQSort(ranked, func(a, b scored) int8 {
+		if a.score > b.score {
+			return -1
+		} else if a.score < b.score {
+			return 1
+		}
+		return 0
+	})
+
+	if n > len(ranked) {
+		n = len(ranked)
+	}
+	nodes := make([]N, n)
+	for i := range nodes {
+		nodes[i] = ranked[i].node
+	}
+	return nodes
+}
+
+// Nodes returns all nodes.
+func (t *Table[K, N]) Nodes() []N {
+	return append([]N(nil), t.nodes...)
+}
+
+// Empty returns true if the table has no nodes.
+func (t *Table[K, N]) Empty() bool {
+	return len(t.nodes) == 0
+}
+
+// Size returns the number of nodes.
+func (t *Table[K, N]) Size() int {
+	return len(t.nodes)
+}
+
+// Clear removes all nodes.
+func (t *Table[K, N]) Clear() {
+	t.nodes, t.hashes = nil, nil
+	t.index = make(map[N]int)
+}
@@ -0,0 +1,100 @@
+package rendezvous
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const keys = 100000
+
+func nodeNames(n int) []string {
+	nodes := make([]string, n)
+	for i := range nodes {
+		nodes[i] = fmt.Sprintf("node-%d", i)
+	}
+	return nodes
+}
+
+func assignment(table *Table[int, string]) []string {
+	nodes := make([]string, keys)
+	for k := range nodes {
+		nodes[k], _ = table.Get(k)
+	}
+	return nodes
+}
+
+func TestGet(t *testing.T) {
+	table := New(common.IntHasher, common.StringHasher)
+	_, err := table.Get(1)
+	assert.True(t, errors.Is(err, ErrNoNodes))
+	assert.Nil(t, table.GetN(1, 2))
+
+	table.Add(nodeNames(8)...)
+	table.Add("node-0")
+	assert.Equal(t, 8, table.Size())
+	assert.ElementsMatch(t, nodeNames(8), table.Nodes())
+
+	counts := make(map[string]int)
+	for k := 0; k < keys; k++ {
+		node, err := table.Get(k)
+		assert.NoError(t, err)
+		counts[node]++
+
+		if k%100 == 0 {
+			ranked := table.GetN(k, 8)
+			assert.ElementsMatch(t, nodeNames(8), ranked)
+			assert.Equal(t, node, ranked[0])
+			for i := 1; i < len(ranked); i++ {
+				prev := score(common.IntHasher(k), common.StringHasher(ranked[i-1]))
+				assert.Greater(t, prev, score(common.IntHasher(k), common.StringHasher(ranked[i])))
+			}
+			assert.Equal(t, ranked[:3], table.GetN(k, 3))
+		}
+	}
+	for node, count := range counts {
+		assert.InDelta(t, keys/8, count, 0.05*keys/8, node)
+	}
+
+	table.Clear()
+	assert.True(t, table.Empty())
+}
+
+func TestMinimalMovement(t *testing.T) {
+	table := New(common.IntHasher, common.StringHasher)
+	table.Add(nodeNames(10)...)
+	before := assignment(table)
+
+	// Adding a node only moves keys to the new node.
+	table.Add("node-10")
+	after := assignment(table)
+	moved := 0
+	for k := range before {
+		if before[k] != after[k] {
+			assert.Equal(t, "node-10", after[k])
+			moved++
+		}
+	}
+	assert.InDelta(t, keys/11, moved, 0.1*keys/11)
+
+	// Removing a node only moves its own keys; the swap with the last node keeps the others in place.
+	assert.True(t, table.Remove("node-2"))
+	assert.False(t, table.Remove("node-2"))
+	assert.False(t, table.Contains("node-2"))
+	assert.True(t, table.Contains("node-10"))
+	removed := assignment(table)
+	for k := range after {
+		if after[k] != "node-2" {
+			assert.Equal(t, after[k], removed[k])
+		} else {
+			assert.NotEqual(t, "node-2", removed[k])
+		}
+	}
+
+	table.Remove("node-10")
+	table.Add("node-2")
+	assert.Equal(t, before, assignment(table))
+}
@@ -0,0 +1,134 @@
+package ring
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/sharding"
+)
+
+var _ sharding.Sharder[int, string] = (*Bounded[int, string])(nil)
+
+var ErrInvalidFactor = errors.New("load factor must be at least 1")
+
+// Bounded is a consistent-hash ring that caps the number of keys assigned to each node at
+// ⌈c·(m+1)/n⌉ for m assigned keys, n nodes and load factor c. A key goes to the first node
+// clockwise from its position that is below the cap, so no node gets more than c times the
+// average load, at the cost of moving somewhat more keys when nodes or loads change.
+//
+// Keys are assigned with Assign and released with Release; Get only reports where a new key would go.
+type Bounded[K any, N comparable] struct {
+	ring   *Ring[K, N]
+	factor float64
+	loads  map[N]int
+	total  int
+}
+
+// NewBounded returns an empty bounded-load ring with load factor c, which must be at least 1.
+// Smaller factors balance better and move more keys; 1.25 is a common choice.
+func NewBounded[K any, N comparable](keyHasher common.Hasher[K], nodeHasher common.Hasher[N], c float64, opts ...Option[K, N]) (*Bounded[K, N], error) {
+	if !(c >= 1) || math.IsInf(c, 1) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFactor, c)
+	}
+	return &Bounded[K, N]{ring: New(keyHasher, nodeHasher, opts...), factor: c, loads: make(map[N]int)}, nil
+}
+
+// capacity returns the maximum load of a node after one more key is assigned.
+func (b *Bounded[K, N]) capacity() int {
+	return int(math.Ceil(b.factor * float64(b.total+1) / float64(len(b.ring.nodes))))
+}
+
+// Add adds the nodes. Nodes that are already present are ignored.
+func (b *Bounded[K, N]) Add(nodes ...N) {
+	b.ring.Add(nodes...)
+}
+
+// Remove removes the node and forgets its load; the keys assigned to it must be assigned again.
+// It returns false if the node was not present.
+func (b *Bounded[K, N]) Remove(node N) bool {
+	if !b.ring.Remove(node) {
+		return false
+	}
+	b.total -= b.loads[node]
+	delete(b.loads, node)
+	return true
+}
+
+// Get returns the node Assign would choose for the key, without assigning it.
+// It returns ErrNoNodes if the ring is empty.
+func (b *Bounded[K, N]) Get(key K) (N, error) {
+	var found N
+	if b.ring.Empty() {
+		return found, ErrNoNodes
+	}
+	capacity := b.capacity()
+	b.ring.walk(key, func(node N) bool {
+		found = node
+		return b.loads[node] >= capacity
+	})
+	return found, nil
+}
+
+// GetN returns up to n distinct nodes found walking clockwise from the key, ignoring loads.
+func (b *Bounded[K, N]) GetN(key K, n int) []N {
+	return b.ring.GetN(key, n)
+}
+
+// Assign assigns the key to the first node clockwise from it that is below the load cap and returns the node.
+// It returns ErrNoNodes if the ring is empty.
+func (b *Bounded[K, N]) Assign(key K) (N, error) {
+	node, err := b.Get(key)
+	if err != nil {
+		return node, err
+	}
+	b.loads[node]++
+	b.total++
+	return node, nil
+}
+
+// Release releases one key assigned to the node. It returns false if the node has no assigned keys.
+func (b *Bounded[K, N]) Release(node N) bool {
+	if b.loads[node] == 0 {
+		return false
+	}
+	b.loads[node]--
+	if b.loads[node] == 0 {
+		delete(b.loads, node)
+	}
+	b.total--
+	return true
+}
+
+// Load returns the number of keys assigned to the node.
+func (b *Bounded[K, N]) Load(node N) int {
+	return b.loads[node]
+}
+
+// TotalLoad returns the number of keys assigned to all nodes.
+func (b *Bounded[K, N]) TotalLoad() int {
+	return b.total
+}
+
+// Nodes returns all nodes in no particular order.
+func (b *Bounded[K, N]) Nodes() []N {
+	return b.ring.Nodes()
+}
+
+// Empty returns true if the ring has no nodes.
+func (b *Bounded[K, N]) Empty() bool {
+	return b.ring.Empty()
+}
+
+// Size returns the number of nodes.
+func (b *Bounded[K, N]) Size() int {
+	return b.ring.Size()
+}
+
+// Clear removes all nodes and loads.
+func (b *Bounded[K, N]) Clear() {
+	b.ring.Clear()
+	b.loads = make(map[N]int)
+	b.total = 0
+}
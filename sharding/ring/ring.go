@@ -0,0 +1,208 @@
+// Package ring implements consistent hashing: nodes and keys are hashed onto a circle of 64-bit
+// values and a key belongs to the first node at or after its position, wrapping around.
+//
+// Each node is placed at several points (virtual nodes), which evens out the share of the circle
+// each node owns. When a node joins it only takes keys from its neighbours, and when it leaves only
+// its keys move, so about 1/n of the keys move for n nodes. Bounded adds a cap on the number of
+// keys per node (consistent hashing with bounded loads).
+//
+// Structures are not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Consistent_hashing,
+// https://arxiv.org/abs/1608.01350 (bounded loads)
+package ring
+
+import (
+	"errors"
+
+	"github.com/kwstars/goads/binarysearch"
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/sharding"
+	"github.com/kwstars/goads/sorting"
+)
+
+var _ sharding.Sharder[int, string] = (*Ring[int, string])(nil)
+
+const defaultReplicas = 100
+
+var ErrNoNodes = errors.New("ring has no nodes")
+
+// point is a virtual node: a position on the circle owned by a node.
+type point[N any] struct {
+	hash uint64
+	node N
+}
+
+func byHash[N any](a, b point[N]) int8 {
+	if a.hash < b.hash {
+		return -1
+	} else if a.hash > b.hash {
+		return 1
+	}
+	return 0
+}
+
+func compareHash[N any](p point[N], hash uint64) int8 {
+	if p.hash < hash {
+		return -1
+	} else if p.hash > hash {
+		return 1
+	}
+	return 0
+}
+
+// Option is a function that can be passed to New to customize the Ring.
+type Option[K any, N comparable] func(*Ring[K, N])
+
+// WithReplicas sets the number of virtual nodes per node. Values below 1 are ignored.
+func WithReplicas[K any, N comparable](replicas int) Option[K, N] {
+	return func(r *Ring[K, N]) {
+		if replicas > 0 {
+			r.replicas = replicas
+		}
+	}
+}
+
+// Ring is a consistent-hash ring with virtual nodes.
+type Ring[K any, N comparable] struct {
+	points     []point[N] // sorted by hash
+	nodes      map[N]struct{}
+	replicas   int
+	keyHasher  common.Hasher[K]
+	nodeHasher common.Hasher[N]
+}
+
+// New returns an empty ring that hashes keys with keyHasher and nodes with nodeHasher.
+func New[K any, N comparable](keyHasher common.Hasher[K], nodeHasher common.Hasher[N], opts ...Option[K, N]) *Ring[K, N] {
+	r := &Ring[K, N]{
+		nodes:      make(map[N]struct{}),
+		replicas:   defaultReplicas,
+		keyHasher:  keyHasher,
+		nodeHasher: nodeHasher,
+	}
+	for _, option := range opts {
+		option(r)
+	}
+	return r
+}
+
+// Add adds the nodes. Nodes that are already present are ignored.
+func (r *Ring[K, N]) Add(nodes ...N) {
+	added := false
+	for _, node := range nodes {
+		if _, ok := r.nodes[node]; ok {
+			continue
+		}
+		r.nodes[node] = struct{}{}
+		h := r.nodeHasher(node)
+		for i := 0; i < r.replicas; i++ {
+			r.points = append(r.points, point[N]{hash: common.Mix64(h + uint64(i)), node: node})
+		}
+		added = true
+	}
+	if added {
+		sorting.TimSort(r.points, byHash[N])
+	}
+}
+
+// Remove removes the node. It returns false if the node was not present.
+func (r *Ring[K, N]) Remove(node N) bool {
+	if _, ok := r.nodes[node]; !ok {
+		return false
+	}
+	delete(r.nodes, node)
+	points := r.points[:0]
+	for _, p := range r.points {
+		if p.node != node {
+			points = append(points, p)
+		}
+	}
+	r.points = points
+	return true
+}
+
+// Contains returns true if the node is in the ring.
+func (r *Ring[K, N]) Contains(node N) bool {
+	_, ok := r.nodes[node]
+	return ok
+}
+
+// search returns the index of the first point at or after the position of the key, wrapping around.
+func (r *Ring[K, N]) search(key K) int {
+	i := binarysearch.FindFirstGreaterOrEqual(r.points, r.keyHasher(key), compareHash[N])
+	if i < 0 {
+		return 0
+	}
+	return i
+}
+
+// Get returns the node the key belongs to. It returns ErrNoNodes if the ring is empty.
+func (r *Ring[K, N]) Get(key K) (N, error) {
+	if len(r.points) == 0 {
+		var zero N
+		return zero, ErrNoNodes
+	}
+	return r.points[r.search(key)].node, nil
+}
+
+// GetN returns up to n distinct nodes found walking clockwise from the key.
+func (r *Ring[K, N]) GetN(key K, n int) []N {
+	if len(r.points) == 0 || n <= 0 {
+		return nil
+	}
+	if n > len(r.nodes) {
+		n = len(r.nodes)
+	}
+	nodes := make([]N, 0, n)
+	r.walk(key, func(node N) bool {
+		for _, seen := range nodes {
+			if seen == node {
+				return true
+			}
+		}
+		nodes = append(nodes, node)
+		return len(nodes) < n
+	})
+	return nodes
+}
+
+// walk calls fn with the node of each point clockwise from the key, once around the circle.
+// If fn returns false, walk stops.
+func (r *Ring[K, N]) walk(key K, fn func(node N) bool) {
+	start := r.search(key)
+	for i := 0; i < len(r.points); i++ {
+		if !fn(r.points[(start+i)%len(r.points)].node) {
+			return
+		}
+	}
+}
+
+// Nodes returns all nodes in no particular order.
+func (r *Ring[K, N]) Nodes() []N {
+	nodes := make([]N, 0, len(r.nodes))
+	for node := range r.nodes {
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// Replicas returns the number of virtual nodes per node.
+func (r *Ring[K, N]) Replicas() int {
+	return r.replicas
+}
+
+// Empty returns true if the ring has no nodes.
+func (r *Ring[K, N]) Empty() bool {
+	return len(r.nodes) == 0
+}
+
+// Size returns the number of nodes.
+func (r *Ring[K, N]) Size() int {
+	return len(r.nodes)
+}
+
+// Clear removes all nodes.
+func (r *Ring[K, N]) Clear() {
+	r.points = nil
+	r.nodes = make(map[N]struct{})
+}
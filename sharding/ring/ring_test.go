@@ -0,0 +1,186 @@
+package ring
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const keys = 100000
+
+func nodeNames(n int) []string {
+	nodes := make([]string, n)
+	for i := range nodes {
+		nodes[i] = fmt.Sprintf("node-%d", i)
+	}
+	return nodes
+}
+
+// assignment returns the node of every key.
+func assignment(r *Ring[int, string]) []string {
+	nodes := make([]string, keys)
+	for k := range nodes {
+		nodes[k], _ = r.Get(k)
+	}
+	return nodes
+}
+
+func TestGet(t *testing.T) {
+	r := New(common.IntHasher, common.StringHasher, WithReplicas[int, string](50))
+	_, err := r.Get(1)
+	assert.True(t, errors.Is(err, ErrNoNodes))
+	assert.Nil(t, r.GetN(1, 3))
+
+	r.Add(nodeNames(10)...)
+	r.Add("node-3")
+	assert.Equal(t, 10, r.Size())
+	assert.Len(t, r.points, 500)
+	assert.Equal(t, 50, r.Replicas())
+
+	for k := 0; k < 1000; k++ {
+		// Brute force: the first point at or after the key, wrapping around to the first point.
+		h := common.IntHasher(k)
+		want := r.points[0].node
+		for _, p := range r.points {
+			if p.hash >= h {
+				want = p.node
+				break
+			}
+		}
+		got, err := r.Get(k)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+
+		replicas := r.GetN(k, 3)
+		assert.Len(t, replicas, 3)
+		assert.Equal(t, got, replicas[0])
+		assert.NotEqual(t, replicas[1], replicas[0])
+		assert.NotEqual(t, replicas[2], replicas[1])
+		assert.NotEqual(t, replicas[2], replicas[0])
+	}
+	assert.ElementsMatch(t, nodeNames(10), r.GetN(1, 20))
+
+	r.Clear()
+	assert.True(t, r.Empty())
+	_, err = r.Get(1)
+	assert.True(t, errors.Is(err, ErrNoNodes))
+}
+
+func TestBalance(t *testing.T) {
+	r := New(common.IntHasher, common.StringHasher)
+	r.Add(nodeNames(10)...)
+	counts := make(map[string]int)
+	for _, node := range assignment(r) {
+		counts[node]++
+	}
+	for node, count := range counts {
+		assert.InDelta(t, keys/10, count, 0.35*keys/10, node)
+	}
+}
+
+func TestMinimalMovement(t *testing.T) {
+	r := New(common.IntHasher, common.StringHasher)
+	r.Add(nodeNames(10)...)
+	before := assignment(r)
+
+	// Adding a node only moves keys to the new node.
+	r.Add("node-10")
+	after := assignment(r)
+	moved := 0
+	for k := range before {
+		if before[k] != after[k] {
+			assert.Equal(t, "node-10", after[k])
+			moved++
+		}
+	}
+	assert.InDelta(t, keys/11, moved, 0.4*keys/11)
+
+	// Removing a node only moves its own keys.
+	assert.True(t, r.Remove("node-4"))
+	assert.False(t, r.Remove("node-4"))
+	assert.False(t, r.Contains("node-4"))
+	removed := assignment(r)
+	for k := range after {
+		if after[k] != "node-4" {
+			assert.Equal(t, after[k], removed[k])
+		} else {
+			assert.NotEqual(t, "node-4", removed[k])
+		}
+	}
+
+	// Removing the added node restores the original assignment of the other keys.
+	r.Remove("node-10")
+	r.Add("node-4")
+	assert.Equal(t, before, assignment(r))
+}
+
+func TestBounded(t *testing.T) {
+	_, err := NewBounded(common.IntHasher, common.StringHasher, 0.9)
+	assert.True(t, errors.Is(err, ErrInvalidFactor))
+
+	b, err := NewBounded(common.IntHasher, common.StringHasher, 1.25, WithReplicas[int, string](20))
+	assert.NoError(t, err)
+	_, err = b.Assign(1)
+	assert.True(t, errors.Is(err, ErrNoNodes))
+
+	b.Add(nodeNames(10)...)
+	assigned := make([]string, 10000)
+	for k := range assigned {
+		assigned[k], err = b.Assign(k)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 10000, b.TotalLoad())
+	limit := int(math.Ceil(1.25 * 10000 / 10))
+	for _, node := range b.Nodes() {
+		assert.LessOrEqual(t, b.Load(node), limit)
+		assert.Greater(t, b.Load(node), 0)
+	}
+
+	// Without pressure a key goes to its ring successor.
+	fresh, _ := NewBounded(common.IntHasher, common.StringHasher, 1.25, WithReplicas[int, string](20))
+	fresh.Add(nodeNames(10)...)
+	sameAsRing := 0
+	for k := range assigned {
+		want, _ := fresh.ring.Get(k)
+		if assigned[k] == want {
+			sameAsRing++
+		}
+	}
+	assert.Greater(t, sameAsRing, 8000)
+
+	// Releasing all keys of a node brings its load back to zero.
+	for k, node := range assigned {
+		if node == "node-0" {
+			assert.True(t, b.Release(node))
+			assigned[k] = ""
+		}
+	}
+	assert.Equal(t, 0, b.Load("node-0"))
+	assert.False(t, b.Release("node-0"))
+
+	// Removing a node drops its load; its keys are assigned again.
+	total, load := b.TotalLoad(), b.Load("node-1")
+	assert.True(t, b.Remove("node-1"))
+	assert.Equal(t, 0, b.Load("node-1"))
+	assert.Equal(t, total-load, b.TotalLoad())
+	assert.Equal(t, 9, b.Size())
+	for k, node := range assigned {
+		if node == "node-1" {
+			assigned[k], _ = b.Assign(k)
+			assert.NotEqual(t, "node-1", assigned[k])
+		}
+	}
+	assert.Equal(t, total, b.TotalLoad())
+	for _, node := range b.Nodes() {
+		assert.LessOrEqual(t, b.Load(node), limit)
+	}
+	assert.Len(t, b.GetN(1, 2), 2)
+
+	b.Clear()
+	assert.True(t, b.Empty())
+	assert.Equal(t, 0, b.TotalLoad())
+}
@@ -0,0 +1,25 @@
+// Package sharding defines the interface of schemes that spread keys over a changing set of nodes.
+//
+// A good scheme balances the keys over the nodes and moves as few keys as possible when a node
+// joins or leaves: ideally only the keys of the leaving node, or the share taken by the new node.
+// A plain hash(key) % n moves almost every key whenever n changes.
+package sharding
+
+import "github.com/kwstars/goads/containers"
+
+// Sharder maps keys of type K to nodes of type N.
+type Sharder[K any, N comparable] interface {
+	containers.Container[N]
+
+	// Add adds the nodes. Nodes that are already present are ignored.
+	Add(nodes ...N)
+	// Remove removes the node. It returns false if the node was not present.
+	Remove(node N) bool
+	// Get returns the node the key belongs to. It returns an error if there are no nodes.
+	Get(key K) (N, error)
+	// GetN returns up to n distinct nodes for the key in order of preference, for placing replicas.
+	// The first node is the one returned by Get.
+	GetN(key K, n int) []N
+	// Nodes returns all nodes.
+	Nodes() []N
+}
@@ -0,0 +1,143 @@
+// Package countmin implements a Count-Min sketch, which estimates how often each item occurs in a
+// stream in fixed memory, and a heavy-hitters tracker built on it.
+//
+// The sketch is a depth × width table of counters. An item increments one counter per row and its
+// estimate is the smallest of those counters: it never underestimates, and with probability 1-δ it
+// overestimates by at most ε·N, where width = ⌈e/ε⌉, depth = ⌈ln 1/δ⌉ and N is the total count.
+// With conservative update only the counters that are at the current minimum are raised, which
+// reduces the overestimate considerably; such a sketch no longer supports negative updates.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Count%E2%80%93min_sketch,
+// http://dimacs.rutgers.edu/~graham/pubs/papers/cm-full.pdf
+package countmin
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/kwstars/goads/pkg/common"
+)
+
+var (
+	ErrInvalidParameter = errors.New("invalid sketch parameter")
+	ErrIncompatible     = errors.New("sketches have different dimensions")
+)
+
+// Option is a function that can be passed to New to customize the Sketch.
+type Option[T any] func(*Sketch[T])
+
+// WithConservativeUpdate makes Add raise only the counters that are at the item's current estimate.
+func WithConservativeUpdate[T any]() Option[T] {
+	return func(s *Sketch[T]) {
+		s.conservative = true
+	}
+}
+
+// Sketch is a Count-Min sketch.
+type Sketch[T any] struct {
+	counters     []uint64 // depth rows of width counters
+	width        int
+	depth        int
+	total        uint64
+	conservative bool
+	hasher       common.Hasher[T]
+}
+
+// New returns a sketch with depth rows of width counters.
+func New[T any](hasher common.Hasher[T], width, depth int, opts ...Option[T]) (*Sketch[T], error) {
+	if width <= 0 || depth <= 0 {
+		return nil, fmt.Errorf("%w: width %d, depth %d", ErrInvalidParameter, width, depth)
+	}
+	s := &Sketch[T]{counters: make([]uint64, width*depth), width: width, depth: depth, hasher: hasher}
+	for _, option := range opts {
+		option(s)
+	}
+	return s, nil
+}
+
+// NewWithEstimates returns a sketch whose estimates exceed the true count by at most epsilon times
+// the total count with probability 1-delta.
+func NewWithEstimates[T any](hasher common.Hasher[T], epsilon, delta float64, opts ...Option[T]) (*Sketch[T], error) {
+	if !(epsilon > 0 && epsilon < 1) || !(delta > 0 && delta < 1) {
+		return nil, fmt.Errorf("%w: epsilon %v, delta %v", ErrInvalidParameter, epsilon, delta)
+	}
+	width := int(math.Ceil(math.E / epsilon))
+	depth := int(math.Ceil(math.Log(1 / delta)))
+	return New(hasher, width, depth, opts...)
+}
+
+// cells calls fn with the index of the item's counter in each row.
+// The row positions are derived from one hash by double hashing.
+func (s *Sketch[T]) cells(item T, fn func(i int)) {
+	h1 := s.hasher(item)
+	h2 := common.Mix64(h1) | 1
+	for row := 0; row < s.depth; row++ {
+		fn(row*s.width + int((h1+uint64(row)*h2)%uint64(s.width)))
+	}
+}
+
+// Add adds count occurrences of the item.
+func (s *Sketch[T]) Add(item T, count uint64) {
+	s.total += count
+	if !s.conservative {
+		s.cells(item, func(i int) { s.counters[i] += count })
+		return
+	}
+
+	target := s.Estimate(item) + count
+	s.cells(item, func(i int) {
+		if s.counters[i] < target {
+			s.counters[i] = target
+		}
+	})
+}
+
+// Estimate returns an upper bound of the number of occurrences of the item.
+func (s *Sketch[T]) Estimate(item T) uint64 {
+	estimate := uint64(math.MaxUint64)
+	s.cells(item, func(i int) {
+		if s.counters[i] < estimate {
+			estimate = s.counters[i]
+		}
+	})
+	return estimate
+}
+
+// Total returns the sum of all counts added.
+func (s *Sketch[T]) Total() uint64 {
+	return s.total
+}
+
+// Width returns the number of counters per row.
+func (s *Sketch[T]) Width() int {
+	return s.width
+}
+
+// Depth returns the number of rows.
+func (s *Sketch[T]) Depth() int {
+	return s.depth
+}
+
+// Merge adds all counts of other to s. Both sketches must have the same dimensions and hasher.
+// The result of merging conservative sketches still never underestimates.
+func (s *Sketch[T]) Merge(other *Sketch[T]) error {
+	if s.width != other.width || s.depth != other.depth {
+		return fmt.Errorf("%w: %d×%d and %d×%d", ErrIncompatible, s.depth, s.width, other.depth, other.width)
+	}
+	for i, c := range other.counters {
+		s.counters[i] += c
+	}
+	s.total += other.total
+	return nil
+}
+
+// Clear resets all counters.
+func (s *Sketch[T]) Clear() {
+	for i := range s.counters {
+		s.counters[i] = 0
+	}
+	s.total = 0
+}
@@ -0,0 +1,165 @@
+package countmin
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// stream returns a skewed stream of n items drawn from [0, m) and the exact count of each.
+func stream(seed int64, n int, m uint64) ([]int, map[int]uint64) {
+	r := rand.New(rand.NewSource(seed))
+	zipf := rand.NewZipf(r, 1.2, 1, m-1)
+	items := make([]int, n)
+	counts := make(map[int]uint64)
+	for i := range items {
+		items[i] = int(zipf.Uint64())
+		counts[items[i]]++
+	}
+	return items, counts
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(common.IntHasher, 0, 1)
+	assert.True(t, errors.Is(err, ErrInvalidParameter))
+	_, err = NewWithEstimates(common.IntHasher, 0.01, 1)
+	assert.True(t, errors.Is(err, ErrInvalidParameter))
+
+	s, err := NewWithEstimates(common.IntHasher, 0.001, 0.01)
+	assert.NoError(t, err)
+	assert.Equal(t, 2719, s.Width())
+	assert.Equal(t, 5, s.Depth())
+}
+
+func TestEstimate(t *testing.T) {
+	items, counts := stream(1, 100000, 10000)
+	plain, _ := NewWithEstimates(common.IntHasher, 0.001, 0.01)
+	conservative, _ := NewWithEstimates(common.IntHasher, 0.001, 0.01, WithConservativeUpdate[int]())
+	for _, item := range items {
+		plain.Add(item, 1)
+		conservative.Add(item, 1)
+	}
+	assert.Equal(t, uint64(len(items)), plain.Total())
+	assert.Equal(t, uint64(len(items)), conservative.Total())
+
+	bound := uint64(0.001 * float64(len(items)))
+	var plainError, conservativeError uint64
+	for item, count := range counts {
+		p, c := plain.Estimate(item), conservative.Estimate(item)
+		assert.GreaterOrEqual(t, p, count)
+		assert.GreaterOrEqual(t, c, count)
+		assert.LessOrEqual(t, c, p)
+		assert.LessOrEqual(t, p-count, bound)
+		plainError += p - count
+		conservativeError += c - count
+	}
+	assert.Less(t, conservativeError, plainError/2)
+
+	plain.Clear()
+	assert.Equal(t, uint64(0), plain.Estimate(items[0]))
+	assert.Equal(t, uint64(0), plain.Total())
+}
+
+func TestMerge(t *testing.T) {
+	a, _ := New(common.StringHasher, 100, 4)
+	b, _ := New(common.StringHasher, 100, 4)
+	whole, _ := New(common.StringHasher, 100, 4)
+	for i, word := range []string{"a", "b", "c", "a", "d", "a", "b"} {
+		if i%2 == 0 {
+			a.Add(word, 2)
+		} else {
+			b.Add(word, 2)
+		}
+		whole.Add(word, 2)
+	}
+	assert.NoError(t, a.Merge(b))
+	assert.Equal(t, whole.counters, a.counters)
+	assert.Equal(t, uint64(14), a.Total())
+	assert.GreaterOrEqual(t, a.Estimate("a"), uint64(6))
+
+	c, _ := New(common.StringHasher, 100, 3)
+	assert.True(t, errors.Is(a.Merge(c), ErrIncompatible))
+}
+
+func TestMarshalBinary(t *testing.T) {
+	s, _ := New(common.IntHasher, 50, 3, WithConservativeUpdate[int]())
+	for i := 0; i < 1000; i++ {
+		s.Add(i%37, uint64(i))
+	}
+	data, err := s.MarshalBinary()
+	assert.NoError(t, err)
+
+	decoded, _ := New(common.IntHasher, 1, 1)
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, s.counters, decoded.counters)
+	assert.Equal(t, s.Total(), decoded.Total())
+	assert.True(t, decoded.conservative)
+	for i := 0; i < 37; i++ {
+		assert.Equal(t, s.Estimate(i), decoded.Estimate(i))
+	}
+
+	for _, bad := range [][]byte{nil, data[:len(data)-1], append(data, 0), append([]byte{0}, data[1:]...)} {
+		assert.True(t, errors.Is(decoded.UnmarshalBinary(bad), ErrInvalidFormat))
+	}
+}
+
+// exactTop returns the k most frequent items by exact count.
+func exactTop(counts map[int]uint64, k int) []int {
+	items := make([]int, 0, len(counts))
+	for item := range counts {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return counts[items[i]] > counts[items[j]] || counts[items[i]] == counts[items[j]] && items[i] < items[j]
+	})
+	return items[:k]
+}
+
+func TestHeavyHitters(t *testing.T) {
+	const k = 10
+	items, counts := stream(2, 200000, 100000)
+	sketch, _ := NewWithEstimates(common.IntHasher, 0.0005, 0.01, WithConservativeUpdate[int]())
+	h := NewHeavyHitters(sketch, k)
+	for _, item := range items {
+		h.Add(item, 1)
+	}
+
+	top := h.Top()
+	assert.Len(t, top, k)
+	got := make([]int, k)
+	for i, e := range top {
+		got[i] = e.Item
+		assert.Equal(t, sketch.Estimate(e.Item), e.Count)
+		if i > 0 {
+			assert.GreaterOrEqual(t, top[i-1].Count, e.Count)
+		}
+	}
+	assert.ElementsMatch(t, exactTop(counts, k), got)
+	assert.LessOrEqual(t, h.heap.Size(), 2*k)
+
+	// Split the stream, track each half separately and merge.
+	first, _ := NewWithEstimates(common.IntHasher, 0.0005, 0.01)
+	second, _ := NewWithEstimates(common.IntHasher, 0.0005, 0.01)
+	h1, h2 := NewHeavyHitters(first, k), NewHeavyHitters(second, k)
+	for i, item := range items {
+		if i < len(items)/2 {
+			h1.Add(item, 1)
+		} else {
+			h2.Add(item, 1)
+		}
+	}
+	assert.NoError(t, h1.Merge(h2))
+	merged := make([]int, 0, k)
+	for _, e := range h1.Top() {
+		merged = append(merged, e.Item)
+	}
+	assert.ElementsMatch(t, exactTop(counts, k), merged)
+
+	h.Clear()
+	assert.Empty(t, h.Top())
+	assert.Equal(t, uint64(0), h.Sketch().Total())
+}
@@ -0,0 +1,134 @@
+package countmin
+
+import (
+	"github.com/kwstars/goads/sorting"
+	"github.com/kwstars/goads/trees/binaryheap"
+)
+
+// Entry is an item together with its estimated count.
+type Entry[T any] struct {
+	Item  T
+	Count uint64
+}
+
+// HeavyHitters tracks the k items with the highest estimated counts in a stream.
+//
+// Every item is counted in a Sketch; the current top k are kept in a map and in a min-heap on
+// their estimates. The heap has no decrease-key, so an item whose estimate grows is pushed again
+// and outdated entries are discarded when they reach the top (lazy deletion).
+type HeavyHitters[T comparable] struct {
+	sketch *Sketch[T]
+	k      int
+	top    map[T]uint64
+	heap   *binaryheap.BinaryHeap[Entry[T]]
+}
+
+// lowestFirst makes the heap return the entry with the lowest count first.
+func lowestFirst[T any](a, b Entry[T]) int8 {
+	if a.Count < b.Count {
+		return 1
+	} else if a.Count > b.Count {
+		return -1
+	}
+	return 0
+}
+
+// NewHeavyHitters returns a tracker of the k most frequent items that counts items in sketch.
+func NewHeavyHitters[T comparable](sketch *Sketch[T], k int) *HeavyHitters[T] {
+	return &HeavyHitters[T]{
+		sketch: sketch,
+		k:      k,
+		top:    make(map[T]uint64, k+1),
+		heap:   binaryheap.New(lowestFirst[T], binaryheap.WithInitialCapacity[Entry[T]](2*k)),
+	}
+}
+
+// Sketch returns the sketch the items are counted in.
+func (h *HeavyHitters[T]) Sketch() *Sketch[T] {
+	return h.sketch
+}
+
+// Add adds count occurrences of the item.
+func (h *HeavyHitters[T]) Add(item T, count uint64) {
+	h.sketch.Add(item, count)
+	h.offer(item, h.sketch.Estimate(item))
+}
+
+// offer records the estimate of item and keeps the k highest.
+func (h *HeavyHitters[T]) offer(item T, estimate uint64) {
+	if h.k <= 0 {
+		return
+	}
+	if _, ok := h.top[item]; !ok && len(h.top) == h.k {
+		if estimate <= h.min().Count {
+			return
+		}
+		evicted, _ := h.heap.Pop()
+		delete(h.top, evicted.Item)
+	}
+	h.top[item] = estimate
+	h.heap.Push(Entry[T]{Item: item, Count: estimate})
+
+	if h.heap.Size() > 2*h.k {
+		h.rebuild()
+	}
+}
+
+// min drops outdated heap entries and returns the tracked entry with the lowest count.
+func (h *HeavyHitters[T]) min() Entry[T] {
+	for {
+		e, _ := h.heap.Peek()
+		if count, ok := h.top[e.Item]; ok && count == e.Count {
+			return e
+		}
+		_, _ = h.heap.Pop()
+	}
+}
+
+// rebuild refills the heap with one entry per tracked item.
+func (h *HeavyHitters[T]) rebuild() {
+	h.heap.Clear()
+	for item, count := range h.top {
+		h.heap.Push(Entry[T]{Item: item, Count: count})
+	}
+}
+
+// Top returns the tracked items by estimated count, highest first.
+func (h *HeavyHitters[T]) Top() []Entry[T] {
+	entries := make([]Entry[T], 0, len(h.top))
+	for item, count := range h.top {
+		entries = append(entries, Entry[T]{Item: item, Count: count})
+	}
+	sorting.TimSort(entries, lowestFirst[T])
+	return entries
+}
+
+// Merge merges the sketch of other into the sketch of h and keeps the k highest of the items
+// tracked by either, re-estimated on the merged sketch.
+func (h *HeavyHitters[T]) Merge(other *HeavyHitters[T]) error {
+	if err := h.sketch.Merge(other.sketch); err != nil {
+		return err
+	}
+	candidates := make([]T, 0, len(h.top)+len(other.top))
+	for item := range h.top {
+		candidates = append(candidates, item)
+	}
+	for item := range other.top {
+		if _, ok := h.top[item]; !ok {
+			candidates = append(candidates, item)
+		}
+	}
+	h.top = make(map[T]uint64, h.k+1)
+	h.heap.Clear()
+	for _, item := range candidates {
+		h.offer(item, h.sketch.Estimate(item))
+	}
+	return nil
+}
+
+// Clear resets the sketch and forgets all tracked items.
+func (h *HeavyHitters[T]) Clear() {
+	h.sketch.Clear()
+	h.top = make(map[T]uint64, h.k+1)
+	h.heap.Clear()
+}
@@ -0,0 +1,69 @@
+package countmin
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// The serialized format is little-endian:
+//
+//	cookie        uint32  0x31534d43 ("CMS1")
+//	width, depth  uint32
+//	conservative  uint8   0 or 1
+//	total         uint64
+//	counters      depth × width × uint64, row by row
+//
+// The hasher is not part of the encoding.
+const cookie uint32 = 0x31534d43
+
+const headerSize = 21
+
+var ErrInvalidFormat = errors.New("invalid count-min sketch encoding")
+
+// MarshalBinary encodes the sketch.
+func (s *Sketch[T]) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, headerSize+8*len(s.counters))
+	buf = appendUint32(buf, cookie)
+	buf = appendUint32(buf, uint32(s.width))
+	buf = appendUint32(buf, uint32(s.depth))
+	conservative := byte(0)
+	if s.conservative {
+		conservative = 1
+	}
+	buf = append(buf, conservative)
+	buf = appendUint64(buf, s.total)
+	for _, c := range s.counters {
+		buf = appendUint64(buf, c)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary replaces the content of the sketch with the decoded data. The hasher is kept.
+func (s *Sketch[T]) UnmarshalBinary(data []byte) error {
+	le := binary.LittleEndian
+	if len(data) < headerSize || le.Uint32(data) != cookie {
+		return fmt.Errorf("%w: bad header", ErrInvalidFormat)
+	}
+	width, depth, conservative, total := uint64(le.Uint32(data[4:])), uint64(le.Uint32(data[8:])), data[12], le.Uint64(data[13:])
+	data = data[headerSize:]
+	if width == 0 || depth == 0 || conservative > 1 || uint64(len(data)) != 8*width*depth {
+		return fmt.Errorf("%w: %d bytes for %d×%d counters", ErrInvalidFormat, len(data), depth, width)
+	}
+
+	counters := make([]uint64, width*depth)
+	for i := range counters {
+		counters[i] = le.Uint64(data[8*i:])
+	}
+	s.counters, s.width, s.depth, s.total = counters, int(width), int(depth), total
+	s.conservative = conservative == 1
+	return nil
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	return appendUint32(appendUint32(buf, uint32(v)), uint32(v>>32))
+}
@@ -0,0 +1,246 @@
+// Package hyperloglog implements HyperLogLog, a sketch that estimates the number of distinct items
+// in fixed memory.
+//
+// The dense representation keeps m = 2^p one-byte registers; each holds the longest run of leading
+// zeros seen among the hashes routed to it, and the estimate has a standard error of about 1.04/√m.
+// Small sketches start in a sparse representation (as in HyperLogLog++): a sorted list of
+// (index, rank) pairs at precision 25, which is far more accurate at low cardinalities and is
+// converted to dense registers once it would use more memory than them.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/HyperLogLog,
+// https://static.googleusercontent.com/media/research.google.com/en//pubs/archive/40671.pdf (HyperLogLog++)
+package hyperloglog
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/sorting"
+)
+
+const (
+	MinPrecision = 4
+	MaxPrecision = 18
+
+	// sparsePrecision is the number of index bits of a sparse entry. An entry packs the index
+	// and a 6-bit rank into a uint32: index<<6 | rank.
+	sparsePrecision = 25
+)
+
+var (
+	ErrInvalidPrecision = errors.New("invalid precision")
+	ErrIncompatible     = errors.New("sketches have different precisions")
+)
+
+// Sketch is a HyperLogLog sketch.
+type Sketch[T any] struct {
+	p         uint8
+	registers []uint8  // dense registers, nil while the sketch is sparse
+	sparse    []uint32 // sorted entries, one per index
+	buffer    []uint32 // unsorted entries not yet merged into sparse
+	hasher    common.Hasher[T]
+}
+
+// New returns an empty sketch with 2^precision registers.
+func New[T any](hasher common.Hasher[T], precision int) (*Sketch[T], error) {
+	if precision < MinPrecision || precision > MaxPrecision {
+		return nil, fmt.Errorf("%w: %d not in [%d, %d]", ErrInvalidPrecision, precision, MinPrecision, MaxPrecision)
+	}
+	return &Sketch[T]{p: uint8(precision), hasher: hasher}, nil
+}
+
+// Precision returns the number of index bits p.
+func (s *Sketch[T]) Precision() int {
+	return int(s.p)
+}
+
+// Sparse returns true while the sketch uses the sparse representation.
+func (s *Sketch[T]) Sparse() bool {
+	return s.registers == nil
+}
+
+// Add adds the item.
+func (s *Sketch[T]) Add(item T) {
+	h := s.hasher(item)
+	if s.registers != nil {
+		idx, rank := split(h, s.p)
+		if rank > s.registers[idx] {
+			s.registers[idx] = rank
+		}
+		return
+	}
+
+	idx, rank := split(h, sparsePrecision)
+	s.buffer = append(s.buffer, idx<<6|uint32(rank))
+	if len(s.buffer) >= s.sparseLimit() {
+		s.flush()
+	}
+}
+
+// split returns the top p bits of h as the register index and the position of the first
+// set bit in the remaining bits as the rank.
+func split(h uint64, p uint8) (uint32, uint8) {
+	return uint32(h >> (64 - p)), uint8(bits.LeadingZeros64(h<<p|1<<(p-1))) + 1
+}
+
+// sparseLimit returns the number of sparse entries that take as much memory as the dense registers.
+func (s *Sketch[T]) sparseLimit() int {
+	return 1 << s.p / 4
+}
+
+// flush merges the buffer into the sparse list and switches to dense registers when the list grows too long.
+func (s *Sketch[T]) flush() {
+	if len(s.buffer) > 0 {
+		sorting.PDQSort(s.buffer, compareEntries)
+		s.sparse = mergeSparse(s.sparse, s.buffer)
+		s.buffer = s.buffer[:0]
+	}
+	if len(s.sparse) > s.sparseLimit() {
+		s.toDense()
+	}
+}
+
+func compareEntries(a, b uint32) int8 {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// mergeSparse merges two sorted entry lists, keeping the highest rank of every index.
+// b may contain several entries per index.
+func mergeSparse(a, b []uint32) []uint32 {
+	merged := make([]uint32, 0, len(a)+len(b))
+	push := func(e uint32) {
+		// Entries arrive ordered by index, then rank: a later entry of the same index has a higher rank.
+		if n := len(merged); n > 0 && merged[n-1]>>6 == e>>6 {
+			merged[n-1] = e
+			return
+		}
+		merged = append(merged, e)
+	}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i] <= b[j] {
+			push(a[i])
+			i++
+		} else {
+			push(b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		push(a[i])
+	}
+	for ; j < len(b); j++ {
+		push(b[j])
+	}
+	return merged
+}
+
+// toDense converts the sparse entries into registers.
+func (s *Sketch[T]) toDense() {
+	s.registers = make([]uint8, 1<<s.p)
+	s.addSparse(s.sparse)
+	s.addSparse(s.buffer)
+	s.sparse, s.buffer = nil, nil
+}
+
+// addSparse folds sparse entries into the dense registers. The index bits beyond p
+// become the leading bits of the dense rank.
+func (s *Sketch[T]) addSparse(entries []uint32) {
+	extra := sparsePrecision - s.p
+	for _, e := range entries {
+		idx, rank := e>>6, uint8(e&63)
+		if low := idx & (1<<extra - 1); low != 0 {
+			rank = uint8(bits.LeadingZeros32(low<<(32-extra))) + 1
+		} else {
+			rank += extra
+		}
+		if r := &s.registers[idx>>extra]; rank > *r {
+			*r = rank
+		}
+	}
+}
+
+// Count returns the estimated number of distinct items added.
+func (s *Sketch[T]) Count() uint64 {
+	if s.registers == nil {
+		s.flush()
+	}
+	if s.registers == nil {
+		// Linear counting over the 2^25 sparse registers.
+		return uint64(math.Round(linearCounting(1<<sparsePrecision, 1<<sparsePrecision-len(s.sparse))))
+	}
+
+	m := float64(len(s.registers))
+	sum, zeros := 0.0, 0
+	for _, r := range s.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+	estimate := alpha(len(s.registers)) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = linearCounting(m, zeros)
+	}
+	return uint64(math.Round(estimate))
+}
+
+func linearCounting(m float64, zeros int) float64 {
+	return m * math.Log(m/float64(zeros))
+}
+
+// alpha is the bias correction constant for m registers.
+func alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/float64(m))
+}
+
+// Merge adds all items of other to s, as if s had seen both streams. Both sketches must have the same precision.
+func (s *Sketch[T]) Merge(other *Sketch[T]) error {
+	if s.p != other.p {
+		return fmt.Errorf("%w: %d and %d", ErrIncompatible, s.p, other.p)
+	}
+	if s.registers == nil && other.registers == nil {
+		s.buffer = append(s.buffer, other.sparse...)
+		s.buffer = append(s.buffer, other.buffer...)
+		s.flush()
+		return nil
+	}
+
+	if s.registers == nil {
+		s.toDense()
+	}
+	if other.registers == nil {
+		s.addSparse(other.sparse)
+		s.addSparse(other.buffer)
+		return nil
+	}
+	for i, r := range other.registers {
+		if r > s.registers[i] {
+			s.registers[i] = r
+		}
+	}
+	return nil
+}
+
+// Clear removes all items and returns to the sparse representation.
+func (s *Sketch[T]) Clear() {
+	s.registers, s.sparse, s.buffer = nil, nil, nil
+}
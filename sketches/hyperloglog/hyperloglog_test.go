@@ -0,0 +1,131 @@
+package hyperloglog
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func relativeError(estimate uint64, n int) float64 {
+	return math.Abs(float64(estimate)-float64(n)) / float64(n)
+}
+
+func TestNew(t *testing.T) {
+	for _, p := range []int{MinPrecision - 1, MaxPrecision + 1} {
+		_, err := New(common.IntHasher, p)
+		assert.True(t, errors.Is(err, ErrInvalidPrecision))
+	}
+	s, err := New(common.IntHasher, 14)
+	assert.NoError(t, err)
+	assert.Equal(t, 14, s.Precision())
+	assert.True(t, s.Sparse())
+	assert.Equal(t, uint64(0), s.Count())
+}
+
+func TestCount(t *testing.T) {
+	for _, p := range []int{MinPrecision, 10, 14} {
+		s, _ := New(common.IntHasher, p)
+		tolerance := 4 * 1.04 / math.Sqrt(float64(int(1)<<p))
+		n := 0
+		for _, target := range []int{10, 100, 1000, 10000, 200000} {
+			for ; n < target; n++ {
+				s.Add(n)
+				s.Add(n) // duplicates do not count
+			}
+			if s.Sparse() {
+				// Sparse mode is nearly exact.
+				assert.InDelta(t, n, s.Count(), 0.01*float64(n)+1, "p=%d n=%d", p, n)
+			} else {
+				assert.Less(t, relativeError(s.Count(), n), tolerance, "p=%d n=%d", p, n)
+			}
+		}
+		assert.False(t, s.Sparse())
+	}
+}
+
+func TestMerge(t *testing.T) {
+	newSketch := func(lo, hi int) *Sketch[int] {
+		s, _ := New(common.IntHasher, 12)
+		for i := lo; i < hi; i++ {
+			s.Add(i)
+		}
+		return s
+	}
+	tests := []struct {
+		name         string
+		a, b         [2]int
+		sparse       bool
+		distinctSeen int
+	}{
+		{"sparse+sparse", [2]int{0, 300}, [2]int{200, 500}, true, 500},
+		{"sparse+sparse overflows", [2]int{0, 800}, [2]int{800, 1600}, false, 1600},
+		{"sparse+dense", [2]int{0, 500}, [2]int{0, 50000}, false, 50000},
+		{"dense+sparse", [2]int{0, 50000}, [2]int{49900, 50100}, false, 50100},
+		{"dense+dense", [2]int{0, 50000}, [2]int{25000, 75000}, false, 75000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := newSketch(tt.a[0], tt.a[1]), newSketch(tt.b[0], tt.b[1])
+			assert.NoError(t, a.Merge(b))
+			assert.Equal(t, tt.sparse, a.Sparse())
+			assert.Less(t, relativeError(a.Count(), tt.distinctSeen), 0.07)
+
+			// Merging equals adding both streams into one sketch when both end up dense.
+			union := newSketch(tt.a[0], tt.a[1])
+			for i := tt.b[0]; i < tt.b[1]; i++ {
+				union.Add(i)
+			}
+			if !union.Sparse() && !a.Sparse() {
+				assert.Equal(t, union.registers, a.registers)
+			}
+		})
+	}
+
+	other, _ := New(common.IntHasher, 13)
+	assert.True(t, errors.Is(newSketch(0, 1).Merge(other), ErrIncompatible))
+}
+
+func TestMarshalBinary(t *testing.T) {
+	for _, n := range []int{0, 100, 100000} {
+		s, _ := New(common.StringHasher, 12)
+		for i := 0; i < n; i++ {
+			s.Add(string(rune(i)) + "x")
+		}
+		data, err := s.MarshalBinary()
+		assert.NoError(t, err)
+
+		decoded, _ := New(common.StringHasher, 4)
+		assert.NoError(t, decoded.UnmarshalBinary(data))
+		assert.Equal(t, s.Sparse(), decoded.Sparse())
+		assert.Equal(t, s.Precision(), decoded.Precision())
+		assert.Equal(t, s.Count(), decoded.Count())
+
+		for _, bad := range [][]byte{nil, data[:len(data)-1], append(data, 0)} {
+			assert.True(t, errors.Is(decoded.UnmarshalBinary(bad), ErrInvalidFormat))
+		}
+	}
+
+	s, _ := New(common.IntHasher, 12)
+	s.Add(1)
+	s.Add(2)
+	data, _ := s.MarshalBinary()
+	// Swap the order of the two entries.
+	first := append([]byte(nil), data[10:14]...)
+	copy(data[10:14], data[14:18])
+	copy(data[14:18], first)
+	assert.True(t, errors.Is(s.UnmarshalBinary(data), ErrInvalidFormat))
+}
+
+func TestClear(t *testing.T) {
+	s, _ := New(common.IntHasher, 8)
+	for i := 0; i < 1000; i++ {
+		s.Add(i)
+	}
+	assert.False(t, s.Sparse())
+	s.Clear()
+	assert.True(t, s.Sparse())
+	assert.Equal(t, uint64(0), s.Count())
+}
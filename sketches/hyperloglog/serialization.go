@@ -0,0 +1,90 @@
+package hyperloglog
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// The serialized format is little-endian:
+//
+//	cookie     uint32  0x314c4c48 ("HLL1")
+//	precision  uint8
+//	kind       uint8   1 = sparse, 2 = dense
+//	payload            sparse: n uint32, n × uint32 sorted entries; dense: 2^precision × uint8 registers
+//
+// The hasher is not part of the encoding.
+const cookie uint32 = 0x314c4c48
+
+const (
+	kindSparse uint8 = iota + 1
+	kindDense
+)
+
+var ErrInvalidFormat = errors.New("invalid hyperloglog encoding")
+
+// MarshalBinary encodes the sketch, keeping its representation.
+func (s *Sketch[T]) MarshalBinary() ([]byte, error) {
+	if s.registers == nil {
+		s.flush()
+	}
+	buf := make([]byte, 0, 10+len(s.registers)+4*len(s.sparse))
+	buf = appendUint32(buf, cookie)
+	if s.registers != nil {
+		buf = append(buf, s.p, kindDense)
+		return append(buf, s.registers...), nil
+	}
+	buf = append(buf, s.p, kindSparse)
+	buf = appendUint32(buf, uint32(len(s.sparse)))
+	for _, e := range s.sparse {
+		buf = appendUint32(buf, e)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary replaces the content of the sketch with the decoded data. The hasher is kept.
+func (s *Sketch[T]) UnmarshalBinary(data []byte) error {
+	le := binary.LittleEndian
+	if len(data) < 6 || le.Uint32(data) != cookie {
+		return fmt.Errorf("%w: bad header", ErrInvalidFormat)
+	}
+	p, kind, data := data[4], data[5], data[6:]
+	if p < MinPrecision || p > MaxPrecision {
+		return fmt.Errorf("%w: precision %d", ErrInvalidFormat, p)
+	}
+
+	switch kind {
+	case kindDense:
+		if len(data) != 1<<p {
+			return fmt.Errorf("%w: %d registers for precision %d", ErrInvalidFormat, len(data), p)
+		}
+		for _, r := range data {
+			if r > 64-p+1 {
+				return fmt.Errorf("%w: register value %d", ErrInvalidFormat, r)
+			}
+		}
+		s.registers = append([]uint8(nil), data...)
+		s.sparse, s.buffer = nil, nil
+	case kindSparse:
+		if len(data) < 4 || uint64(len(data)-4) != 4*uint64(le.Uint32(data)) {
+			return fmt.Errorf("%w: truncated sparse list", ErrInvalidFormat)
+		}
+		sparse := make([]uint32, le.Uint32(data))
+		for i := range sparse {
+			sparse[i] = le.Uint32(data[4+4*i:])
+			if rank := sparse[i] & 63; rank == 0 || rank > 64-sparsePrecision+1 ||
+				i > 0 && sparse[i]>>6 <= sparse[i-1]>>6 {
+				return fmt.Errorf("%w: sparse entry %d", ErrInvalidFormat, i)
+			}
+		}
+		s.registers, s.sparse, s.buffer = nil, sparse, nil
+	default:
+		return fmt.Errorf("%w: kind %d", ErrInvalidFormat, kind)
+	}
+	s.p = p
+	return nil
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
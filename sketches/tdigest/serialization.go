@@ -0,0 +1,79 @@
+package tdigest
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The serialized format is little-endian:
+//
+//	cookie       uint32   0x31474454 ("TDG1")
+//	compression  float64
+//	min, max     float64
+//	n            uint32   number of centroids
+//	centroids    n × (mean, weight float64), sorted by mean
+const cookie uint32 = 0x31474454
+
+const headerSize = 32
+
+var ErrInvalidFormat = errors.New("invalid t-digest encoding")
+
+// MarshalBinary encodes the digest after merging buffered points.
+func (d *Digest) MarshalBinary() ([]byte, error) {
+	d.compress()
+	buf := make([]byte, 0, headerSize+16*len(d.centroids))
+	buf = appendUint32(buf, cookie)
+	buf = appendFloat64(buf, d.compression)
+	buf = appendFloat64(buf, d.min)
+	buf = appendFloat64(buf, d.max)
+	buf = appendUint32(buf, uint32(len(d.centroids)))
+	for _, c := range d.centroids {
+		buf = appendFloat64(buf, c.Mean)
+		buf = appendFloat64(buf, c.Weight)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary replaces the content of the digest with the decoded data.
+func (d *Digest) UnmarshalBinary(data []byte) error {
+	le := binary.LittleEndian
+	if len(data) < headerSize || le.Uint32(data) != cookie {
+		return fmt.Errorf("%w: bad header", ErrInvalidFormat)
+	}
+	float := func(i int) float64 {
+		return math.Float64frombits(le.Uint64(data[i:]))
+	}
+	compression, min, max, n := float(4), float(12), float(20), uint64(le.Uint32(data[28:]))
+	if !validCompression(compression) || uint64(len(data)-headerSize) != 16*n {
+		return fmt.Errorf("%w: compression %v, %d centroids in %d bytes", ErrInvalidFormat, compression, n, len(data))
+	}
+
+	centroids := make([]Centroid, n)
+	weight := 0.0
+	for i := range centroids {
+		c := Centroid{Mean: float(headerSize + 16*i), Weight: float(headerSize + 16*i + 8)}
+		if !(c.Weight > 0) || math.IsInf(c.Weight, 1) || !(c.Mean >= min && c.Mean <= max) ||
+			i > 0 && c.Mean < centroids[i-1].Mean {
+			return fmt.Errorf("%w: centroid %d", ErrInvalidFormat, i)
+		}
+		centroids[i] = c
+		weight += c.Weight
+	}
+	if n == 0 {
+		min, max = math.Inf(1), math.Inf(-1)
+	}
+	d.compression, d.centroids, d.buffer = compression, centroids, nil
+	d.weight, d.min, d.max = weight, min, max
+	return nil
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func appendFloat64(buf []byte, v float64) []byte {
+	b := math.Float64bits(v)
+	return appendUint32(appendUint32(buf, uint32(b)), uint32(b>>32))
+}
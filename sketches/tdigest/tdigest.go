@@ -0,0 +1,243 @@
+// Package tdigest implements the merging t-digest, a sketch that estimates quantiles and ranks of a
+// stream of numbers in bounded memory.
+//
+// The digest summarizes the data as a sorted list of centroids (mean, weight). Centroids near the
+// median may absorb many points while those near the tails stay small, so extreme quantiles are
+// estimated with a small relative error. The size of a centroid is bounded by the scale function
+// k(q) = δ/2π · asin(2q-1): one centroid spans at most one unit of k, giving at most about δ centroids
+// for compression δ. New points are buffered and merged into the centroids in one sorted pass.
+//
+// Structure is not thread safe.
+//
+// References: https://arxiv.org/abs/1902.04023, https://github.com/tdunning/t-digest
+package tdigest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/kwstars/goads/sorting"
+)
+
+const (
+	DefaultCompression = 100
+	MinCompression     = 10
+	// MaxCompression bounds the number of centroids and the size of the buffer of unmerged points.
+	MaxCompression = 1e6
+)
+
+var (
+	ErrInvalidParameter = errors.New("invalid digest parameter")
+	ErrEmpty            = errors.New("digest is empty")
+)
+
+// Centroid is a cluster of points summarized by their mean and total weight.
+type Centroid struct {
+	Mean   float64
+	Weight float64
+}
+
+func byMean(a, b Centroid) int8 {
+	if a.Mean < b.Mean {
+		return -1
+	} else if a.Mean > b.Mean {
+		return 1
+	}
+	return 0
+}
+
+// Digest is a merging t-digest.
+type Digest struct {
+	compression float64
+	centroids   []Centroid // merged centroids, sorted by mean
+	buffer      []Centroid // points not yet merged
+	weight      float64    // total weight of centroids and buffer
+	min, max    float64
+}
+
+// New returns an empty digest. A higher compression keeps more centroids and gives more
+// accurate estimates; DefaultCompression is a good starting point. The compression must be
+// in [MinCompression, MaxCompression].
+func New(compression float64) (*Digest, error) {
+	if !validCompression(compression) {
+		return nil, fmt.Errorf("%w: compression %v", ErrInvalidParameter, compression)
+	}
+	return &Digest{compression: compression, min: math.Inf(1), max: math.Inf(-1)}, nil
+}
+
+func validCompression(compression float64) bool {
+	return compression >= MinCompression && compression <= MaxCompression
+}
+
+// Compression returns the compression parameter δ.
+func (d *Digest) Compression() float64 {
+	return d.compression
+}
+
+// Add adds the value x once.
+func (d *Digest) Add(x float64) error {
+	return d.AddWeighted(x, 1)
+}
+
+// AddWeighted adds the value x with weight w.
+func (d *Digest) AddWeighted(x, w float64) error {
+	if math.IsNaN(x) || math.IsInf(x, 0) || !(w > 0) || math.IsInf(w, 1) {
+		return fmt.Errorf("%w: value %v with weight %v", ErrInvalidParameter, x, w)
+	}
+	d.add(Centroid{Mean: x, Weight: w})
+	return nil
+}
+
+func (d *Digest) add(c Centroid) {
+	d.buffer = append(d.buffer, c)
+	d.weight += c.Weight
+	if c.Mean < d.min {
+		d.min = c.Mean
+	}
+	if c.Mean > d.max {
+		d.max = c.Mean
+	}
+	if len(d.buffer) >= int(5*d.compression) {
+		d.compress()
+	}
+}
+
+// scale is the k1 scale function.
+func (d *Digest) scale(q float64) float64 {
+	return d.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+// compress merges the buffer into the centroids.
+func (d *Digest) compress() {
+	if len(d.buffer) == 0 {
+		return
+	}
+	all := append(d.buffer, d.centroids...)
+	sorting.TimSort(all, byMean)
+
+	size := int(d.compression)
+	if size > len(all) {
+		size = len(all)
+	}
+	merged := make([]Centroid, 0, size)
+	cur := all[0]
+	before := 0.0 // weight of the centroids left of cur
+	kLeft := d.scale(0)
+	for _, c := range all[1:] {
+		if d.scale((before+cur.Weight+c.Weight)/d.weight)-kLeft <= 1 {
+			cur.Weight += c.Weight
+			cur.Mean += (c.Mean - cur.Mean) * c.Weight / cur.Weight
+			continue
+		}
+		merged = append(merged, cur)
+		before += cur.Weight
+		kLeft = d.scale(before / d.weight)
+		cur = c
+	}
+	d.centroids = append(merged, cur)
+	d.buffer = d.buffer[:0]
+}
+
+// Count returns the total weight added.
+func (d *Digest) Count() float64 {
+	return d.weight
+}
+
+// Min returns the smallest value added.
+func (d *Digest) Min() (float64, error) {
+	if d.weight == 0 {
+		return 0, ErrEmpty
+	}
+	return d.min, nil
+}
+
+// Max returns the largest value added.
+func (d *Digest) Max() (float64, error) {
+	if d.weight == 0 {
+		return 0, ErrEmpty
+	}
+	return d.max, nil
+}
+
+// Centroids returns a copy of the centroids after merging buffered points.
+func (d *Digest) Centroids() []Centroid {
+	d.compress()
+	return append([]Centroid(nil), d.centroids...)
+}
+
+// knots returns the piecewise-linear approximation of the inverse CDF: cumulative weights and
+// values, starting at (0, min) and ending at (weight, max), with a knot at the middle of each centroid.
+func (d *Digest) knots() (ranks, values []float64) {
+	d.compress()
+	ranks = make([]float64, 0, len(d.centroids)+2)
+	values = make([]float64, 0, len(d.centroids)+2)
+	ranks, values = append(ranks, 0), append(values, d.min)
+	before := 0.0
+	for _, c := range d.centroids {
+		ranks, values = append(ranks, before+c.Weight/2), append(values, c.Mean)
+		before += c.Weight
+	}
+	return append(ranks, d.weight), append(values, d.max)
+}
+
+// Quantile returns the estimated value below which a fraction q of the weight lies.
+func (d *Digest) Quantile(q float64) (float64, error) {
+	if !(q >= 0 && q <= 1) {
+		return 0, fmt.Errorf("%w: quantile %v", ErrInvalidParameter, q)
+	}
+	if d.weight == 0 {
+		return 0, ErrEmpty
+	}
+	ranks, values := d.knots()
+	target := q * d.weight
+	i := 1
+	for i < len(ranks)-1 && ranks[i] < target {
+		i++
+	}
+	return interpolate(target, ranks[i-1], ranks[i], values[i-1], values[i]), nil
+}
+
+// CDF returns the estimated fraction of the weight at or below x.
+func (d *Digest) CDF(x float64) (float64, error) {
+	if d.weight == 0 {
+		return 0, ErrEmpty
+	}
+	if x < d.min {
+		return 0, nil
+	}
+	if x >= d.max {
+		return 1, nil
+	}
+	ranks, values := d.knots()
+	i := 1
+	for values[i] <= x {
+		i++
+	}
+	return interpolate(x, values[i-1], values[i], ranks[i-1], ranks[i]) / d.weight, nil
+}
+
+// interpolate maps x in [x0, x1] linearly onto [y0, y1].
+func interpolate(x, x0, x1, y0, y1 float64) float64 {
+	if x1 == x0 {
+		return y1
+	}
+	return y0 + (y1-y0)*(x-x0)/(x1-x0)
+}
+
+// Merge adds all points summarized by other to d.
+func (d *Digest) Merge(other *Digest) {
+	for _, c := range other.centroids {
+		d.add(c)
+	}
+	for _, c := range other.buffer {
+		d.add(c)
+	}
+	d.compress()
+}
+
+// Clear removes all points.
+func (d *Digest) Clear() {
+	d.centroids, d.buffer = nil, nil
+	d.weight, d.min, d.max = 0, math.Inf(1), math.Inf(-1)
+}
@@ -0,0 +1,165 @@
+package tdigest
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var quantiles = []float64{0, 0.001, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 0.999, 1}
+
+// rankError returns how far the exact rank of estimate is from q.
+func rankError(sorted []float64, estimate, q float64) float64 {
+	lo := sort.SearchFloat64s(sorted, estimate)
+	hi := sort.Search(len(sorted), func(i int) bool { return sorted[i] > estimate })
+	n := float64(len(sorted))
+	if q*n < float64(lo) {
+		return float64(lo)/n - q
+	} else if q*n > float64(hi) {
+		return q - float64(hi)/n
+	}
+	return 0
+}
+
+// tolerance is the allowed rank error at quantile q: tight at the tails, looser near the median.
+func tolerance(q float64) float64 {
+	return 0.0005 + 0.02*q*(1-q)
+}
+
+func TestNew(t *testing.T) {
+	for _, c := range []float64{0, 9, MaxCompression + 1, 1e18, math.NaN(), math.Inf(1)} {
+		_, err := New(c)
+		assert.True(t, errors.Is(err, ErrInvalidParameter))
+	}
+
+	d, err := New(DefaultCompression)
+	assert.NoError(t, err)
+	_, err = d.Quantile(0.5)
+	assert.True(t, errors.Is(err, ErrEmpty))
+	_, err = d.CDF(0)
+	assert.True(t, errors.Is(err, ErrEmpty))
+	_, err = d.Min()
+	assert.True(t, errors.Is(err, ErrEmpty))
+	assert.True(t, errors.Is(d.Add(math.NaN()), ErrInvalidParameter))
+	assert.True(t, errors.Is(d.AddWeighted(1, 0), ErrInvalidParameter))
+
+	assert.NoError(t, d.Add(3))
+	for _, q := range []float64{0, 0.5, 1} {
+		v, err := d.Quantile(q)
+		assert.NoError(t, err)
+		assert.Equal(t, 3.0, v)
+	}
+	_, err = d.Quantile(1.5)
+	assert.True(t, errors.Is(err, ErrInvalidParameter))
+}
+
+func TestQuantile(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	distributions := map[string]func() float64{
+		"uniform":     r.Float64,
+		"normal":      r.NormFloat64,
+		"exponential": r.ExpFloat64,
+		"discrete":    func() float64 { return float64(r.Intn(10)) },
+	}
+	for name, next := range distributions {
+		t.Run(name, func(t *testing.T) {
+			d, _ := New(DefaultCompression)
+			data := make([]float64, 100000)
+			for i := range data {
+				data[i] = next()
+				assert.NoError(t, d.Add(data[i]))
+			}
+			sort.Float64s(data)
+
+			assert.Equal(t, float64(len(data)), d.Count())
+			assert.Less(t, len(d.Centroids()), int(DefaultCompression))
+			min, _ := d.Min()
+			max, _ := d.Max()
+			assert.Equal(t, data[0], min)
+			assert.Equal(t, data[len(data)-1], max)
+
+			for _, q := range quantiles {
+				v, err := d.Quantile(q)
+				assert.NoError(t, err)
+				assert.LessOrEqual(t, rankError(data, v, q), tolerance(q), "q=%v", q)
+
+				exact := data[int(q*float64(len(data)-1))]
+				cdf, err := d.CDF(exact)
+				assert.NoError(t, err)
+				lo := float64(sort.SearchFloat64s(data, exact)) / float64(len(data))
+				hi := float64(sort.Search(len(data), func(i int) bool { return data[i] > exact })) / float64(len(data))
+				assert.True(t, cdf >= lo-tolerance(q) && cdf <= hi+tolerance(q), "x=%v cdf=%v in [%v, %v]", exact, cdf, lo, hi)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	var data []float64
+	merged, _ := New(DefaultCompression)
+	for part := 0; part < 10; part++ {
+		d, _ := New(DefaultCompression)
+		for i := 0; i < 10000; i++ {
+			x := r.NormFloat64() + float64(part)
+			data = append(data, x)
+			assert.NoError(t, d.Add(x))
+		}
+		merged.Merge(d)
+	}
+	sort.Float64s(data)
+	assert.Equal(t, float64(len(data)), merged.Count())
+	for _, q := range quantiles {
+		v, _ := merged.Quantile(q)
+		assert.LessOrEqual(t, rankError(data, v, q), 2*tolerance(q), "q=%v", q)
+	}
+
+	merged.Clear()
+	assert.Equal(t, 0.0, merged.Count())
+	_, err := merged.Max()
+	assert.True(t, errors.Is(err, ErrEmpty))
+}
+
+func TestMarshalBinary(t *testing.T) {
+	d, _ := New(50)
+	for i := 0; i < 10000; i++ {
+		assert.NoError(t, d.AddWeighted(float64(i%1000), float64(1+i%3)))
+	}
+	data, err := d.MarshalBinary()
+	assert.NoError(t, err)
+
+	decoded, _ := New(DefaultCompression)
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, d.Compression(), decoded.Compression())
+	assert.Equal(t, d.Centroids(), decoded.Centroids())
+	assert.InDelta(t, d.Count(), decoded.Count(), 1e-9)
+	for _, q := range quantiles {
+		want, _ := d.Quantile(q)
+		got, _ := decoded.Quantile(q)
+		assert.InDelta(t, want, got, 1e-9)
+	}
+
+	empty, _ := New(DefaultCompression)
+	data2, _ := empty.MarshalBinary()
+	assert.NoError(t, decoded.UnmarshalBinary(data2))
+	assert.Equal(t, 0.0, decoded.Count())
+
+	huge := append([]byte(nil), data2...)
+	copy(huge[4:], appendFloat64(nil, 1e18))
+	for _, bad := range [][]byte{nil, data[:len(data)-1], append(data, 0), append([]byte{0}, data[1:]...), huge} {
+		assert.True(t, errors.Is(decoded.UnmarshalBinary(bad), ErrInvalidFormat))
+	}
+
+	// The largest compression works with few points.
+	big, err := New(MaxCompression)
+	assert.NoError(t, err)
+	assert.NoError(t, big.Add(1))
+	assert.NoError(t, big.Add(2))
+	median, err := big.Quantile(0.5)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, median)
+}
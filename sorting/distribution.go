@@ -0,0 +1,104 @@
+package sorting
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kwstars/goads/pkg/common"
+)
+
+var ErrKeyRangeTooLarge = errors.New("key range too large")
+
+// maxCountingRange bounds the number of counters CountingSort allocates.
+const maxCountingRange = 1 << 24
+
+// CountingSort sorts s stably by the integer key of each element, counting how many elements have
+// each key between the smallest and the largest. It takes O(n + k) time and space, where k is the
+// size of the key range, and returns ErrKeyRangeTooLarge if k exceeds 2²⁴; use RadixSort then.
+func CountingSort[T any, K common.Integer](s []T, key func(T) K) error {
+	if len(s) < 2 {
+		return nil
+	}
+	keys := make([]K, len(s))
+	lo, hi := key(s[0]), key(s[0])
+	for i, v := range s {
+		k := key(v)
+		keys[i] = k
+		if k < lo {
+			lo = k
+		}
+		if k > hi {
+			hi = k
+		}
+	}
+	// Subtracting in uint64 is exact for every integer type: the wrapped difference is the distance.
+	if span := uint64(hi) - uint64(lo); span >= maxCountingRange {
+		return fmt.Errorf("%w: %v to %v", ErrKeyRangeTooLarge, lo, hi)
+	}
+
+	// counts[k+1] is the number of elements with key lo+k; prefix sums turn it into start positions.
+	counts := make([]int, uint64(hi)-uint64(lo)+2)
+	for _, k := range keys {
+		counts[uint64(k)-uint64(lo)+1]++
+	}
+	for i := 1; i < len(counts); i++ {
+		counts[i] += counts[i-1]
+	}
+	sorted := make([]T, len(s))
+	for i, v := range s {
+		c := uint64(keys[i]) - uint64(lo)
+		sorted[counts[c]] = v
+		counts[c]++
+	}
+	copy(s, sorted)
+	return nil
+}
+
+// RadixSort sorts s stably by the integer key of each element with a least-significant-digit
+// radix sort on bytes. It takes O(n·w) time, where w is the number of bytes in which the keys
+// differ, and O(n) extra space. Signed keys are ordered correctly.
+func RadixSort[T any, K common.Integer](s []T, key func(T) K) {
+	if len(s) < 2 {
+		return
+	}
+	// Map keys to uint64 preserving order: sign extension keeps negative numbers in two's
+	// complement, and flipping the sign bit moves them below the non-negative ones.
+	var zero K
+	var flip uint64
+	if zero-1 < zero {
+		flip = 1 << 63
+	}
+	keys := make([]uint64, len(s))
+	for i, v := range s {
+		keys[i] = uint64(key(v)) ^ flip
+	}
+
+	src, dst := s, make([]T, len(s))
+	srcKeys, dstKeys := keys, make([]uint64, len(s))
+	for shift := uint(0); shift < 64; shift += 8 {
+		var counts [256]int
+		for _, k := range srcKeys {
+			counts[byte(k>>shift)]++
+		}
+		// Skip bytes that are the same in every key, such as the high bytes of small values.
+		if counts[byte(srcKeys[0]>>shift)] == len(s) {
+			continue
+		}
+		pos := 0
+		for b, c := range counts {
+			counts[b] = pos
+			pos += c
+		}
+		for i, k := range srcKeys {
+			b := byte(k >> shift)
+			dst[counts[b]] = src[i]
+			dstKeys[counts[b]] = k
+			counts[b]++
+		}
+		src, dst = dst, src
+		srcKeys, dstKeys = dstKeys, srcKeys
+	}
+	if &src[0] != &s[0] {
+		copy(s, src)
+	}
+}
@@ -0,0 +1,124 @@
+package externalsort
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrCorruptRecord = errors.New("corrupt record")
+
+// Codec writes records to and reads them back from a byte stream.
+// It is used for the run files, and by NewStreamReader and NewStreamWriter for the input and output.
+type Codec[T any] interface {
+	// Encode writes one record.
+	Encode(w *bufio.Writer, v T) error
+	// Decode reads one record. It returns io.EOF if the stream ends before the record starts,
+	// and another error if it ends in the middle.
+	Decode(r *bufio.Reader) (T, error)
+}
+
+// Reader is a source of records. Read returns io.EOF after the last record.
+type Reader[T any] interface {
+	Read() (T, error)
+}
+
+// Writer is a sink of records.
+type Writer[T any] interface {
+	Write(v T) error
+}
+
+// StreamReader decodes records from an io.Reader.
+type StreamReader[T any] struct {
+	r     *bufio.Reader
+	codec Codec[T]
+}
+
+// NewStreamReader returns a Reader decoding records from r with codec.
+func NewStreamReader[T any](r io.Reader, codec Codec[T]) *StreamReader[T] {
+	return &StreamReader[T]{r: bufio.NewReader(r), codec: codec}
+}
+
+// Read returns the next record.
+func (s *StreamReader[T]) Read() (T, error) {
+	return s.codec.Decode(s.r)
+}
+
+// StreamWriter encodes records to an io.Writer through a buffer.
+type StreamWriter[T any] struct {
+	w     *bufio.Writer
+	codec Codec[T]
+}
+
+// NewStreamWriter returns a Writer encoding records to w with codec. Call Flush when done.
+func NewStreamWriter[T any](w io.Writer, codec Codec[T]) *StreamWriter[T] {
+	return &StreamWriter[T]{w: bufio.NewWriter(w), codec: codec}
+}
+
+// Write encodes a record.
+func (s *StreamWriter[T]) Write(v T) error {
+	return s.codec.Encode(s.w, v)
+}
+
+// Flush writes buffered data to the underlying writer.
+func (s *StreamWriter[T]) Flush() error {
+	return s.w.Flush()
+}
+
+// LineCodec encodes strings as newline-terminated lines. Strings must not contain '\n'.
+// When decoding, a final line without terminator is accepted and a trailing "\r" is kept.
+type LineCodec struct{}
+
+func (LineCodec) Encode(w *bufio.Writer, v string) error {
+	if _, err := w.WriteString(v); err != nil {
+		return err
+	}
+	return w.WriteByte('\n')
+}
+
+func (LineCodec) Decode(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err == io.EOF && line != "" {
+		return line, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return line[:len(line)-1], nil
+}
+
+// maxRecordSize bounds the length BytesCodec accepts, so a corrupt prefix cannot exhaust memory.
+const maxRecordSize = 1 << 31
+
+// BytesCodec encodes byte slices prefixed with their length as an unsigned varint.
+type BytesCodec struct{}
+
+func (BytesCodec) Encode(w *bufio.Writer, v []byte) error {
+	var prefix [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(prefix[:], uint64(len(v)))
+	if _, err := w.Write(prefix[:n]); err != nil {
+		return err
+	}
+	_, err := w.Write(v)
+	return err
+}
+
+func (BytesCodec) Decode(r *bufio.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("%w: %v", ErrCorruptRecord, err)
+	}
+	if n > maxRecordSize {
+		return nil, fmt.Errorf("%w: length %d", ErrCorruptRecord, n)
+	}
+	v := make([]byte, n)
+	if _, err := io.ReadFull(r, v); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptRecord, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,184 @@
+// Package externalsort implements an external merge sort for data sets larger than memory.
+//
+// The input is read in chunks that fit the memory budget; each chunk is sorted and spilled to a
+// temporary file as a run. The runs are then merged with a heap, k at a time, into the output.
+// Records are written to the run files with a pluggable Codec.
+//
+// Runs are created either by sorting a full buffer (the default, which keeps the sort stable)
+// or by replacement selection, which streams records through a heap and produces runs about twice
+// as long as the buffer on random input, and a single run on input that is already nearly sorted.
+//
+// Temporary files are removed when Sort returns, whether it succeeds or fails.
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/External_sorting
+package externalsort
+
+import (
+	"errors"
+	"io"
+	"os"
+	"unsafe"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/sorting"
+)
+
+const (
+	defaultMemoryLimit = 64 << 20
+	defaultFanIn       = 64
+)
+
+// Option is a function that can be passed to New to customize the Sorter.
+type Option[T any] func(*Sorter[T])
+
+// WithMemoryLimit sets the approximate number of bytes of records held in memory, as measured by
+// the size function. Runs are spilled when the limit is reached. Defaults to 64 MiB.
+func WithMemoryLimit[T any](bytes int64) Option[T] {
+	return func(s *Sorter[T]) {
+		if bytes > 0 {
+			s.memoryLimit = bytes
+		}
+	}
+}
+
+// WithSizeFunc sets the function estimating the memory a record occupies. The default is the size
+// of T itself, which ignores memory referenced by strings, slices and pointers; set it for such records.
+func WithSizeFunc[T any](size func(T) int) Option[T] {
+	return func(s *Sorter[T]) {
+		s.size = size
+	}
+}
+
+// WithTempDir sets the directory for run files. Defaults to os.TempDir.
+func WithTempDir[T any](dir string) Option[T] {
+	return func(s *Sorter[T]) {
+		s.tempDir = dir
+	}
+}
+
+// WithFanIn sets the maximum number of runs merged at once, which bounds the number of open files.
+// If there are more runs, they are merged in several passes. Values below 2 are ignored. Defaults to 64.
+func WithFanIn[T any](k int) Option[T] {
+	return func(s *Sorter[T]) {
+		if k >= 2 {
+			s.fanIn = k
+		}
+	}
+}
+
+// WithReplacementSelection creates runs with replacement selection instead of sorting full buffers.
+// Runs get longer, so fewer of them need merging, but the sort is no longer stable.
+func WithReplacementSelection[T any]() Option[T] {
+	return func(s *Sorter[T]) {
+		s.replacementSelection = true
+	}
+}
+
+// Sorter sorts record streams that may not fit in memory.
+type Sorter[T any] struct {
+	cmp   common.Comparator[T, T]
+	codec Codec[T]
+
+	memoryLimit          int64
+	size                 func(T) int
+	tempDir              string
+	fanIn                int
+	replacementSelection bool
+
+	runs []string // paths of the run files not merged yet
+}
+
+// New returns a Sorter ordering records with cmp and spilling them with codec.
+// cmp should return a negative number if a < b, zero if a == b, and a positive number if a > b.
+func New[T any](cmp common.Comparator[T, T], codec Codec[T], opts ...Option[T]) *Sorter[T] {
+	s := &Sorter[T]{
+		cmp:         cmp,
+		codec:       codec,
+		memoryLimit: defaultMemoryLimit,
+		fanIn:       defaultFanIn,
+	}
+	for _, option := range opts {
+		option(s)
+	}
+	if s.size == nil {
+		var zero T
+		recordSize := int(unsafe.Sizeof(zero))
+		s.size = func(T) int { return recordSize }
+	}
+	return s
+}
+
+// Sort reads all records from in and writes them to out in sorted order.
+// If the input fits within the memory limit it is sorted without touching the disk.
+// Temporary files are removed before Sort returns, also on error.
+func (s *Sorter[T]) Sort(in Reader[T], out Writer[T]) (err error) {
+	s.runs = nil
+	defer func() {
+		for _, path := range s.runs {
+			if rmErr := os.Remove(path); rmErr != nil && err == nil && !errors.Is(rmErr, os.ErrNotExist) {
+				err = rmErr
+			}
+		}
+		s.runs = nil
+	}()
+
+	var rest []T
+	if s.replacementSelection {
+		rest, err = s.replacementRuns(in)
+	} else {
+		rest, err = s.bufferRuns(in)
+	}
+	if err != nil {
+		return err
+	}
+	if len(s.runs) == 0 {
+		// Everything fit in memory.
+		for _, v := range rest {
+			if err := out.Write(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(rest) > 0 {
+		if err := s.spill(rest); err != nil {
+			return err
+		}
+	}
+
+	// Merge in passes until one pass can write directly to the output.
+	for len(s.runs) > s.fanIn {
+		if err := s.mergePass(); err != nil {
+			return err
+		}
+	}
+	return s.merge(s.runs, out)
+}
+
+// bufferRuns fills a buffer up to the memory limit, sorts it and spills it, until the input ends.
+// It returns the last buffer, sorted but not spilled.
+func (s *Sorter[T]) bufferRuns(in Reader[T]) ([]T, error) {
+	var buf []T
+	var used int64
+	for {
+		v, err := in.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, v)
+		used += int64(s.size(v))
+		if used >= s.memoryLimit {
+			sorting.TimSort(buf, s.cmp)
+			if err := s.spill(buf); err != nil {
+				return nil, err
+			}
+			buf, used = buf[:0], 0
+		}
+	}
+	sorting.TimSort(buf, s.cmp)
+	return buf, nil
+}
@@ -0,0 +1,261 @@
+package externalsort
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// record is a sort key with its input position, to check stability.
+type record struct {
+	key int64
+	pos int64
+}
+
+func byKey(a, b record) int8 {
+	if a.key < b.key {
+		return -1
+	} else if a.key > b.key {
+		return 1
+	}
+	return 0
+}
+
+type recordCodec struct{}
+
+func (recordCodec) Encode(w *bufio.Writer, v record) error {
+	var buf [2 * binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], v.key)
+	n += binary.PutVarint(buf[n:], v.pos)
+	_, err := w.Write(buf[:n])
+	return err
+}
+
+func (recordCodec) Decode(r *bufio.Reader) (record, error) {
+	key, err := binary.ReadVarint(r)
+	if err != nil {
+		return record{}, err
+	}
+	pos, err := binary.ReadVarint(r)
+	if err != nil {
+		return record{}, io.ErrUnexpectedEOF
+	}
+	return record{key: key, pos: pos}, nil
+}
+
+type sliceReader[T any] struct {
+	values []T
+	err    error // returned instead of io.EOF at the end, if set
+}
+
+func (r *sliceReader[T]) Read() (T, error) {
+	if len(r.values) == 0 {
+		var zero T
+		if r.err != nil {
+			return zero, r.err
+		}
+		return zero, io.EOF
+	}
+	v := r.values[0]
+	r.values = r.values[1:]
+	return v, nil
+}
+
+type sliceWriter[T any] struct {
+	values []T
+	limit  int // fail after this many writes if positive
+}
+
+var errFull = errors.New("writer full")
+
+func (w *sliceWriter[T]) Write(v T) error {
+	if w.limit > 0 && len(w.values) == w.limit {
+		return errFull
+	}
+	w.values = append(w.values, v)
+	return nil
+}
+
+func randomRecords(r *rand.Rand, n int, keys int64) []record {
+	records := make([]record, n)
+	for i := range records {
+		records[i] = record{key: r.Int63n(keys), pos: int64(i)}
+	}
+	return records
+}
+
+func assertSortedStable(t *testing.T, input, output []record, stable bool) {
+	want := append([]record(nil), input...)
+	sort.SliceStable(want, func(i, j int) bool { return want[i].key < want[j].key })
+	if stable {
+		assert.Equal(t, want, output)
+		return
+	}
+	assert.Len(t, output, len(want))
+	for i := range output {
+		assert.Equal(t, want[i].key, output[i].key)
+	}
+	assert.ElementsMatch(t, want, output)
+}
+
+func assertNoRunFiles(t *testing.T, dir string) {
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestSort(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const recordSize = 16
+	tests := []struct {
+		name   string
+		n      int
+		opts   []Option[record]
+		stable bool
+	}{
+		{"empty", 0, nil, true},
+		{"in memory", 1000, nil, true},
+		{"spilled", 5000, []Option[record]{WithMemoryLimit[record](300 * recordSize)}, true},
+		{"multi-pass", 5000, []Option[record]{WithMemoryLimit[record](100 * recordSize), WithFanIn[record](3)}, true},
+		{"replacement selection", 5000, []Option[record]{WithMemoryLimit[record](300 * recordSize), WithReplacementSelection[record]()}, false},
+		{"replacement in memory", 100, []Option[record]{WithReplacementSelection[record]()}, false},
+		{"replacement multi-pass", 5000, []Option[record]{
+			WithMemoryLimit[record](50 * recordSize), WithFanIn[record](4), WithReplacementSelection[record](),
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			opts := append([]Option[record]{WithTempDir[record](dir)}, tt.opts...)
+			s := New(byKey, Codec[record](recordCodec{}), opts...)
+
+			input := randomRecords(r, tt.n, 100)
+			out := &sliceWriter[record]{}
+			assert.NoError(t, s.Sort(&sliceReader[record]{values: append([]record(nil), input...)}, out))
+			assertSortedStable(t, input, out.values, tt.stable)
+			assertNoRunFiles(t, dir)
+		})
+	}
+}
+
+func TestSort_ReplacementSelectionRunLength(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	dir := t.TempDir()
+	const n, memory = 10000, 500
+
+	countRuns := func(opts ...Option[record]) int {
+		s := New(byKey, Codec[record](recordCodec{}), append(opts, WithTempDir[record](dir), WithMemoryLimit[record](memory*16))...)
+		input := randomRecords(r, n, 1<<40)
+		runs, err := s.replacementRuns(&sliceReader[record]{values: input})
+		assert.Nil(t, runs)
+		assert.NoError(t, err)
+		count := len(s.runs)
+		for _, path := range s.runs {
+			assert.NoError(t, os.Remove(path))
+		}
+		s.runs = nil
+		return count
+	}
+	// Runs average twice the memory on random input.
+	assert.InDelta(t, n/(2*memory), countRuns(), 2)
+
+	// Sorted input yields a single run.
+	s := New(byKey, Codec[record](recordCodec{}), WithTempDir[record](dir), WithMemoryLimit[record](memory*16))
+	sorted := make([]record, n)
+	for i := range sorted {
+		sorted[i] = record{key: int64(i)}
+	}
+	_, err := s.replacementRuns(&sliceReader[record]{values: sorted})
+	assert.NoError(t, err)
+	assert.Len(t, s.runs, 1)
+	assert.NoError(t, os.Remove(s.runs[0]))
+}
+
+func TestSort_CleanupOnError(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	errRead := errors.New("read failed")
+	for _, replacement := range []bool{false, true} {
+		dir := t.TempDir()
+		opts := []Option[record]{WithTempDir[record](dir), WithMemoryLimit[record](100 * 16), WithFanIn[record](2)}
+		if replacement {
+			opts = append(opts, WithReplacementSelection[record]())
+		}
+		s := New(byKey, Codec[record](recordCodec{}), opts...)
+
+		err := s.Sort(&sliceReader[record]{values: randomRecords(r, 1000, 100), err: errRead}, &sliceWriter[record]{})
+		assert.True(t, errors.Is(err, errRead))
+		assertNoRunFiles(t, dir)
+
+		err = s.Sort(&sliceReader[record]{values: randomRecords(r, 1000, 100)}, &sliceWriter[record]{limit: 10})
+		assert.True(t, errors.Is(err, errFull))
+		assertNoRunFiles(t, dir)
+	}
+
+	s := New(byKey, Codec[record](recordCodec{}), WithTempDir[record]("/nonexistent/dir"), WithMemoryLimit[record](16))
+	err := s.Sort(&sliceReader[record]{values: randomRecords(r, 10, 100)}, &sliceWriter[record]{})
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestSort_Lines(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	var lines []string
+	var input strings.Builder
+	for i := 0; i < 2000; i++ {
+		line := strings.Repeat(string(rune('a'+r.Intn(26))), 1+r.Intn(10))
+		lines = append(lines, line)
+		input.WriteString(line + "\n")
+	}
+
+	dir := t.TempDir()
+	s := New(func(a, b string) int8 { return int8(strings.Compare(a, b)) }, Codec[string](LineCodec{}),
+		WithTempDir[string](dir), WithMemoryLimit[string](1000), WithSizeFunc(func(s string) int { return len(s) }))
+
+	var output bytes.Buffer
+	w := NewStreamWriter[string](&output, LineCodec{})
+	assert.NoError(t, s.Sort(NewStreamReader[string](strings.NewReader(input.String()), LineCodec{}), w))
+	assert.NoError(t, w.Flush())
+
+	sort.Strings(lines)
+	assert.Equal(t, strings.Join(lines, "\n")+"\n", output.String())
+	assertNoRunFiles(t, dir)
+}
+
+func TestCodecs(t *testing.T) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	for _, v := range [][]byte{[]byte("hello"), {}, bytes.Repeat([]byte{0}, 300)} {
+		assert.NoError(t, BytesCodec{}.Encode(w, v))
+	}
+	assert.NoError(t, w.Flush())
+	r := bufio.NewReader(bytes.NewReader(buf.Bytes()))
+	v, err := BytesCodec{}.Decode(r)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello"), v)
+	v, _ = BytesCodec{}.Decode(r)
+	assert.Empty(t, v)
+	v, _ = BytesCodec{}.Decode(r)
+	assert.Len(t, v, 300)
+	_, err = BytesCodec{}.Decode(r)
+	assert.Equal(t, io.EOF, err)
+
+	_, err = BytesCodec{}.Decode(bufio.NewReader(bytes.NewReader([]byte{5, 'a'})))
+	assert.True(t, errors.Is(err, ErrCorruptRecord))
+
+	lr := bufio.NewReader(strings.NewReader("a\nb"))
+	line, _ := LineCodec{}.Decode(lr)
+	assert.Equal(t, "a", line)
+	line, err = LineCodec{}.Decode(lr)
+	assert.NoError(t, err)
+	assert.Equal(t, "b", line)
+	_, err = LineCodec{}.Decode(lr)
+	assert.Equal(t, io.EOF, err)
+}
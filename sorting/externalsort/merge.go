@@ -0,0 +1,99 @@
+package externalsort
+
+import (
+	"io"
+	"os"
+
+	"github.com/kwstars/goads/trees/binaryheap"
+)
+
+// head is the next record of a run during a merge.
+type head[T any] struct {
+	value T
+	run   int // index of the run, which breaks ties to keep the merge stable
+}
+
+// mergePass merges the runs fanIn at a time into new runs.
+func (s *Sorter[T]) mergePass() error {
+	pending := s.runs
+	s.runs = nil
+	// Merged runs are removed right away; until then they stay registered for cleanup.
+	defer func() { s.runs = append(s.runs, pending...) }()
+
+	for len(pending) > 0 {
+		k := s.fanIn
+		if k > len(pending) {
+			k = len(pending)
+		}
+		w, err := s.createRun()
+		if err != nil {
+			return err
+		}
+		if err := s.merge(pending[:k], w); err != nil {
+			_ = w.close()
+			return err
+		}
+		if err := w.close(); err != nil {
+			return err
+		}
+		for _, path := range pending[:k] {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+		pending = pending[k:]
+	}
+	return nil
+}
+
+// merge merges the sorted run files into out with a min heap holding the next record of each run.
+func (s *Sorter[T]) merge(paths []string, out Writer[T]) (err error) {
+	readers := make([]*StreamReader[T], len(paths))
+	for i, path := range paths {
+		f, r, err := s.openRun(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		readers[i] = r
+	}
+
+	heap := binaryheap.New(func(a, b head[T]) int8 {
+		if c := s.cmp(a.value, b.value); c != 0 {
+			return smallestFirst(c)
+		}
+		if a.run < b.run {
+			return 1
+		} else if a.run > b.run {
+			return -1
+		}
+		return 0
+	}, binaryheap.WithInitialCapacity[head[T]](len(paths)))
+
+	for i, r := range readers {
+		v, err := r.Read()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		heap.Push(head[T]{value: v, run: i})
+	}
+
+	for !heap.Empty() {
+		h, _ := heap.Pop()
+		if err := out.Write(h.value); err != nil {
+			return err
+		}
+		v, err := readers[h.run].Read()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		heap.Push(head[T]{value: v, run: h.run})
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package externalsort
+
+import (
+	"bufio"
+	"io"
+	"os"
+
+	"github.com/kwstars/goads/trees/binaryheap"
+)
+
+// spill writes sorted records to a new run file.
+func (s *Sorter[T]) spill(records []T) error {
+	w, err := s.createRun()
+	if err != nil {
+		return err
+	}
+	for _, v := range records {
+		if err := w.Write(v); err != nil {
+			_ = w.close()
+			return err
+		}
+	}
+	return w.close()
+}
+
+// runWriter writes records to a run file.
+type runWriter[T any] struct {
+	file *os.File
+	*StreamWriter[T]
+}
+
+// createRun creates a new run file and registers it for removal.
+func (s *Sorter[T]) createRun() (*runWriter[T], error) {
+	f, err := os.CreateTemp(s.tempDir, "externalsort-*.run")
+	if err != nil {
+		return nil, err
+	}
+	s.runs = append(s.runs, f.Name())
+	return &runWriter[T]{file: f, StreamWriter: NewStreamWriter[T](f, s.codec)}, nil
+}
+
+func (w *runWriter[T]) close() error {
+	err := w.Flush()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// tagged is a record in the replacement selection heap, labelled with the run it belongs to.
+type tagged[T any] struct {
+	value T
+	run   int
+}
+
+// replacementRuns creates runs by replacement selection: a heap holds as many records as the memory
+// limit allows, and the smallest record is repeatedly written to the current run and replaced by the
+// next input record. An input record smaller than the last one written cannot join the current run,
+// so it is tagged for the next one. It returns the records of the last run if nothing was spilled.
+func (s *Sorter[T]) replacementRuns(in Reader[T]) ([]T, error) {
+	heap := binaryheap.New(func(a, b tagged[T]) int8 {
+		if a.run != b.run {
+			if a.run < b.run {
+				return 1
+			}
+			return -1
+		}
+		return smallestFirst(s.cmp(a.value, b.value))
+	})
+
+	var used int64
+	eof := false
+	for !eof && used < s.memoryLimit {
+		v, err := in.Read()
+		if err == io.EOF {
+			eof = true
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		heap.Push(tagged[T]{value: v})
+		used += int64(s.size(v))
+	}
+	if eof {
+		// Everything fits in memory: drain the heap in order.
+		records := make([]T, 0, heap.Size())
+		for !heap.Empty() {
+			t, _ := heap.Pop()
+			records = append(records, t.value)
+		}
+		return records, nil
+	}
+
+	var w *runWriter[T]
+	current := -1
+	for !heap.Empty() {
+		smallest, _ := heap.Pop()
+		if smallest.run != current {
+			if w != nil {
+				if err := w.close(); err != nil {
+					return nil, err
+				}
+			}
+			var err error
+			if w, err = s.createRun(); err != nil {
+				return nil, err
+			}
+			current = smallest.run
+		}
+		if err := w.Write(smallest.value); err != nil {
+			_ = w.close()
+			return nil, err
+		}
+		used -= int64(s.size(smallest.value))
+
+		// Refill up to the memory limit.
+		for !eof && used < s.memoryLimit {
+			v, err := in.Read()
+			if err == io.EOF {
+				eof = true
+				break
+			}
+			if err != nil {
+				_ = w.close()
+				return nil, err
+			}
+			run := current
+			if s.cmp(v, smallest.value) < 0 {
+				run++
+			}
+			heap.Push(tagged[T]{value: v, run: run})
+			used += int64(s.size(v))
+		}
+	}
+	return nil, w.close()
+}
+
+// smallestFirst turns the result of cmp(a, b) into a binaryheap comparison that puts the smaller record first.
+func smallestFirst(c int8) int8 {
+	switch {
+	case c < 0:
+		return 1
+	case c > 0:
+		return -1
+	}
+	return 0
+}
+
+// openRun opens a run file for reading.
+func (s *Sorter[T]) openRun(path string) (*os.File, *StreamReader[T], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, &StreamReader[T]{r: bufio.NewReader(f), codec: s.codec}, nil
+}
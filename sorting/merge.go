@@ -0,0 +1,47 @@
+package sorting
+
+import "github.com/kwstars/goads/pkg/common"
+
+// MergeSort sorts s stably by sorting both halves and merging them.
+// It merges bottom-up, starting from runs sorted by insertion, with a single buffer of n/2 elements.
+func MergeSort[T any](s []T, cmp common.Comparator[T, T]) {
+	n := len(s)
+	const run = insertionThreshold
+	for lo := 0; lo < n; lo += run {
+		insertionSort(s, lo, minInt(lo+run, n), cmp)
+	}
+	buf := make([]T, 0, (n+1)/2)
+	for width := run; width < n; width *= 2 {
+		for lo := 0; lo+width < n; lo += 2 * width {
+			merge(s, lo, lo+width, minInt(lo+2*width, n), buf, cmp)
+		}
+	}
+}
+
+// merge merges the sorted ranges s[lo:mid] and s[mid:hi], taking from the left on ties.
+// Only the left range is copied to buf.
+func merge[T any](s []T, lo, mid, hi int, buf []T, cmp common.Comparator[T, T]) {
+	if cmp(s[mid], s[mid-1]) >= 0 {
+		return // already in order
+	}
+	left := append(buf[:0], s[lo:mid]...)
+	i, j, k := 0, mid, lo
+	for i < len(left) && j < hi {
+		if cmp(s[j], left[i]) < 0 {
+			s[k] = s[j]
+			j++
+		} else {
+			s[k] = left[i]
+			i++
+		}
+		k++
+	}
+	copy(s[k:], left[i:])
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
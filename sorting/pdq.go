@@ -0,0 +1,231 @@
+package sorting
+
+import (
+	"math/bits"
+
+	"github.com/kwstars/goads/pkg/common"
+)
+
+// sortedHint reports what pivot selection learned about the order of a range.
+type sortedHint int8
+
+const (
+	unknownHint sortedHint = iota
+	increasingHint
+	decreasingHint
+)
+
+// PDQSort sorts s with pattern-defeating quicksort: quicksort that recognizes sorted, reversed
+// and equal-heavy input and finishes those in linear time, shuffles elements to break up patterns
+// that cause unbalanced partitions, and falls back to heapsort after too many of them.
+//
+// References: https://arxiv.org/abs/2106.05123
+func PDQSort[T any](s []T, cmp common.Comparator[T, T]) {
+	pdqsort(s, 0, len(s), log2(len(s)), cmp)
+}
+
+// pdqsort sorts s[lo:hi]. limit is the number of unbalanced partitions allowed before heapsort takes over.
+func pdqsort[T any](s []T, lo, hi, limit int, cmp common.Comparator[T, T]) {
+	wasBalanced, wasPartitioned := true, true
+	for {
+		n := hi - lo
+		if n <= insertionThreshold {
+			insertionSort(s, lo, hi, cmp)
+			return
+		}
+		if limit == 0 {
+			heapSort(s, lo, hi, cmp)
+			return
+		}
+		if !wasBalanced {
+			breakPatterns(s, lo, hi)
+			limit--
+		}
+
+		pivot, hint := choosePivot(s, lo, hi, cmp)
+		if hint == decreasingHint {
+			reverse(s[lo:hi])
+			pivot = (hi - 1) - (pivot - lo)
+			hint = increasingHint
+		}
+		// The range looks sorted: try to finish it with a few insertions.
+		if wasBalanced && wasPartitioned && hint == increasingHint && partialInsertionSort(s, lo, hi, cmp) {
+			return
+		}
+
+		// The element before the range is a previous pivot, so no element is smaller than it.
+		// If the new pivot equals it, the range holds many equal elements: split them off.
+		if lo > 0 && cmp(s[lo-1], s[pivot]) >= 0 {
+			lo = partitionEqual(s, lo, hi, pivot, cmp)
+			continue
+		}
+
+		mid, alreadyPartitioned := partitionRight(s, lo, hi, pivot, cmp)
+		wasPartitioned = alreadyPartitioned
+		left, right := mid-lo, hi-mid
+		if left < right {
+			wasBalanced = left >= n/8
+			pdqsort(s, lo, mid, limit, cmp)
+			lo = mid + 1
+		} else {
+			wasBalanced = right >= n/8
+			pdqsort(s, mid+1, hi, limit, cmp)
+			hi = mid
+		}
+	}
+}
+
+// partitionRight partitions s[lo:hi] around s[pivot] into elements less than it, the pivot,
+// and elements not less than it. It returns the final pivot position and whether no swaps were needed.
+func partitionRight[T any](s []T, lo, hi, pivot int, cmp common.Comparator[T, T]) (int, bool) {
+	s[lo], s[pivot] = s[pivot], s[lo]
+	i, j := lo+1, hi-1
+	for i <= j && cmp(s[i], s[lo]) < 0 {
+		i++
+	}
+	for i <= j && cmp(s[j], s[lo]) >= 0 {
+		j--
+	}
+	if i > j {
+		s[j], s[lo] = s[lo], s[j]
+		return j, true
+	}
+	s[i], s[j] = s[j], s[i]
+	i++
+	j--
+	for {
+		for i <= j && cmp(s[i], s[lo]) < 0 {
+			i++
+		}
+		for i <= j && cmp(s[j], s[lo]) >= 0 {
+			j--
+		}
+		if i > j {
+			break
+		}
+		s[i], s[j] = s[j], s[i]
+		i++
+		j--
+	}
+	s[j], s[lo] = s[lo], s[j]
+	return j, false
+}
+
+// partitionEqual partitions s[lo:hi] into elements equal to s[pivot] followed by greater elements,
+// given that none is smaller. It returns the start of the greater elements.
+func partitionEqual[T any](s []T, lo, hi, pivot int, cmp common.Comparator[T, T]) int {
+	s[lo], s[pivot] = s[pivot], s[lo]
+	i, j := lo+1, hi-1
+	for {
+		for i <= j && cmp(s[lo], s[i]) >= 0 {
+			i++
+		}
+		for i <= j && cmp(s[lo], s[j]) < 0 {
+			j--
+		}
+		if i > j {
+			break
+		}
+		s[i], s[j] = s[j], s[i]
+		i++
+		j--
+	}
+	return i
+}
+
+// partialInsertionSort sorts s[lo:hi] if it needs only a few insertions, and returns false
+// (leaving it partially sorted) as soon as it takes more.
+func partialInsertionSort[T any](s []T, lo, hi int, cmp common.Comparator[T, T]) bool {
+	const (
+		maxSteps         = 5  // at most this many adjacent elements out of order
+		shortestShifting = 50 // don't shift anything in short ranges, it's cheaper to just sort them
+	)
+	i := lo + 1
+	for step := 0; step < maxSteps; step++ {
+		for i < hi && cmp(s[i], s[i-1]) >= 0 {
+			i++
+		}
+		if i == hi {
+			return true
+		}
+		if hi-lo < shortestShifting {
+			return false
+		}
+		s[i], s[i-1] = s[i-1], s[i]
+		// Shift the smaller element left and the larger one right.
+		for j := i - 1; j > lo && cmp(s[j], s[j-1]) < 0; j-- {
+			s[j], s[j-1] = s[j-1], s[j]
+		}
+		for j := i + 1; j < hi && cmp(s[j], s[j-1]) < 0; j++ {
+			s[j], s[j-1] = s[j-1], s[j]
+		}
+	}
+	return false
+}
+
+// breakPatterns swaps a few elements around the middle of s[lo:hi] with pseudo-random ones
+// to break patterns that make pivot selection fail repeatedly.
+func breakPatterns[T any](s []T, lo, hi int) {
+	n := hi - lo
+	if n < 8 {
+		return
+	}
+	random := uint64(n) // xorshift seeded with the length, so sorting is deterministic
+	mask := uint64(1)<<bits.Len(uint(n)) - 1
+	idx := lo + (n/4)*2 - 1
+	for i := 0; i < 3; i++ {
+		random ^= random << 13
+		random ^= random >> 7
+		random ^= random << 17
+		other := int(random & mask)
+		if other >= n {
+			other -= n
+		}
+		s[idx-1+i], s[lo+other] = s[lo+other], s[idx-1+i]
+	}
+}
+
+// choosePivot returns the index of a pivot for s[lo:hi]: the median of three elements, or for long
+// ranges the median of three medians of three (Tukey's ninther). The hint reports whether all the
+// sampled elements were in increasing or decreasing order.
+func choosePivot[T any](s []T, lo, hi int, cmp common.Comparator[T, T]) (int, sortedHint) {
+	const (
+		shortestNinther = 50
+		maxSwaps        = 4 * 3
+	)
+	n := hi - lo
+	swaps := 0
+	a, b, c := lo+n/4, lo+n/4*2, lo+n/4*3
+	if n >= 8 {
+		if n >= shortestNinther {
+			a = medianOfThree(s, a-1, a, a+1, &swaps, cmp)
+			b = medianOfThree(s, b-1, b, b+1, &swaps, cmp)
+			c = medianOfThree(s, c-1, c, c+1, &swaps, cmp)
+		}
+		b = medianOfThree(s, a, b, c, &swaps, cmp)
+	}
+	switch swaps {
+	case 0:
+		return b, increasingHint
+	case maxSwaps:
+		return b, decreasingHint
+	default:
+		return b, unknownHint
+	}
+}
+
+// medianOfThree returns the index of the median of s[a], s[b] and s[c], counting in swaps
+// how many of the three comparisons found a pair out of order.
+func medianOfThree[T any](s []T, a, b, c int, swaps *int, cmp common.Comparator[T, T]) int {
+	order := func(x, y int) (int, int) {
+		if cmp(s[y], s[x]) < 0 {
+			*swaps++
+			return y, x
+		}
+		return x, y
+	}
+	a, b = order(a, b)
+	b, c = order(b, c)
+	_, b = order(a, b)
+	return b
+}
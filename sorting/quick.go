@@ -0,0 +1,77 @@
+package sorting
+
+import "github.com/kwstars/goads/pkg/common"
+
+// QuickSort sorts s by partitioning around the median of three elements and sorting both sides.
+// It recurses into the smaller side only, so the stack depth is O(log n) even in the O(n²) worst case.
+func QuickSort[T any](s []T, cmp common.Comparator[T, T]) {
+	quickSort(s, 0, len(s), -1, cmp)
+}
+
+// IntroSort sorts s with quicksort, switching to heapsort when the recursion gets deeper than
+// 2·log₂(n), which bounds the worst case to O(n log n), and to insertion sort for short ranges.
+func IntroSort[T any](s []T, cmp common.Comparator[T, T]) {
+	quickSort(s, 0, len(s), 2*log2(len(s)), cmp)
+}
+
+// quickSort sorts s[lo:hi]. A negative depth means no limit; otherwise heapsort takes over when it reaches 0.
+func quickSort[T any](s []T, lo, hi, depth int, cmp common.Comparator[T, T]) {
+	for hi-lo > insertionThreshold {
+		if depth == 0 {
+			heapSort(s, lo, hi, cmp)
+			return
+		}
+		depth--
+		p := partition(s, lo, hi, cmp)
+		if p-lo < hi-p {
+			quickSort(s, lo, p, depth, cmp)
+			lo = p + 1
+		} else {
+			quickSort(s, p+1, hi, depth, cmp)
+			hi = p
+		}
+	}
+	insertionSort(s, lo, hi, cmp)
+}
+
+// Partition moves the median of the first, middle and last element of s into its final position p
+// and returns p, with no larger element before it and no smaller element after it.
+// It is the partition step of QuickSort, exposed for selection algorithms. s must not be empty.
+func Partition[T any](s []T, cmp common.Comparator[T, T]) int {
+	return partition(s, 0, len(s), cmp)
+}
+
+// partition moves the median of the first, middle and last element of s[lo:hi] into its final
+// position p and returns p, with no larger element before it and no smaller element after it.
+func partition[T any](s []T, lo, hi int, cmp common.Comparator[T, T]) int {
+	mid, last := lo+(hi-lo)/2, hi-1
+	if cmp(s[mid], s[lo]) < 0 {
+		s[mid], s[lo] = s[lo], s[mid]
+	}
+	if cmp(s[last], s[mid]) < 0 {
+		s[last], s[mid] = s[mid], s[last]
+		if cmp(s[mid], s[lo]) < 0 {
+			s[mid], s[lo] = s[lo], s[mid]
+		}
+	}
+	// s[lo] <= s[mid] <= s[last]; park the pivot at lo and scan from both ends (Hoare).
+	s[lo], s[mid] = s[mid], s[lo]
+	i, j := lo+1, last
+	for {
+		for i <= j && cmp(s[i], s[lo]) < 0 {
+			i++
+		}
+		for i <= j && cmp(s[lo], s[j]) < 0 {
+			j--
+		}
+		if i >= j {
+			break
+		}
+		// Swapping elements equal to the pivot keeps partitions balanced on repeated values.
+		s[i], s[j] = s[j], s[i]
+		i++
+		j--
+	}
+	s[lo], s[j] = s[j], s[lo]
+	return j
+}
@@ -0,0 +1,77 @@
+package selection
+
+import (
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/trees/binaryheap"
+)
+
+// RunningMedian maintains the median of a stream of numbers with two heaps: a max heap holding
+// the lower half and a min heap holding the upper half, which has at most one element fewer.
+// Add takes O(log n) and Median O(1).
+type RunningMedian[T common.Number] struct {
+	lower *binaryheap.BinaryHeap[T] // max heap
+	upper *binaryheap.BinaryHeap[T] // min heap
+}
+
+// NewRunningMedian returns an empty RunningMedian.
+func NewRunningMedian[T common.Number]() *RunningMedian[T] {
+	return &RunningMedian[T]{
+		lower: binaryheap.New(func(a, b T) int8 { return first(compare(b, a)) }),
+		upper: binaryheap.New(func(a, b T) int8 { return first(compare(a, b)) }),
+	}
+}
+
+// Add adds a number to the stream.
+func (m *RunningMedian[T]) Add(v T) {
+	if top, err := m.lower.Peek(); err != nil || v <= top {
+		m.lower.Push(v)
+	} else {
+		m.upper.Push(v)
+	}
+	// Rebalance so that the lower half has as many elements as the upper half, or one more.
+	if m.lower.Size() > m.upper.Size()+1 {
+		v, _ := m.lower.Pop()
+		m.upper.Push(v)
+	} else if m.upper.Size() > m.lower.Size() {
+		v, _ := m.upper.Pop()
+		m.lower.Push(v)
+	}
+}
+
+// Size returns the number of numbers added.
+func (m *RunningMedian[T]) Size() int {
+	return m.lower.Size() + m.upper.Size()
+}
+
+// Median returns the median: the middle number, or the mean of the two middle numbers if the count is even.
+// It returns ErrEmpty if no number has been added.
+func (m *RunningMedian[T]) Median() (float64, error) {
+	lo, hi, err := m.Middle()
+	if err != nil {
+		return 0, err
+	}
+	return (float64(lo) + float64(hi)) / 2, nil
+}
+
+// Middle returns the two middle numbers, which are the same number if the count is odd.
+// It returns ErrEmpty if no number has been added.
+func (m *RunningMedian[T]) Middle() (T, T, error) {
+	lo, err := m.lower.Peek()
+	if err != nil {
+		return lo, lo, ErrEmpty
+	}
+	if m.upper.Size() < m.lower.Size() {
+		return lo, lo, nil
+	}
+	hi, _ := m.upper.Peek()
+	return lo, hi, nil
+}
+
+func compare[T common.Number](a, b T) int8 {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+package selection
+
+import (
+	"github.com/kwstars/goads/containers"
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/trees/binaryheap"
+)
+
+var _ containers.Iterator[int] = (*MergeIterator[int])(nil)
+
+// head is the next element of a source iterator.
+type head[T any] struct {
+	value  T
+	source int
+}
+
+// MergeIterator lazily merges sorted iterators into one sorted sequence.
+type MergeIterator[T any] struct {
+	sources []containers.Iterator[T]
+	heap    *binaryheap.BinaryHeap[head[T]]
+}
+
+// Merge returns an iterator over the elements of the sorted iterators in sorted order.
+// It holds one element per source and each step costs O(log k) for k sources.
+// Equal elements are returned in the order of their sources, so the merge is stable.
+func Merge[T any](cmp common.Comparator[T, T], iterators ...containers.Iterator[T]) *MergeIterator[T] {
+	m := &MergeIterator[T]{
+		sources: iterators,
+		heap: binaryheap.New(func(a, b head[T]) int8 {
+			if c := cmp(a.value, b.value); c != 0 {
+				return first(c)
+			}
+			return first(int8(compareInts(a.source, b.source)))
+		}, binaryheap.WithInitialCapacity[head[T]](len(iterators))),
+	}
+	for i, it := range iterators {
+		m.advance(i, it)
+	}
+	return m
+}
+
+// advance queues the next element of a source, if any.
+func (m *MergeIterator[T]) advance(source int, it containers.Iterator[T]) {
+	if it.HasNext() {
+		m.heap.Push(head[T]{value: it.Next(), source: source})
+	}
+}
+
+// HasNext returns true if there are more elements.
+func (m *MergeIterator[T]) HasNext() bool {
+	return !m.heap.Empty()
+}
+
+// Next returns the next element. It panics if there are no more elements.
+func (m *MergeIterator[T]) Next() T {
+	h, err := m.heap.Pop()
+	if err != nil {
+		panic(err)
+	}
+	m.advance(h.source, m.sources[h.source])
+	return h.value
+}
+
+// MergeSlices merges sorted slices into a new sorted slice. It is stable.
+func MergeSlices[T any](cmp common.Comparator[T, T], slices ...[]T) []T {
+	total := 0
+	iterators := make([]containers.Iterator[T], len(slices))
+	for i, s := range slices {
+		total += len(s)
+		iterators[i] = containers.Slice[T](s).Iter()
+	}
+	merged := make([]T, 0, total)
+	for it := Merge(cmp, iterators...); it.HasNext(); {
+		merged = append(merged, it.Next())
+	}
+	return merged
+}
+
+func compareInts(a, b int) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
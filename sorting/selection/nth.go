@@ -0,0 +1,43 @@
+package selection
+
+import (
+	"fmt"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/sorting"
+)
+
+// NthElement rearranges s so that s[n] is the element that would be at index n if s were sorted,
+// no element before it is greater and no element after it is smaller. It uses quickselect with
+// median-of-three pivots, O(n) on average; if partitioning keeps going badly it sorts the remaining
+// range with heapsort, which bounds the worst case to O(n log n).
+// It returns ErrIndexOutOfRange if n is not a valid index of s.
+func NthElement[T any](s []T, n int, cmp common.Comparator[T, T]) error {
+	if n < 0 || n >= len(s) {
+		return fmt.Errorf("%w: %d", ErrIndexOutOfRange, n)
+	}
+	lo, hi := 0, len(s)
+	// Each good partition at least halves the range; allow twice as many as that needs.
+	budget := 0
+	for size := len(s); size > 0; size >>= 1 {
+		budget += 2
+	}
+	for hi-lo > 3 {
+		if budget == 0 {
+			sorting.HeapSort(s[lo:hi], cmp)
+			return nil
+		}
+		budget--
+		p := lo + sorting.Partition(s[lo:hi], cmp)
+		switch {
+		case n < p:
+			hi = p
+		case n > p:
+			lo = p + 1
+		default:
+			return nil
+		}
+	}
+	sorting.InsertionSort(s[lo:hi], cmp)
+	return nil
+}
@@ -0,0 +1,27 @@
+// Package selection implements heap-based utilities for taking elements in order out of
+// sorted sources and unsorted streams: lazy k-way merging, streaming top-K and bottom-K,
+// a running median, and quickselect.
+//
+// cmp should return a negative number if a < b, zero if a == b, and a positive number if a > b.
+// Structures are not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Selection_algorithm
+package selection
+
+import "errors"
+
+var (
+	ErrEmpty           = errors.New("no elements")
+	ErrIndexOutOfRange = errors.New("index out of range")
+)
+
+// first turns the result of cmp(a, b) into a binaryheap comparison that puts a first if it is smaller.
+func first(c int8) int8 {
+	switch {
+	case c < 0:
+		return 1
+	case c > 0:
+		return -1
+	}
+	return 0
+}
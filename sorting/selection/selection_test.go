@@ -0,0 +1,181 @@
+package selection
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/kwstars/goads/containers"
+	"github.com/stretchr/testify/assert"
+)
+
+func intCmp(a, b int) int8 {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+type tagged struct{ key, source int }
+
+func byKey(a, b tagged) int8 { return intCmp(a.key, b.key) }
+
+func TestMerge(t *testing.T) {
+	it := Merge(intCmp,
+		containers.Slice[int]{1, 4, 7}.Iter(),
+		containers.Slice[int]{}.Iter(),
+		containers.Slice[int]{2, 5, 8, 9}.Iter(),
+		containers.Slice[int]{3, 6}.Iter(),
+	)
+	var got []int
+	for it.HasNext() {
+		got = append(got, it.Next())
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}, got)
+	assert.Panics(t, func() { it.Next() })
+
+	assert.False(t, Merge[int](intCmp).HasNext())
+}
+
+func TestMergeSlices(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var slices [][]tagged
+	var all []tagged
+	for i := 0; i < 10; i++ {
+		s := make([]tagged, r.Intn(50))
+		for j := range s {
+			s[j] = tagged{key: r.Intn(20), source: i}
+		}
+		sort.SliceStable(s, func(a, b int) bool { return s[a].key < s[b].key })
+		slices = append(slices, s)
+		all = append(all, s...)
+	}
+	// A stable sort of the concatenation is what a stable merge must produce.
+	sort.SliceStable(all, func(a, b int) bool { return all[a].key < all[b].key })
+	assert.Equal(t, all, MergeSlices(byKey, slices...))
+	assert.Empty(t, MergeSlices(byKey))
+}
+
+func TestTopK(t *testing.T) {
+	top := NewTopK(3, intCmp)
+	_, err := top.Threshold()
+	assert.True(t, errors.Is(err, ErrEmpty))
+
+	for _, v := range []int{5, 1, 9, 3, 7} {
+		top.Push(v)
+	}
+	assert.Equal(t, []int{9, 7, 5}, top.Items())
+	assert.Equal(t, []int{9, 7, 5}, top.Items())
+	threshold, _ := top.Threshold()
+	assert.Equal(t, 5, threshold)
+	assert.False(t, top.Push(5))
+	assert.True(t, top.Push(6))
+	assert.Equal(t, []int{9, 7, 6}, top.Items())
+	assert.Equal(t, 3, top.Size())
+
+	bottom := NewBottomK(2, intCmp)
+	for _, v := range []int{5, 1, 9, 3, 7} {
+		bottom.Push(v)
+	}
+	assert.Equal(t, []int{1, 3}, bottom.Items())
+
+	none := NewTopK(0, intCmp)
+	assert.False(t, none.Push(1))
+	assert.Empty(t, none.Items())
+}
+
+func TestTopK_Ties(t *testing.T) {
+	type item struct{ key, id int }
+	byItemKey := func(a, b item) int8 { return intCmp(a.key, b.key) }
+
+	// Several kept elements tie at the threshold; a stronger one must evict the latest of them.
+	top := NewTopK(3, byItemKey)
+	for id, key := range []int{5, 1, 1, 1, 1, 9, 1} {
+		top.Push(item{key: key, id: id})
+	}
+	assert.Equal(t, []item{{9, 5}, {5, 0}, {1, 1}}, top.Items())
+
+	bottom := NewBottomK(4, byItemKey)
+	for id, key := range []int{2, 2, 2, 2, 2, 1, 0, 2} {
+		bottom.Push(item{key: key, id: id})
+	}
+	assert.Equal(t, []item{{0, 6}, {1, 5}, {2, 0}, {2, 1}}, bottom.Items())
+}
+
+func TestTopK_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	values := r.Perm(10000)
+	top, bottom := NewTopK(50, intCmp), NewBottomK(50, intCmp)
+	for _, v := range values {
+		top.Push(v)
+		bottom.Push(v)
+	}
+	sort.Ints(values)
+	want := append([]int(nil), values[len(values)-50:]...)
+	sort.Sort(sort.Reverse(sort.IntSlice(want)))
+	assert.Equal(t, want, top.Items())
+	assert.Equal(t, values[:50], bottom.Items())
+}
+
+func TestRunningMedian(t *testing.T) {
+	m := NewRunningMedian[int]()
+	_, err := m.Median()
+	assert.True(t, errors.Is(err, ErrEmpty))
+
+	r := rand.New(rand.NewSource(3))
+	var seen []int
+	for i := 0; i < 500; i++ {
+		v := r.Intn(100) - 50
+		m.Add(v)
+		seen = append(seen, v)
+
+		sorted := append([]int(nil), seen...)
+		sort.Ints(sorted)
+		n := len(sorted)
+		lo, hi, err := m.Middle()
+		assert.NoError(t, err)
+		assert.Equal(t, sorted[(n-1)/2], lo)
+		assert.Equal(t, sorted[n/2], hi)
+		median, _ := m.Median()
+		assert.Equal(t, float64(sorted[(n-1)/2]+sorted[n/2])/2, median)
+	}
+	assert.Equal(t, 500, m.Size())
+
+	f := NewRunningMedian[float64]()
+	f.Add(1.5)
+	f.Add(2)
+	median, _ := f.Median()
+	assert.Equal(t, 1.75, median)
+}
+
+func TestNthElement(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for _, n := range []int{1, 2, 3, 5, 10, 100, 1000} {
+		for _, limit := range []int{3, n + 1} {
+			values := make([]int, n)
+			for i := range values {
+				values[i] = r.Intn(limit)
+			}
+			sorted := append([]int(nil), values...)
+			sort.Ints(sorted)
+
+			k := r.Intn(n)
+			s := append([]int(nil), values...)
+			assert.NoError(t, NthElement(s, k, intCmp))
+			assert.Equal(t, sorted[k], s[k])
+			for i := 0; i < k; i++ {
+				assert.LessOrEqual(t, s[i], s[k])
+			}
+			for i := k + 1; i < n; i++ {
+				assert.GreaterOrEqual(t, s[i], s[k])
+			}
+			assert.ElementsMatch(t, values, s)
+		}
+	}
+
+	assert.True(t, errors.Is(NthElement([]int{1}, 1, intCmp), ErrIndexOutOfRange))
+	assert.True(t, errors.Is(NthElement([]int(nil), 0, intCmp), ErrIndexOutOfRange))
+}
@@ -0,0 +1,104 @@
+package selection
+
+import (
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/trees/binaryheap"
+)
+
+// TopK keeps the k largest (or smallest) elements seen in a stream, using O(k) memory.
+// The kept elements live in a heap whose root is the weakest of them, so each new element
+// is compared with the root and only replaces it if it is stronger, in O(log k).
+type TopK[T any] struct {
+	k      int
+	seq    uint64            // seq numbers the pushed elements in arrival order
+	better func(a, b T) bool // better(a, b) is true if a ranks before b
+	heap   *binaryheap.BinaryHeap[ranked[T]]
+}
+
+// ranked is a kept element with its arrival number. Among equal elements the later one is weaker.
+type ranked[T any] struct {
+	value T
+	seq   uint64
+}
+
+// NewTopK returns a TopK keeping the k largest elements according to cmp.
+func NewTopK[T any](k int, cmp common.Comparator[T, T]) *TopK[T] {
+	return newTopK(k, cmp)
+}
+
+// NewBottomK returns a TopK keeping the k smallest elements according to cmp.
+func NewBottomK[T any](k int, cmp common.Comparator[T, T]) *TopK[T] {
+	return newTopK(k, func(a, b T) int8 { return cmp(b, a) })
+}
+
+// newTopK keeps the k largest elements according to cmp.
+func newTopK[T any](k int, cmp common.Comparator[T, T]) *TopK[T] {
+	if k < 0 {
+		k = 0
+	}
+	return &TopK[T]{
+		k:      k,
+		better: func(a, b T) bool { return cmp(a, b) > 0 },
+		// A min heap: the smallest kept element is the first to go, the latest one among equals.
+		heap: binaryheap.New(func(a, b ranked[T]) int8 {
+			if c := cmp(a.value, b.value); c != 0 {
+				return first(c)
+			}
+			if a.seq > b.seq {
+				return 1
+			}
+			return -1
+		}, binaryheap.WithInitialCapacity[ranked[T]](k)),
+	}
+}
+
+// Push offers an element. It returns true if the element is kept, possibly evicting another.
+// Among equal elements the ones seen first are kept.
+func (t *TopK[T]) Push(v T) bool {
+	t.seq++
+	if t.heap.Size() < t.k {
+		t.heap.Push(ranked[T]{value: v, seq: t.seq})
+		return true
+	}
+	if t.k == 0 {
+		return false
+	}
+	weakest, _ := t.heap.Peek()
+	if !t.better(v, weakest.value) {
+		return false
+	}
+	_, _ = t.heap.Pop()
+	t.heap.Push(ranked[T]{value: v, seq: t.seq})
+	return true
+}
+
+// Threshold returns the weakest kept element: once k elements are kept, a new element is only
+// kept if it ranks strictly before it. It returns ErrEmpty if nothing is kept.
+func (t *TopK[T]) Threshold() (T, error) {
+	r, err := t.heap.Peek()
+	if err != nil {
+		return r.value, ErrEmpty
+	}
+	return r.value, nil
+}
+
+// Size returns the number of kept elements, at most k.
+func (t *TopK[T]) Size() int {
+	return t.heap.Size()
+}
+
+// Items returns the kept elements, best first: largest first for NewTopK, smallest first for NewBottomK.
+// Equal elements are in arrival order. It does not change the kept elements.
+func (t *TopK[T]) Items() []T {
+	kept := make([]ranked[T], t.heap.Size())
+	// Popping yields the weakest first, so fill from the back.
+	for i := len(kept) - 1; i >= 0; i-- {
+		kept[i], _ = t.heap.Pop()
+	}
+	items := make([]T, len(kept))
+	for i, r := range kept {
+		t.heap.Push(r)
+		items[i] = r.value
+	}
+	return items
+}
@@ -0,0 +1,44 @@
+package sorting
+
+import "github.com/kwstars/goads/pkg/common"
+
+// BubbleSort sorts s by repeatedly swapping adjacent elements that are out of order.
+// It stops as soon as a pass makes no swap, so it runs in O(n) on sorted input.
+func BubbleSort[T any](s []T, cmp common.Comparator[T, T]) {
+	for end := len(s); end > 1; {
+		// Everything after the last swap is already in its final place.
+		lastSwap := 0
+		for i := 1; i < end; i++ {
+			if cmp(s[i], s[i-1]) < 0 {
+				s[i], s[i-1] = s[i-1], s[i]
+				lastSwap = i
+			}
+		}
+		end = lastSwap
+	}
+}
+
+// SelectionSort sorts s by repeatedly moving the smallest remaining element to the front.
+// It makes at most n-1 swaps, which helps when moving elements is expensive.
+func SelectionSort[T any](s []T, cmp common.Comparator[T, T]) {
+	for i := 0; i < len(s)-1; i++ {
+		smallest := i
+		for j := i + 1; j < len(s); j++ {
+			if cmp(s[j], s[smallest]) < 0 {
+				smallest = j
+			}
+		}
+		s[i], s[smallest] = s[smallest], s[i]
+	}
+}
+
+// InsertionSort sorts s by inserting each element into the sorted prefix before it.
+// It runs in O(n + d) where d is the number of inversions, so it is fast on nearly sorted input.
+func InsertionSort[T any](s []T, cmp common.Comparator[T, T]) {
+	insertionSort(s, 0, len(s), cmp)
+}
+
+// HeapSort sorts s by building a max heap in place and repeatedly moving its root to the end.
+func HeapSort[T any](s []T, cmp common.Comparator[T, T]) {
+	heapSort(s, 0, len(s), cmp)
+}
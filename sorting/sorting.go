@@ -0,0 +1,90 @@
+// Package sorting implements comparison sorts generic over common.Comparator, and distribution sorts
+// for integer keys.
+//
+// Every comparison sort orders the slice in place so that cmp(s[i], s[i+1]) <= 0.
+// cmp should return a negative number if a < b, zero if a == b, and a positive number if a > b.
+//
+//	Algorithm       Time (average)  Time (worst)  Extra space  Stable
+//	BubbleSort      O(n²)           O(n²)         O(1)         yes
+//	SelectionSort   O(n²)           O(n²)         O(1)         no
+//	InsertionSort   O(n²)           O(n²)         O(1)         yes
+//	QuickSort       O(n log n)      O(n²)         O(log n)     no
+//	MergeSort       O(n log n)      O(n log n)    O(n)         yes
+//	HeapSort        O(n log n)      O(n log n)    O(1)         no
+//	IntroSort       O(n log n)      O(n log n)    O(log n)     no
+//	PDQSort         O(n log n)      O(n log n)    O(log n)     no
+//	TimSort         O(n log n)      O(n log n)    O(n)         yes
+//	CountingSort    O(n + k)        O(n + k)      O(n + k)     yes
+//	RadixSort       O(n·w)          O(n·w)        O(n)         yes
+//
+// References: https://en.wikipedia.org/wiki/Sorting_algorithm
+package sorting
+
+import "github.com/kwstars/goads/pkg/common"
+
+// insertionThreshold is the length below which the divide-and-conquer sorts switch to insertion sort.
+const insertionThreshold = 12
+
+// IsSorted returns true if s is sorted according to cmp.
+func IsSorted[T any](s []T, cmp common.Comparator[T, T]) bool {
+	for i := 1; i < len(s); i++ {
+		if cmp(s[i], s[i-1]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// insertionSort sorts s[lo:hi] by insertion.
+func insertionSort[T any](s []T, lo, hi int, cmp common.Comparator[T, T]) {
+	for i := lo + 1; i < hi; i++ {
+		for j := i; j > lo && cmp(s[j], s[j-1]) < 0; j-- {
+			s[j], s[j-1] = s[j-1], s[j]
+		}
+	}
+}
+
+// heapSort sorts s[lo:hi] with a max heap built in place.
+func heapSort[T any](s []T, lo, hi int, cmp common.Comparator[T, T]) {
+	n := hi - lo
+	for i := n/2 - 1; i >= 0; i-- {
+		siftDown(s, lo, i, n, cmp)
+	}
+	for end := n - 1; end > 0; end-- {
+		s[lo], s[lo+end] = s[lo+end], s[lo]
+		siftDown(s, lo, 0, end, cmp)
+	}
+}
+
+// siftDown restores the max heap property for the subtree rooted at root of the heap s[lo:lo+n].
+func siftDown[T any](s []T, lo, root, n int, cmp common.Comparator[T, T]) {
+	for {
+		child := 2*root + 1
+		if child >= n {
+			return
+		}
+		if child+1 < n && cmp(s[lo+child], s[lo+child+1]) < 0 {
+			child++
+		}
+		if cmp(s[lo+root], s[lo+child]) >= 0 {
+			return
+		}
+		s[lo+root], s[lo+child] = s[lo+child], s[lo+root]
+		root = child
+	}
+}
+
+func reverse[T any](s []T) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// log2 returns the number of bits needed to represent n.
+func log2(n int) int {
+	bits := 0
+	for ; n > 0; n >>= 1 {
+		bits++
+	}
+	return bits
+}
@@ -0,0 +1,266 @@
+package sorting
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// record is an element with a sort key and its original position, to check stability.
+type record struct {
+	key int
+	pos int
+}
+
+func byKey(a, b record) int8 {
+	if a.key < b.key {
+		return -1
+	} else if a.key > b.key {
+		return 1
+	}
+	return 0
+}
+
+func intCmp(a, b int) int8 {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+var algorithms = []struct {
+	name   string
+	sort   func([]record)
+	stable bool
+	slow   bool // quadratic: only run on short inputs
+}{
+	{"bubble", func(s []record) { BubbleSort(s, byKey) }, true, true},
+	{"selection", func(s []record) { SelectionSort(s, byKey) }, false, true},
+	{"insertion", func(s []record) { InsertionSort(s, byKey) }, true, true},
+	{"quick", func(s []record) { QuickSort(s, byKey) }, false, false},
+	{"merge", func(s []record) { MergeSort(s, byKey) }, true, false},
+	{"heap", func(s []record) { HeapSort(s, byKey) }, false, false},
+	{"intro", func(s []record) { IntroSort(s, byKey) }, false, false},
+	{"pdq", func(s []record) { PDQSort(s, byKey) }, false, false},
+	{"tim", func(s []record) { TimSort(s, byKey) }, true, false},
+	{"counting", func(s []record) {
+		if err := CountingSort(s, func(r record) int { return r.key }); err != nil {
+			panic(err)
+		}
+	}, true, false},
+	{"radix", func(s []record) { RadixSort(s, func(r record) int { return r.key }) }, true, false},
+}
+
+// generators produce keys with the patterns that trip up sorting algorithms.
+var generators = map[string]func(r *rand.Rand, n int) []int{
+	"random":    func(r *rand.Rand, n int) []int { return randomKeys(r, n, 1<<20) },
+	"few":       func(r *rand.Rand, n int) []int { return randomKeys(r, n, 4) },
+	"equal":     func(r *rand.Rand, n int) []int { return make([]int, n) },
+	"sorted":    func(r *rand.Rand, n int) []int { return ramp(n, func(i int) int { return i }) },
+	"reversed":  func(r *rand.Rand, n int) []int { return ramp(n, func(i int) int { return n - i }) },
+	"sawtooth":  func(r *rand.Rand, n int) []int { return ramp(n, func(i int) int { return i % 37 }) },
+	"organpipe": func(r *rand.Rand, n int) []int { return ramp(n, func(i int) int { return minInt(i, n-i) }) },
+	"negative":  func(r *rand.Rand, n int) []int { return ramp(n, func(i int) int { return r.Intn(1000) - 500 }) },
+	"nearly": func(r *rand.Rand, n int) []int {
+		keys := ramp(n, func(i int) int { return i })
+		for i := 0; i < n/20+1 && n > 1; i++ {
+			a, b := r.Intn(n), r.Intn(n)
+			keys[a], keys[b] = keys[b], keys[a]
+		}
+		return keys
+	},
+	"runs": func(r *rand.Rand, n int) []int {
+		keys := ramp(n, func(i int) int { return r.Intn(1 << 10) })
+		for lo := 0; lo < n; lo += 100 {
+			run := keys[lo:minInt(lo+100, n)]
+			sort.Ints(run)
+			if r.Intn(2) == 0 {
+				reverse(run)
+			}
+		}
+		return keys
+	},
+}
+
+func randomKeys(r *rand.Rand, n, limit int) []int {
+	return ramp(n, func(int) int { return r.Intn(limit) })
+}
+
+func ramp(n int, f func(i int) int) []int {
+	keys := make([]int, n)
+	for i := range keys {
+		keys[i] = f(i)
+	}
+	return keys
+}
+
+// TestProperties checks for every algorithm and input pattern that the output is sorted,
+// is a permutation of the input and, for stable algorithms, keeps equal keys in input order.
+func TestProperties(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, alg := range algorithms {
+		alg := alg
+		t.Run(alg.name, func(t *testing.T) {
+			sizes := []int{0, 1, 2, 3, 7, 12, 13, 31, 32, 33, 64, 100, 257, 1000, 5000}
+			if alg.slow {
+				sizes = sizes[:12]
+			}
+			for pattern, gen := range generators {
+				for _, n := range sizes {
+					keys := gen(r, n)
+					s := make([]record, n)
+					for i, k := range keys {
+						s[i] = record{key: k, pos: i}
+					}
+					alg.sort(s)
+					if !assertSorted(t, keys, s, alg.stable) {
+						t.Fatalf("%s failed on %s input of length %d", alg.name, pattern, n)
+					}
+				}
+			}
+		})
+	}
+}
+
+func assertSorted(t *testing.T, keys []int, s []record, stable bool) bool {
+	seen := make([]bool, len(keys))
+	for i, rec := range s {
+		if rec.pos < 0 || rec.pos >= len(keys) || seen[rec.pos] || keys[rec.pos] != rec.key {
+			return assert.Fail(t, "not a permutation of the input")
+		}
+		seen[rec.pos] = true
+		if i == 0 {
+			continue
+		}
+		if s[i-1].key > rec.key {
+			return assert.Fail(t, "not sorted", "index %d", i)
+		}
+		if stable && s[i-1].key == rec.key && s[i-1].pos > rec.pos {
+			return assert.Fail(t, "not stable", "index %d", i)
+		}
+	}
+	return true
+}
+
+func TestIsSorted(t *testing.T) {
+	assert.True(t, IsSorted(nil, intCmp))
+	assert.True(t, IsSorted([]int{1, 1, 2}, intCmp))
+	assert.False(t, IsSorted([]int{1, 3, 2}, intCmp))
+}
+
+func TestPartition(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for name, gen := range generators {
+		for _, n := range []int{1, 2, 3, 10, 100} {
+			s := gen(r, n)
+			p := Partition(s, intCmp)
+			for i := range s {
+				if i < p {
+					assert.LessOrEqual(t, s[i], s[p], "%s n=%d", name, n)
+				} else {
+					assert.GreaterOrEqual(t, s[i], s[p], "%s n=%d", name, n)
+				}
+			}
+		}
+	}
+}
+
+func TestTimSort_Galloping(t *testing.T) {
+	// Two long interleaved runs where one side wins long stretches exercise galloping in both directions.
+	for _, n := range []int{1000, 10000} {
+		s := make([]int, 0, 2*n)
+		for i := 0; i < n; i++ {
+			s = append(s, i/100*100)
+		}
+		for i := 0; i < n; i++ {
+			s = append(s, i/50*100+50)
+		}
+		TimSort(s, intCmp)
+		assert.True(t, IsSorted(s, intCmp))
+
+		reverse(s[n:])
+		TimSort(s, intCmp)
+		assert.True(t, IsSorted(s, intCmp))
+	}
+}
+
+func TestPDQSort_AdversarialFallsBack(t *testing.T) {
+	// Median-of-3 killer input for quicksort.
+	n := 1 << 14
+	s := make([]int, n)
+	for i := 0; i < n/2; i++ {
+		if i%2 == 0 {
+			s[i] = i + 1
+		} else {
+			s[i] = n/2 + i + (n/2)%2
+		}
+		s[n/2+i] = 2 * (i + 1)
+	}
+	comparisons := 0
+	counting := func(a, b int) int8 { comparisons++; return intCmp(a, b) }
+	PDQSort(s, counting)
+	assert.True(t, IsSorted(s, intCmp))
+	assert.Less(t, comparisons, 4*n*log2(n))
+}
+
+func TestCountingSort(t *testing.T) {
+	s := []int8{3, -128, 127, 0, -1}
+	assert.NoError(t, CountingSort(s, func(v int8) int8 { return v }))
+	assert.Equal(t, []int8{-128, -1, 0, 3, 127}, s)
+
+	wide := []int64{math.MinInt64, math.MaxInt64}
+	err := CountingSort(wide, func(v int64) int64 { return v })
+	assert.True(t, errors.Is(err, ErrKeyRangeTooLarge))
+}
+
+func TestRadixSort(t *testing.T) {
+	s := []int64{math.MaxInt64, -1, 0, math.MinInt64, 1 << 40, -(1 << 40)}
+	RadixSort(s, func(v int64) int64 { return v })
+	assert.Equal(t, []int64{math.MinInt64, -(1 << 40), -1, 0, 1 << 40, math.MaxInt64}, s)
+
+	u := []uint32{math.MaxUint32, 0, 1 << 31, 7}
+	RadixSort(u, func(v uint32) uint32 { return v })
+	assert.Equal(t, []uint32{0, 7, 1 << 31, math.MaxUint32}, u)
+
+	words := []string{"pear", "fig", "banana", "kiwi", "apple"}
+	RadixSort(words, func(w string) int { return len(w) })
+	assert.Equal(t, []string{"fig", "pear", "kiwi", "apple", "banana"}, words)
+}
+
+func BenchmarkSort(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{100, 10000} {
+		for _, alg := range algorithms {
+			if alg.slow && n > 1000 {
+				continue
+			}
+			keys := randomKeys(r, n, 1<<20)
+			s := make([]record, n)
+			b.Run(fmt.Sprintf("%s/%d", alg.name, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					for j, k := range keys {
+						s[j] = record{key: k, pos: j}
+					}
+					alg.sort(s)
+				}
+			})
+		}
+		b.Run(fmt.Sprintf("sort.SliceStable/%d", n), func(b *testing.B) {
+			keys := randomKeys(r, n, 1<<20)
+			s := make([]record, n)
+			for i := 0; i < b.N; i++ {
+				for j, k := range keys {
+					s[j] = record{key: k, pos: j}
+				}
+				sort.SliceStable(s, func(i, j int) bool { return s[i].key < s[j].key })
+			}
+		})
+	}
+}
@@ -0,0 +1,346 @@
+package sorting
+
+import "github.com/kwstars/goads/pkg/common"
+
+const (
+	// minMerge is the length below which TimSort only uses binary insertion sort.
+	minMerge = 32
+	// initialMinGallop is the number of consecutive wins of one run after which merging starts galloping.
+	initialMinGallop = 7
+)
+
+// TimSort sorts s stably with Tim Peters' adaptive merge sort. It finds the runs that are already
+// ascending (or strictly descending, which it reverses), extends short runs with binary insertion,
+// and merges them while keeping the run lengths balanced. When one run keeps winning, merging
+// switches to galloping: exponential search for how many elements to copy at once.
+// It runs in O(n) on input made of a few runs.
+//
+// References: https://github.com/python/cpython/blob/main/Objects/listsort.txt
+func TimSort[T any](s []T, cmp common.Comparator[T, T]) {
+	n := len(s)
+	if n < 2 {
+		return
+	}
+	if n < minMerge {
+		binaryInsertionSort(s, 0, n, countRun(s, 0, n, cmp), cmp)
+		return
+	}
+
+	ts := &timSort[T]{s: s, cmp: cmp, minGallop: initialMinGallop}
+	minRun := minRunLength(n)
+	for lo := 0; lo < n; {
+		run := countRun(s, lo, n, cmp)
+		if run < minRun {
+			force := minInt(minRun, n-lo)
+			binaryInsertionSort(s, lo, lo+force, lo+run, cmp)
+			run = force
+		}
+		ts.runs = append(ts.runs, timRun{lo, run})
+		ts.mergeCollapse()
+		lo += run
+	}
+	ts.mergeForceCollapse()
+}
+
+type timRun struct {
+	base, len int
+}
+
+type timSort[T any] struct {
+	s         []T
+	cmp       common.Comparator[T, T]
+	runs      []timRun // pending runs, adjacent and in order
+	tmp       []T      // merge buffer for the shorter run
+	minGallop int
+}
+
+// minRunLength returns a minimum run length between minMerge/2 and minMerge such that n divided
+// into runs of that length gives a power of two runs, or slightly less.
+func minRunLength(n int) int {
+	r := 0
+	for n >= minMerge {
+		r |= n & 1
+		n >>= 1
+	}
+	return n + r
+}
+
+// countRun returns the length of the run starting at lo in s[lo:hi], reversing it if it is descending.
+// Only strictly descending runs are reversed, which keeps the sort stable.
+func countRun[T any](s []T, lo, hi int, cmp common.Comparator[T, T]) int {
+	i := lo + 1
+	if i == hi {
+		return 1
+	}
+	if cmp(s[i], s[lo]) < 0 {
+		for i++; i < hi && cmp(s[i], s[i-1]) < 0; i++ {
+		}
+		reverse(s[lo:i])
+	} else {
+		for i++; i < hi && cmp(s[i], s[i-1]) >= 0; i++ {
+		}
+	}
+	return i - lo
+}
+
+// binaryInsertionSort sorts s[lo:hi] given that s[lo:start] is already sorted, finding each
+// insertion point by binary search after any equal elements.
+func binaryInsertionSort[T any](s []T, lo, hi, start int, cmp common.Comparator[T, T]) {
+	for i := start; i < hi; i++ {
+		pivot := s[i]
+		left, right := lo, i
+		for left < right {
+			mid := left + (right-left)>>1
+			if cmp(pivot, s[mid]) < 0 {
+				right = mid
+			} else {
+				left = mid + 1
+			}
+		}
+		copy(s[left+1:i+1], s[left:i])
+		s[left] = pivot
+	}
+}
+
+// mergeCollapse merges pending runs until the lengths of the top three, from the top, satisfy
+// A > B + C and B > C, so that the lengths grow at least as fast as Fibonacci numbers.
+// It also checks the fourth run to fix the flaw found by de Gouw et al. in the original invariant.
+func (ts *timSort[T]) mergeCollapse() {
+	for len(ts.runs) > 1 {
+		r := ts.runs
+		n := len(r) - 2
+		if n > 0 && r[n-1].len <= r[n].len+r[n+1].len || n > 1 && r[n-2].len <= r[n-1].len+r[n].len {
+			if r[n-1].len < r[n+1].len {
+				n--
+			}
+		} else if r[n].len > r[n+1].len {
+			return
+		}
+		ts.mergeAt(n)
+	}
+}
+
+// mergeForceCollapse merges all pending runs into one.
+func (ts *timSort[T]) mergeForceCollapse() {
+	for len(ts.runs) > 1 {
+		n := len(ts.runs) - 2
+		if n > 0 && ts.runs[n-1].len < ts.runs[n+1].len {
+			n--
+		}
+		ts.mergeAt(n)
+	}
+}
+
+// mergeAt merges the pending runs i and i+1.
+func (ts *timSort[T]) mergeAt(i int) {
+	s, cmp := ts.s, ts.cmp
+	base1, len1 := ts.runs[i].base, ts.runs[i].len
+	base2, len2 := ts.runs[i+1].base, ts.runs[i+1].len
+	ts.runs[i].len = len1 + len2
+	ts.runs = append(ts.runs[:i+1], ts.runs[i+2:]...)
+
+	// Elements of run 1 not greater than the first of run 2 are already in place.
+	k := gallopRight(s[base2], s[base1:base1+len1], 0, cmp)
+	base1 += k
+	len1 -= k
+	if len1 == 0 {
+		return
+	}
+	// Elements of run 2 not less than the last of run 1 are already in place.
+	len2 = gallopLeft(s[base1+len1-1], s[base2:base2+len2], len2-1, cmp)
+	if len2 == 0 {
+		return
+	}
+
+	if len1 <= len2 {
+		ts.mergeLo(base1, base2, base2+len2)
+	} else {
+		ts.mergeHi(base1, base2, base2+len2)
+	}
+}
+
+// mergeLo merges s[lo:mid] and s[mid:hi] front to back, buffering the left run, which is the shorter.
+func (ts *timSort[T]) mergeLo(lo, mid, hi int) {
+	s, cmp := ts.s, ts.cmp
+	left := append(ts.tmp[:0], s[lo:mid]...)
+	ts.tmp = left
+	i, j, k := 0, mid, lo // k+len(left)-i == j: the gap is exactly what is left in the buffer
+
+outer:
+	for {
+		// Take one element at a time until one run wins minGallop times in a row.
+		wins1, wins2 := 0, 0
+		for wins1 < ts.minGallop && wins2 < ts.minGallop {
+			if cmp(s[j], left[i]) < 0 {
+				s[k] = s[j]
+				j++
+				wins1, wins2 = 0, wins2+1
+			} else {
+				s[k] = left[i]
+				i++
+				wins1, wins2 = wins1+1, 0
+			}
+			k++
+			if i == len(left) || j == hi {
+				break outer
+			}
+		}
+
+		// Gallop until neither run wins by a long stretch any more.
+		for {
+			n1 := gallopRight(s[j], left[i:], 0, cmp)
+			copy(s[k:], left[i:i+n1])
+			i += n1
+			k += n1
+			if i == len(left) {
+				break outer
+			}
+			n2 := gallopLeft(left[i], s[j:hi], 0, cmp)
+			copy(s[k:], s[j:j+n2])
+			j += n2
+			k += n2
+			if j == hi {
+				break outer
+			}
+			if n1 < initialMinGallop && n2 < initialMinGallop {
+				ts.minGallop++ // galloping did not pay off, make it harder to enter
+				break
+			}
+			if ts.minGallop > 1 {
+				ts.minGallop--
+			}
+		}
+	}
+	copy(s[k:], left[i:])
+}
+
+// mergeHi merges s[lo:mid] and s[mid:hi] back to front, buffering the right run, which is the shorter.
+func (ts *timSort[T]) mergeHi(lo, mid, hi int) {
+	s, cmp := ts.s, ts.cmp
+	right := append(ts.tmp[:0], s[mid:hi]...)
+	ts.tmp = right
+	i, j, k := len(right)-1, mid-1, hi-1
+
+outer:
+	for {
+		wins1, wins2 := 0, 0
+		for wins1 < ts.minGallop && wins2 < ts.minGallop {
+			if cmp(right[i], s[j]) < 0 {
+				s[k] = s[j]
+				j--
+				wins1, wins2 = wins1+1, 0
+			} else {
+				s[k] = right[i]
+				i--
+				wins1, wins2 = 0, wins2+1
+			}
+			k--
+			if i < 0 || j < lo {
+				break outer
+			}
+		}
+
+		for {
+			// Elements of the left run greater than right[i] go to the end.
+			n1 := j + 1 - lo - gallopRight(right[i], s[lo:j+1], j-lo, cmp)
+			copy(s[k-n1+1:k+1], s[j-n1+1:j+1])
+			j -= n1
+			k -= n1
+			if j < lo {
+				break outer
+			}
+			// Elements of the right run not less than s[j] go to the end.
+			n2 := i + 1 - gallopLeft(s[j], right[:i+1], i, cmp)
+			copy(s[k-n2+1:k+1], right[i-n2+1:i+1])
+			i -= n2
+			k -= n2
+			if i < 0 {
+				break outer
+			}
+			if n1 < initialMinGallop && n2 < initialMinGallop {
+				ts.minGallop++
+				break
+			}
+			if ts.minGallop > 1 {
+				ts.minGallop--
+			}
+		}
+	}
+	copy(s[lo:], right[:i+1])
+}
+
+// gallopLeft returns the number of elements of the sorted slice s that are less than key,
+// searching exponentially outward from hint before finishing with a binary search.
+func gallopLeft[T any](key T, s []T, hint int, cmp common.Comparator[T, T]) int {
+	lastOfs, ofs := 0, 1
+	if cmp(s[hint], key) < 0 {
+		// s[hint] < key: gallop right until s[hint+lastOfs] < key <= s[hint+ofs].
+		maxOfs := len(s) - hint
+		for ofs < maxOfs && cmp(s[hint+ofs], key) < 0 {
+			lastOfs, ofs = ofs, ofs<<1+1
+		}
+		if ofs > maxOfs {
+			ofs = maxOfs
+		}
+		lastOfs, ofs = hint+lastOfs, hint+ofs
+	} else {
+		// key <= s[hint]: gallop left until s[hint-ofs] < key <= s[hint-lastOfs].
+		maxOfs := hint + 1
+		for ofs < maxOfs && cmp(s[hint-ofs], key) >= 0 {
+			lastOfs, ofs = ofs, ofs<<1+1
+		}
+		if ofs > maxOfs {
+			ofs = maxOfs
+		}
+		lastOfs, ofs = hint-ofs, hint-lastOfs
+	}
+
+	// s[lastOfs] < key <= s[ofs], where index -1 and len(s) stand for the ends.
+	for lastOfs++; lastOfs < ofs; {
+		m := lastOfs + (ofs-lastOfs)>>1
+		if cmp(s[m], key) < 0 {
+			lastOfs = m + 1
+		} else {
+			ofs = m
+		}
+	}
+	return ofs
+}
+
+// gallopRight returns the number of elements of the sorted slice s that are less than or equal
+// to key, searching exponentially outward from hint before finishing with a binary search.
+func gallopRight[T any](key T, s []T, hint int, cmp common.Comparator[T, T]) int {
+	lastOfs, ofs := 0, 1
+	if cmp(key, s[hint]) < 0 {
+		// key < s[hint]: gallop left until s[hint-ofs] <= key < s[hint-lastOfs].
+		maxOfs := hint + 1
+		for ofs < maxOfs && cmp(key, s[hint-ofs]) < 0 {
+			lastOfs, ofs = ofs, ofs<<1+1
+		}
+		if ofs > maxOfs {
+			ofs = maxOfs
+		}
+		lastOfs, ofs = hint-ofs, hint-lastOfs
+	} else {
+		// s[hint] <= key: gallop right until s[hint+lastOfs] <= key < s[hint+ofs].
+		maxOfs := len(s) - hint
+		for ofs < maxOfs && cmp(key, s[hint+ofs]) >= 0 {
+			lastOfs, ofs = ofs, ofs<<1+1
+		}
+		if ofs > maxOfs {
+			ofs = maxOfs
+		}
+		lastOfs, ofs = hint+lastOfs, hint+ofs
+	}
+
+	// s[lastOfs] <= key < s[ofs], where index -1 and len(s) stand for the ends.
+	for lastOfs++; lastOfs < ofs; {
+		m := lastOfs + (ofs-lastOfs)>>1
+		if cmp(key, s[m]) < 0 {
+			ofs = m
+		} else {
+			lastOfs = m + 1
+		}
+	}
+	return ofs
+}
@@ -0,0 +1,143 @@
+// Package fenwicktree implements a Fenwick tree, also called binary indexed tree, for prefix sums
+// over an array of numbers that changes: both adding to a position and summing a prefix take O(log n).
+//
+// Node i (1-based) stores the sum of the i&-i positions ending at i, so a prefix sum adds up the
+// nodes met by repeatedly clearing the lowest set bit, and an update touches the nodes met by
+// repeatedly adding it.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Fenwick_tree
+package fenwicktree
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/trees"
+)
+
+var _ trees.Tree[int] = (*Tree[int])(nil)
+
+var (
+	ErrIndexOutOfRange = errors.New("index out of range")
+	ErrInvalidRange    = errors.New("invalid range")
+)
+
+// Tree holds n numbers, initially zero, and answers prefix and range sums.
+type Tree[T common.Number] struct {
+	nodes []T // nodes[0] is unused
+}
+
+// New returns a tree of n zeros.
+func New[T common.Number](n int) *Tree[T] {
+	return &Tree[T]{nodes: make([]T, n+1)}
+}
+
+// FromSlice returns a tree holding a copy of values. It is built in O(n).
+func FromSlice[T common.Number](values []T) *Tree[T] {
+	t := &Tree[T]{nodes: make([]T, len(values)+1)}
+	copy(t.nodes[1:], values)
+	for i := 1; i < len(t.nodes); i++ {
+		// Every node passes its sum on to its parent once its own sum is complete.
+		if parent := i + i&-i; parent < len(t.nodes) {
+			t.nodes[parent] += t.nodes[i]
+		}
+	}
+	return t
+}
+
+// Add adds delta to the number at position i.
+func (t *Tree[T]) Add(i int, delta T) error {
+	if i < 0 || i >= t.Size() {
+		return fmt.Errorf("%w: %d", ErrIndexOutOfRange, i)
+	}
+	for i++; i < len(t.nodes); i += i & -i {
+		t.nodes[i] += delta
+	}
+	return nil
+}
+
+// Set replaces the number at position i.
+func (t *Tree[T]) Set(i int, value T) error {
+	old, err := t.Get(i)
+	if err != nil {
+		return err
+	}
+	return t.Add(i, value-old)
+}
+
+// Get returns the number at position i.
+func (t *Tree[T]) Get(i int) (T, error) {
+	if i < 0 || i >= t.Size() {
+		var zero T
+		return zero, fmt.Errorf("%w: %d", ErrIndexOutOfRange, i)
+	}
+	return t.PrefixSum(i+1) - t.PrefixSum(i), nil
+}
+
+// PrefixSum returns the sum of the numbers at positions 0 to i-1. It returns 0 for i <= 0,
+// and the total for i >= Size().
+func (t *Tree[T]) PrefixSum(i int) T {
+	if i > t.Size() {
+		i = t.Size()
+	}
+	var sum T
+	for ; i > 0; i -= i & -i {
+		sum += t.nodes[i]
+	}
+	return sum
+}
+
+// RangeSum returns the sum of the numbers at positions lo to hi-1.
+func (t *Tree[T]) RangeSum(lo, hi int) (T, error) {
+	if lo < 0 || hi > t.Size() || lo > hi {
+		var zero T
+		return zero, fmt.Errorf("%w: [%d, %d) of %d", ErrInvalidRange, lo, hi, t.Size())
+	}
+	return t.PrefixSum(hi) - t.PrefixSum(lo), nil
+}
+
+// LowerBound returns the smallest i such that PrefixSum(i+1) >= target, or Size() if there is none.
+// All numbers must be non-negative, so that prefix sums never decrease. It runs in O(log n) by
+// descending the implicit tree instead of binary searching over PrefixSum.
+func (t *Tree[T]) LowerBound(target T) int {
+	if target <= 0 {
+		return 0
+	}
+	pos := 0 // PrefixSum(pos) < target
+	step := 1
+	for step*2 < len(t.nodes) {
+		step *= 2
+	}
+	for ; step > 0; step >>= 1 {
+		if next := pos + step; next < len(t.nodes) && t.nodes[next] < target {
+			pos = next
+			target -= t.nodes[next]
+		}
+	}
+	return pos
+}
+
+// Empty returns true if the tree holds no numbers.
+func (t *Tree[T]) Empty() bool {
+	return t.Size() == 0
+}
+
+// Size returns the number of numbers.
+func (t *Tree[T]) Size() int {
+	return len(t.nodes) - 1
+}
+
+// Reset sets every number to zero, keeping the size.
+func (t *Tree[T]) Reset() {
+	for i := range t.nodes {
+		t.nodes[i] = 0
+	}
+}
+
+// Clear removes all positions.
+func (t *Tree[T]) Clear() {
+	t.nodes = make([]T, 1)
+}
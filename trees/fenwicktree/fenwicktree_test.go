@@ -0,0 +1,97 @@
+package fenwicktree
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree(t *testing.T) {
+	tree := FromSlice([]int{3, 1, 4, 1, 5, 9, 2, 6})
+	assert.Equal(t, 8, tree.Size())
+	assert.Equal(t, 0, tree.PrefixSum(0))
+	assert.Equal(t, 9, tree.PrefixSum(4))
+	assert.Equal(t, 31, tree.PrefixSum(8))
+	assert.Equal(t, 31, tree.PrefixSum(100))
+	assert.Equal(t, 0, tree.PrefixSum(-1))
+
+	sum, err := tree.RangeSum(2, 6)
+	assert.NoError(t, err)
+	assert.Equal(t, 19, sum)
+
+	assert.NoError(t, tree.Add(3, 10))
+	v, _ := tree.Get(3)
+	assert.Equal(t, 11, v)
+	assert.NoError(t, tree.Set(0, 0))
+	assert.Equal(t, 38, tree.PrefixSum(8))
+
+	assert.True(t, errors.Is(tree.Add(8, 1), ErrIndexOutOfRange))
+	_, err = tree.Get(-1)
+	assert.True(t, errors.Is(err, ErrIndexOutOfRange))
+	_, err = tree.RangeSum(5, 3)
+	assert.True(t, errors.Is(err, ErrInvalidRange))
+
+	tree.Reset()
+	assert.Equal(t, 0, tree.PrefixSum(8))
+	assert.Equal(t, 8, tree.Size())
+	assert.False(t, tree.Empty())
+
+	tree.Clear()
+	assert.True(t, tree.Empty())
+	assert.Equal(t, 0, tree.PrefixSum(8))
+	assert.Equal(t, 0, tree.LowerBound(1))
+	assert.True(t, errors.Is(tree.Add(0, 1), ErrIndexOutOfRange))
+	assert.True(t, New[float64](0).Empty())
+}
+
+func TestTree_LowerBound(t *testing.T) {
+	// Prefix sums: 2 2 5 6 6 10
+	tree := FromSlice([]int{2, 0, 3, 1, 0, 4})
+	tests := map[int]int{-1: 0, 0: 0, 1: 0, 2: 0, 3: 2, 5: 2, 6: 3, 7: 5, 10: 5, 11: 6}
+	for target, want := range tests {
+		assert.Equal(t, want, tree.LowerBound(target), "target %d", target)
+	}
+}
+
+func TestTree_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 17, 64, 100} {
+		values := make([]int64, n)
+		for i := range values {
+			values[i] = r.Int63n(100)
+		}
+		built := FromSlice(values)
+		added := New[int64](n)
+		for i, v := range values {
+			assert.NoError(t, added.Add(i, v))
+		}
+		assert.Equal(t, added.nodes, built.nodes)
+
+		for i := 0; i < 200; i++ {
+			pos, delta := r.Intn(n), r.Int63n(50)
+			values[pos] += delta
+			assert.NoError(t, built.Add(pos, delta))
+
+			lo := r.Intn(n + 1)
+			hi := lo + r.Intn(n-lo+1)
+			var want int64
+			for _, v := range values[lo:hi] {
+				want += v
+			}
+			got, err := built.RangeSum(lo, hi)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+
+			target := r.Int63n(built.PrefixSum(n) + 2)
+			idx := built.LowerBound(target)
+			if idx < n {
+				assert.GreaterOrEqual(t, built.PrefixSum(idx+1), target)
+			}
+			if idx > 0 {
+				assert.Less(t, built.PrefixSum(idx), target)
+			}
+		}
+	}
+}
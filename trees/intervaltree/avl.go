@@ -0,0 +1,136 @@
+package intervaltree
+
+import "github.com/kwstars/goads/pkg/common"
+
+// compare orders the interval [start, end] against the interval of an entry: by start, then by end.
+func compare[K common.Ordered, V any](start, end K, e Entry[K, V]) int {
+	switch {
+	case start < e.Start:
+		return -1
+	case start > e.Start:
+		return 1
+	case end < e.End:
+		return -1
+	case end > e.End:
+		return 1
+	}
+	return 0
+}
+
+func height[K common.Ordered, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// update recomputes the height and the largest end of n from its children.
+func (n *node[K, V]) update() {
+	n.height = height(n.left)
+	if h := height(n.right); h > n.height {
+		n.height = h
+	}
+	n.height++
+
+	n.maxEnd = n.entry.End
+	if n.left != nil && n.left.maxEnd > n.maxEnd {
+		n.maxEnd = n.left.maxEnd
+	}
+	if n.right != nil && n.right.maxEnd > n.maxEnd {
+		n.maxEnd = n.right.maxEnd
+	}
+}
+
+func (n *node[K, V]) balanceFactor() int {
+	return height(n.left) - height(n.right)
+}
+
+// rotateRight lifts the left child of n and returns it as the new subtree root.
+func rotateRight[K common.Ordered, V any](n *node[K, V]) *node[K, V] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
+
+// rotateLeft lifts the right child of n and returns it as the new subtree root.
+func rotateLeft[K common.Ordered, V any](n *node[K, V]) *node[K, V] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+// rebalance restores the AVL property and the max end at n after one of its subtrees changed.
+func rebalance[K common.Ordered, V any](n *node[K, V]) *node[K, V] {
+	n.update()
+	switch bf := n.balanceFactor(); {
+	case bf > 1:
+		if n.left.balanceFactor() < 0 {
+			n.left = rotateLeft(n.left)
+		}
+		return rotateRight(n)
+	case bf < -1:
+		if n.right.balanceFactor() > 0 {
+			n.right = rotateRight(n.right)
+		}
+		return rotateLeft(n)
+	}
+	return n
+}
+
+// insert adds e to the subtree rooted at n, or replaces the value of an equal interval.
+// It reports whether the interval was new.
+func insert[K common.Ordered, V any](n *node[K, V], e Entry[K, V]) (*node[K, V], bool) {
+	if n == nil {
+		return &node[K, V]{entry: e, maxEnd: e.End, height: 1}, true
+	}
+	var added bool
+	switch c := compare(e.Start, e.End, n.entry); {
+	case c < 0:
+		n.left, added = insert(n.left, e)
+	case c > 0:
+		n.right, added = insert(n.right, e)
+	default:
+		n.entry.Value = e.Value
+		return n, false
+	}
+	return rebalance(n), added
+}
+
+// remove removes the interval [start, end] from the subtree rooted at n. It reports whether it was present.
+func remove[K common.Ordered, V any](n *node[K, V], start, end K) (*node[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+	var removed bool
+	switch c := compare(start, end, n.entry); {
+	case c < 0:
+		n.left, removed = remove(n.left, start, end)
+	case c > 0:
+		n.right, removed = remove(n.right, start, end)
+	default:
+		if n.left == nil {
+			return n.right, true
+		}
+		if n.right == nil {
+			return n.left, true
+		}
+		// Replace the entry with its in-order successor and remove the successor instead.
+		successor := n.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		n.entry = successor.entry
+		n.right, _ = remove(n.right, successor.entry.Start, successor.entry.End)
+		removed = true
+	}
+	if !removed {
+		return n, false
+	}
+	return rebalance(n), true
+}
@@ -0,0 +1,174 @@
+// Package intervaltree implements an interval tree: a map from closed intervals to values
+// answering which intervals contain a point (stabbing queries) or overlap an interval.
+//
+// It is an AVL tree ordered by interval start, then end. Every node also stores the largest end
+// in its subtree, which lets queries skip subtrees that end before the query starts.
+// Insertion and removal take O(log n); a query reporting m intervals takes O(min(n, (m+1)·log n)).
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Interval_tree#Augmented_tree
+package intervaltree
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/trees"
+)
+
+var _ trees.Tree[int] = (*Tree[int, int])(nil)
+
+var ErrInvalidInterval = errors.New("interval start is after its end")
+
+// Entry is the closed interval [Start, End] together with its value.
+type Entry[K common.Ordered, V any] struct {
+	Start K
+	End   K
+	Value V
+}
+
+// Overlaps returns true if the entry's interval shares a point with [start, end].
+func (e Entry[K, V]) Overlaps(start, end K) bool {
+	return e.Start <= end && start <= e.End
+}
+
+type node[K common.Ordered, V any] struct {
+	entry       Entry[K, V]
+	maxEnd      K // largest End in the subtree
+	left, right *node[K, V]
+	height      int
+}
+
+// Tree maps intervals to values. Each interval is stored at most once.
+type Tree[K common.Ordered, V any] struct {
+	root *node[K, V]
+	size int
+}
+
+// New returns an empty interval tree.
+func New[K common.Ordered, V any]() *Tree[K, V] {
+	return &Tree[K, V]{}
+}
+
+// Put maps the interval [start, end] to value, replacing the value if the interval is already present.
+// It returns ErrInvalidInterval if start is after end.
+func (t *Tree[K, V]) Put(start, end K, value V) error {
+	if start > end {
+		return fmt.Errorf("%w: [%v, %v]", ErrInvalidInterval, start, end)
+	}
+	var added bool
+	t.root, added = insert(t.root, Entry[K, V]{Start: start, End: end, Value: value})
+	if added {
+		t.size++
+	}
+	return nil
+}
+
+// Get returns the value of the interval [start, end].
+func (t *Tree[K, V]) Get(start, end K) (V, bool) {
+	for n := t.root; n != nil; {
+		switch c := compare(start, end, n.entry); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.entry.Value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Remove removes the interval [start, end]. It returns false if it was not present.
+func (t *Tree[K, V]) Remove(start, end K) bool {
+	var removed bool
+	t.root, removed = remove(t.root, start, end)
+	if removed {
+		t.size--
+	}
+	return removed
+}
+
+// Stab returns the entries whose intervals contain point, ordered by start, then end.
+func (t *Tree[K, V]) Stab(point K) []Entry[K, V] {
+	return t.Overlapping(point, point)
+}
+
+// Overlapping returns the entries whose intervals overlap [start, end], ordered by start, then end.
+func (t *Tree[K, V]) Overlapping(start, end K) []Entry[K, V] {
+	var found []Entry[K, V]
+	t.EachOverlapping(start, end, func(e Entry[K, V]) bool {
+		found = append(found, e)
+		return true
+	})
+	return found
+}
+
+// EachOverlapping calls fn for each entry whose interval overlaps [start, end], ordered by start,
+// then end, until fn returns false.
+func (t *Tree[K, V]) EachOverlapping(start, end K, fn func(e Entry[K, V]) bool) {
+	eachOverlapping(t.root, start, end, fn)
+}
+
+func eachOverlapping[K common.Ordered, V any](n *node[K, V], start, end K, fn func(e Entry[K, V]) bool) bool {
+	if n == nil || n.maxEnd < start {
+		return true // nothing in this subtree reaches start
+	}
+	if !eachOverlapping(n.left, start, end, fn) {
+		return false
+	}
+	if n.entry.Start > end {
+		return true // this node and its right subtree start after end
+	}
+	if n.entry.End >= start && !fn(n.entry) {
+		return false
+	}
+	return eachOverlapping(n.right, start, end, fn)
+}
+
+// Entries returns all entries ordered by start, then end.
+func (t *Tree[K, V]) Entries() []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, t.size)
+	var walk func(n *node[K, V])
+	walk = func(n *node[K, V]) {
+		if n == nil {
+			return
+		}
+		walk(n.left)
+		entries = append(entries, n.entry)
+		walk(n.right)
+	}
+	walk(t.root)
+	return entries
+}
+
+// Span returns the smallest start and the largest end of all intervals.
+func (t *Tree[K, V]) Span() (start, end K, ok bool) {
+	if t.root == nil {
+		return start, end, false
+	}
+	n := t.root
+	for n.left != nil {
+		n = n.left
+	}
+	return n.entry.Start, t.root.maxEnd, true
+}
+
+// Empty returns true if the tree holds no intervals.
+func (t *Tree[K, V]) Empty() bool {
+	return t.size == 0
+}
+
+// Size returns the number of intervals.
+func (t *Tree[K, V]) Size() int {
+	return t.size
+}
+
+// Clear removes all intervals.
+func (t *Tree[K, V]) Clear() {
+	t.root = nil
+	t.size = 0
+}
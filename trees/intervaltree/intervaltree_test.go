@@ -0,0 +1,150 @@
+package intervaltree
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree(t *testing.T) {
+	tree := New[int, string]()
+	assert.True(t, tree.Empty())
+	_, _, ok := tree.Span()
+	assert.False(t, ok)
+
+	assert.NoError(t, tree.Put(15, 20, "a"))
+	assert.NoError(t, tree.Put(10, 30, "b"))
+	assert.NoError(t, tree.Put(17, 19, "c"))
+	assert.NoError(t, tree.Put(5, 20, "d"))
+	assert.NoError(t, tree.Put(12, 15, "e"))
+	assert.NoError(t, tree.Put(30, 40, "f"))
+	assert.Equal(t, 6, tree.Size())
+
+	assert.True(t, errors.Is(tree.Put(2, 1, "x"), ErrInvalidInterval))
+
+	values := func(entries []Entry[int, string]) []string {
+		var vs []string
+		for _, e := range entries {
+			vs = append(vs, e.Value)
+		}
+		return vs
+	}
+	assert.Equal(t, []string{"d", "b", "e", "a"}, values(tree.Stab(15)))
+	assert.Equal(t, []string{"b", "f"}, values(tree.Stab(30)))
+	assert.Empty(t, tree.Stab(41))
+	assert.Equal(t, []string{"d", "b", "e"}, values(tree.Overlapping(0, 14)))
+
+	// Replacing a value keeps the size.
+	assert.NoError(t, tree.Put(15, 20, "A"))
+	assert.Equal(t, 6, tree.Size())
+	v, ok := tree.Get(15, 20)
+	assert.True(t, ok)
+	assert.Equal(t, "A", v)
+	_, ok = tree.Get(15, 21)
+	assert.False(t, ok)
+
+	start, end, ok := tree.Span()
+	assert.True(t, ok)
+	assert.Equal(t, 5, start)
+	assert.Equal(t, 40, end)
+
+	assert.True(t, tree.Remove(10, 30))
+	assert.False(t, tree.Remove(10, 30))
+	assert.Equal(t, []string{"f"}, values(tree.Stab(30)))
+
+	// Stop early.
+	var seen []string
+	tree.EachOverlapping(0, 100, func(e Entry[int, string]) bool {
+		seen = append(seen, e.Value)
+		return len(seen) < 2
+	})
+	assert.Equal(t, []string{"d", "e"}, seen)
+
+	tree.Clear()
+	assert.Equal(t, 0, tree.Size())
+	assert.Empty(t, tree.Entries())
+}
+
+func TestTree_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tree := New[int, int]()
+	reference := make(map[[2]int]int)
+
+	for i := 0; i < 3000; i++ {
+		start := r.Intn(1000)
+		end := start + r.Intn(100)
+		if r.Intn(3) == 0 && len(reference) > 0 {
+			assert.Equal(t, tree.Remove(start, end), func() bool { _, ok := reference[[2]int{start, end}]; return ok }())
+			delete(reference, [2]int{start, end})
+		} else {
+			assert.NoError(t, tree.Put(start, end, i))
+			reference[[2]int{start, end}] = i
+		}
+		if i%100 == 0 {
+			checkInvariants(t, tree.root)
+		}
+	}
+	assert.Equal(t, len(reference), tree.Size())
+	checkInvariants(t, tree.root)
+
+	for i := 0; i < 300; i++ {
+		start := r.Intn(1100) - 50
+		end := start + r.Intn(20)
+		var want []Entry[int, int]
+		for k, v := range reference {
+			if k[0] <= end && start <= k[1] {
+				want = append(want, Entry[int, int]{Start: k[0], End: k[1], Value: v})
+			}
+		}
+		sort.Slice(want, func(i, j int) bool {
+			return want[i].Start < want[j].Start || want[i].Start == want[j].Start && want[i].End < want[j].End
+		})
+		got := tree.Overlapping(start, end)
+		if len(want) == 0 {
+			assert.Empty(t, got)
+		} else {
+			assert.Equal(t, want, got)
+		}
+	}
+}
+
+// checkInvariants verifies the AVL balance, the stored heights and the max end of every node.
+func checkInvariants(t *testing.T, n *node[int, int]) (height int, maxEnd int) {
+	if n == nil {
+		return 0, -1 << 62
+	}
+	lh, lm := checkInvariants(t, n.left)
+	rh, rm := checkInvariants(t, n.right)
+	assert.LessOrEqual(t, lh-rh, 1)
+	assert.GreaterOrEqual(t, lh-rh, -1)
+	height = lh + 1
+	if rh >= lh {
+		height = rh + 1
+	}
+	assert.Equal(t, height, n.height)
+	maxEnd = n.entry.End
+	if lm > maxEnd {
+		maxEnd = lm
+	}
+	if rm > maxEnd {
+		maxEnd = rm
+	}
+	assert.Equal(t, maxEnd, n.maxEnd)
+	return height, maxEnd
+}
+
+func BenchmarkStab(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	tree := New[int, int]()
+	for i := 0; i < 100000; i++ {
+		start := r.Intn(1 << 20)
+		_ = tree.Put(start, start+r.Intn(100), i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Stab(r.Intn(1 << 20))
+	}
+}
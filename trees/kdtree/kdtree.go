@@ -0,0 +1,271 @@
+// Package kdtree implements a k-d tree: a binary space partitioning tree over points in k dimensions
+// answering nearest-neighbor, radius and box queries.
+//
+// Each node splits space at its point along one axis, cycling through the axes by depth.
+// New builds a balanced tree by splitting at the median, so queries visit O(log n) nodes on typical
+// data. Insert adds points without rebalancing; rebuild with New after many insertions.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/K-d_tree
+package kdtree
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kwstars/goads/sorting"
+	"github.com/kwstars/goads/sorting/selection"
+	"github.com/kwstars/goads/trees"
+	"github.com/kwstars/goads/trees/binaryheap"
+	"github.com/kwstars/goads/trees/spatial"
+)
+
+var _ trees.Tree[int] = (*Tree[int, int])(nil)
+
+var ErrDimension = errors.New("point has the wrong number of dimensions")
+
+// Item is a point with its value.
+type Item[C spatial.Coordinate, V any] struct {
+	Point []C
+	Value V
+}
+
+// Neighbor is an item found by a distance query, with its squared Euclidean distance to the query point.
+type Neighbor[C spatial.Coordinate, V any] struct {
+	Item[C, V]
+	DistanceSquared float64
+}
+
+type node[C spatial.Coordinate, V any] struct {
+	item        Item[C, V]
+	axis        int
+	left, right *node[C, V] // points below, and not below, item.Point[axis]
+}
+
+// Tree is a k-d tree of items.
+type Tree[C spatial.Coordinate, V any] struct {
+	k    int
+	root *node[C, V]
+	size int
+}
+
+// New returns a balanced tree of k-dimensional items. The items slice is reordered.
+// It returns ErrDimension if a point does not have k coordinates.
+func New[C spatial.Coordinate, V any](k int, items ...Item[C, V]) (*Tree[C, V], error) {
+	if k < 1 {
+		return nil, fmt.Errorf("%w: k = %d", ErrDimension, k)
+	}
+	for _, it := range items {
+		if len(it.Point) != k {
+			return nil, fmt.Errorf("%w: %v in %d dimensions", ErrDimension, it.Point, k)
+		}
+	}
+	t := &Tree[C, V]{k: k, size: len(items)}
+	t.root = t.build(items, 0)
+	return t, nil
+}
+
+// build returns a subtree over items, splitting at the median along axis.
+func (t *Tree[C, V]) build(items []Item[C, V], axis int) *node[C, V] {
+	if len(items) == 0 {
+		return nil
+	}
+	mid := len(items) / 2
+	_ = selection.NthElement(items, mid, func(a, b Item[C, V]) int8 {
+		return compare(a.Point[axis], b.Point[axis])
+	})
+	// Points equal to the median on this axis belong to the right subtree, as in Insert,
+	// so move the points strictly below it to the front and split after them.
+	split := items[mid].Point[axis]
+	lo := 0
+	for i := 0; i < mid; i++ {
+		if items[i].Point[axis] < split {
+			items[i], items[lo] = items[lo], items[i]
+			lo++
+		}
+	}
+	items[lo], items[mid] = items[mid], items[lo]
+	mid = lo
+	next := (axis + 1) % t.k
+	return &node[C, V]{
+		item:  items[mid],
+		axis:  axis,
+		left:  t.build(items[:mid], next),
+		right: t.build(items[mid+1:], next),
+	}
+}
+
+// Insert adds a point with its value. It returns ErrDimension if the point does not have k coordinates.
+func (t *Tree[C, V]) Insert(point []C, value V) error {
+	if len(point) != t.k {
+		return fmt.Errorf("%w: %v in %d dimensions", ErrDimension, point, t.k)
+	}
+	it := Item[C, V]{Point: point, Value: value}
+	link := &t.root
+	axis := 0
+	for *link != nil {
+		n := *link
+		if point[n.axis] < n.item.Point[n.axis] {
+			link = &n.left
+		} else {
+			link = &n.right
+		}
+		axis = (n.axis + 1) % t.k
+	}
+	*link = &node[C, V]{item: it, axis: axis}
+	t.size++
+	return nil
+}
+
+// Nearest returns the n items nearest to point, nearest first. Ties are broken arbitrarily.
+// It keeps the candidates in a bounded max heap, so the farthest is replaced first, and skips
+// subtrees on the far side of a splitting plane that is farther away than the n-th candidate.
+func (t *Tree[C, V]) Nearest(point []C, n int) ([]Neighbor[C, V], error) {
+	if len(point) != t.k {
+		return nil, fmt.Errorf("%w: %v in %d dimensions", ErrDimension, point, t.k)
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+	heap := binaryheap.New(farthestFirst[C, V], binaryheap.WithInitialCapacity[Neighbor[C, V]](n))
+	t.nearest(t.root, point, n, heap)
+
+	found := make([]Neighbor[C, V], heap.Size())
+	for i := len(found) - 1; i >= 0; i-- {
+		found[i], _ = heap.Pop()
+	}
+	return found, nil
+}
+
+func (t *Tree[C, V]) nearest(nd *node[C, V], point []C, n int, heap *binaryheap.BinaryHeap[Neighbor[C, V]]) {
+	if nd == nil {
+		return
+	}
+	d := distanceSquared(point, nd.item.Point)
+	if heap.Size() < n {
+		heap.Push(Neighbor[C, V]{Item: nd.item, DistanceSquared: d})
+	} else if farthest, _ := heap.Peek(); d < farthest.DistanceSquared {
+		_, _ = heap.Pop()
+		heap.Push(Neighbor[C, V]{Item: nd.item, DistanceSquared: d})
+	}
+
+	diff := float64(point[nd.axis]) - float64(nd.item.Point[nd.axis])
+	near, far := nd.right, nd.left
+	if diff < 0 {
+		near, far = nd.left, nd.right
+	}
+	t.nearest(near, point, n, heap)
+	if farthest, _ := heap.Peek(); heap.Size() < n || diff*diff < farthest.DistanceSquared {
+		t.nearest(far, point, n, heap)
+	}
+}
+
+// Radius returns the items within distance r of point, nearest first.
+func (t *Tree[C, V]) Radius(point []C, r float64) ([]Neighbor[C, V], error) {
+	if len(point) != t.k {
+		return nil, fmt.Errorf("%w: %v in %d dimensions", ErrDimension, point, t.k)
+	}
+	var found []Neighbor[C, V]
+	var visit func(nd *node[C, V])
+	visit = func(nd *node[C, V]) {
+		if nd == nil {
+			return
+		}
+		if d := distanceSquared(point, nd.item.Point); d <= r*r {
+			found = append(found, Neighbor[C, V]{Item: nd.item, DistanceSquared: d})
+		}
+		diff := float64(point[nd.axis]) - float64(nd.item.Point[nd.axis])
+		if diff < r {
+			visit(nd.left)
+		}
+		if diff >= -r {
+			visit(nd.right)
+		}
+	}
+	visit(t.root)
+	sorting.TimSort(found, nearestFirst[C, V])
+	return found, nil
+}
+
+// Range returns the items whose points lie in the box spanned by min and max, borders included.
+func (t *Tree[C, V]) Range(min, max []C) ([]Item[C, V], error) {
+	if len(min) != t.k || len(max) != t.k {
+		return nil, fmt.Errorf("%w: box %v to %v in %d dimensions", ErrDimension, min, max, t.k)
+	}
+	var found []Item[C, V]
+	var visit func(nd *node[C, V])
+	visit = func(nd *node[C, V]) {
+		if nd == nil {
+			return
+		}
+		inside := true
+		for i, c := range nd.item.Point {
+			if c < min[i] || c > max[i] {
+				inside = false
+				break
+			}
+		}
+		if inside {
+			found = append(found, nd.item)
+		}
+		split := nd.item.Point[nd.axis]
+		if min[nd.axis] < split {
+			visit(nd.left)
+		}
+		if max[nd.axis] >= split {
+			visit(nd.right)
+		}
+	}
+	visit(t.root)
+	return found, nil
+}
+
+// Dimensions returns k.
+func (t *Tree[C, V]) Dimensions() int {
+	return t.k
+}
+
+// Empty returns true if the tree holds no items.
+func (t *Tree[C, V]) Empty() bool {
+	return t.size == 0
+}
+
+// Size returns the number of items.
+func (t *Tree[C, V]) Size() int {
+	return t.size
+}
+
+// Clear removes all items.
+func (t *Tree[C, V]) Clear() {
+	t.root = nil
+	t.size = 0
+}
+
+func distanceSquared[C spatial.Coordinate](a, b []C) float64 {
+	var d float64
+	for i := range a {
+		diff := float64(a[i]) - float64(b[i])
+		d += diff * diff
+	}
+	return d
+}
+
+// nearestFirst orders neighbors by ascending distance.
+func nearestFirst[C spatial.Coordinate, V any](a, b Neighbor[C, V]) int8 {
+	return compare(a.DistanceSquared, b.DistanceSquared)
+}
+
+// farthestFirst makes binaryheap.BinaryHeap a max heap by distance.
+func farthestFirst[C spatial.Coordinate, V any](a, b Neighbor[C, V]) int8 {
+	return compare(a.DistanceSquared, b.DistanceSquared)
+}
+
+func compare[T spatial.Coordinate](a, b T) int8 {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,166 @@
+package kdtree
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree(t *testing.T) {
+	tree, err := New(2,
+		Item[int, string]{Point: []int{2, 3}, Value: "a"},
+		Item[int, string]{Point: []int{5, 4}, Value: "b"},
+		Item[int, string]{Point: []int{9, 6}, Value: "c"},
+		Item[int, string]{Point: []int{4, 7}, Value: "d"},
+		Item[int, string]{Point: []int{8, 1}, Value: "e"},
+		Item[int, string]{Point: []int{7, 2}, Value: "f"},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, tree.Size())
+	assert.Equal(t, 2, tree.Dimensions())
+
+	near, err := tree.Nearest([]int{9, 2}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"e", "f"}, values(near))
+	assert.Equal(t, []float64{2, 4}, []float64{near[0].DistanceSquared, near[1].DistanceSquared})
+
+	within, err := tree.Radius([]int{5, 5}, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "d"}, values(within))
+
+	box, err := tree.Range([]int{4, 1}, []int{8, 4})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"b", "e", "f"}, itemValues(box))
+
+	assert.NoError(t, tree.Insert([]int{9, 3}, "g"))
+	near, _ = tree.Nearest([]int{9, 2}, 1)
+	assert.Equal(t, []string{"g"}, values(near))
+
+	all, _ := tree.Nearest([]int{0, 0}, 100)
+	assert.Len(t, all, 7)
+
+	_, err = tree.Nearest([]int{1}, 1)
+	assert.True(t, errors.Is(err, ErrDimension))
+	assert.True(t, errors.Is(tree.Insert([]int{1, 2, 3}, "x"), ErrDimension))
+	_, err = New(2, Item[int, string]{Point: []int{1}})
+	assert.True(t, errors.Is(err, ErrDimension))
+	_, err = New[int, string](0)
+	assert.True(t, errors.Is(err, ErrDimension))
+
+	tree.Clear()
+	assert.True(t, tree.Empty())
+	near, _ = tree.Nearest([]int{0, 0}, 3)
+	assert.Empty(t, near)
+}
+
+func TestDuplicateCoordinates(t *testing.T) {
+	// Many points share coordinates, so the median split must keep equal points together on the right.
+	var items []Item[int, int]
+	for i := 0; i < 50; i++ {
+		items = append(items, Item[int, int]{Point: []int{i % 3, i % 2}, Value: i})
+	}
+	tree, err := New(2, items...)
+	assert.NoError(t, err)
+
+	box, _ := tree.Range([]int{1, 0}, []int{1, 0})
+	var want []int
+	for i := 0; i < 50; i++ {
+		if i%3 == 1 && i%2 == 0 {
+			want = append(want, i)
+		}
+	}
+	assert.ElementsMatch(t, want, itemValues(box))
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const k = 3
+	point := func() []float64 {
+		p := make([]float64, k)
+		for i := range p {
+			p[i] = float64(rng.Intn(100))
+		}
+		return p
+	}
+
+	var items []Item[float64, int]
+	for i := 0; i < 300; i++ {
+		items = append(items, Item[float64, int]{Point: point(), Value: i})
+	}
+	// New reorders its argument, so build from a copy to keep items[i].Value == i.
+	tree, err := New(k, append([]Item[float64, int](nil), items[:200]...)...)
+	assert.NoError(t, err)
+	for _, it := range items[200:] {
+		assert.NoError(t, tree.Insert(it.Point, it.Value))
+	}
+
+	for q := 0; q < 50; q++ {
+		p := point()
+		dists := make([]float64, len(items))
+		for i, it := range items {
+			dists[i] = distanceSquared(p, it.Point)
+		}
+		sorted := append([]float64(nil), dists...)
+		sort.Float64s(sorted)
+
+		near, err := tree.Nearest(p, 10)
+		assert.NoError(t, err)
+		assert.Len(t, near, 10)
+		for i, n := range near {
+			assert.Equal(t, sorted[i], n.DistanceSquared)
+			assert.Equal(t, dists[n.Value], n.DistanceSquared)
+		}
+
+		within, err := tree.Radius(p, 15)
+		assert.NoError(t, err)
+		var want []int
+		for i, d := range dists {
+			if d <= 15*15 {
+				want = append(want, i)
+			}
+		}
+		assert.ElementsMatch(t, want, values(within))
+		assert.True(t, sort.SliceIsSorted(within, func(i, j int) bool {
+			return within[i].DistanceSquared < within[j].DistanceSquared
+		}))
+
+		lo, hi := point(), point()
+		for i := range lo {
+			if lo[i] > hi[i] {
+				lo[i], hi[i] = hi[i], lo[i]
+			}
+		}
+		box, err := tree.Range(lo, hi)
+		assert.NoError(t, err)
+		want = want[:0]
+		for _, it := range items {
+			inside := true
+			for i, c := range it.Point {
+				inside = inside && lo[i] <= c && c <= hi[i]
+			}
+			if inside {
+				want = append(want, it.Value)
+			}
+		}
+		assert.ElementsMatch(t, want, itemValues(box))
+	}
+}
+
+func values[C int | float64, V any](neighbors []Neighbor[C, V]) []V {
+	var vs []V
+	for _, n := range neighbors {
+		vs = append(vs, n.Value)
+	}
+	return vs
+}
+
+func itemValues[C int | float64, V any](items []Item[C, V]) []V {
+	var vs []V
+	for _, it := range items {
+		vs = append(vs, it.Value)
+	}
+	return vs
+}
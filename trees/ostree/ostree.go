@@ -0,0 +1,201 @@
+// Package ostree implements an order-statistic tree: a sorted multiset that also answers rank queries
+// ("how many elements are smaller than x") and selection queries ("which element has rank k") in O(log n).
+//
+// It is a left-leaning red-black tree in which every node stores the size of its subtree.
+// Elements are ordered by a comparator and may be inserted several times. Elements that compare
+// equal share one node and keep their insertion order.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Order_statistic_tree, https://en.wikipedia.org/wiki/Left-leaning_red%E2%80%93black_tree
+package ostree
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/trees"
+)
+
+var _ trees.Tree[int] = (*Tree[int])(nil)
+
+var (
+	ErrEmpty           = errors.New("tree is empty")
+	ErrIndexOutOfRange = errors.New("index out of range")
+)
+
+// Tree is an order-statistic tree holding elements of type T.
+type Tree[T any] struct {
+	root *node[T]
+	cmp  common.Comparator[T, T]
+}
+
+// New returns an empty tree ordered by cmp.
+func New[T any](cmp common.Comparator[T, T]) *Tree[T] {
+	return &Tree[T]{cmp: cmp}
+}
+
+// Insert adds x, keeping any elements equal to it.
+func (t *Tree[T]) Insert(x T) {
+	t.root = t.insert(t.root, x)
+	t.root.red = false
+}
+
+// Remove removes the most recently inserted element equal to x. It returns false if there is none.
+func (t *Tree[T]) Remove(x T) bool {
+	n := t.find(x)
+	if n == nil {
+		return false
+	}
+	if len(n.values) > 1 {
+		var zero T
+		n.values[len(n.values)-1] = zero
+		n.values = n.values[:len(n.values)-1]
+		// Only the sizes on the path to n change.
+		for p := t.root; p != n; {
+			p.size--
+			if t.cmp(x, p.values[0]) < 0 {
+				p = p.left
+			} else {
+				p = p.right
+			}
+		}
+		n.size--
+		return true
+	}
+	if !isRed(t.root.left) && !isRed(t.root.right) {
+		t.root.red = true
+	}
+	t.root = t.remove(t.root, x)
+	if t.root != nil {
+		t.root.red = false
+	}
+	return true
+}
+
+// Contains returns true if the tree holds an element equal to x.
+func (t *Tree[T]) Contains(x T) bool {
+	return t.find(x) != nil
+}
+
+// find returns the node holding the elements equal to x, or nil.
+func (t *Tree[T]) find(x T) *node[T] {
+	for n := t.root; n != nil; {
+		switch c := t.cmp(x, n.values[0]); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// Rank returns the number of elements smaller than x. If x is present, it is the index of its first occurrence.
+func (t *Tree[T]) Rank(x T) int {
+	return t.countBelow(x, false)
+}
+
+// Count returns the number of elements equal to x.
+func (t *Tree[T]) Count(x T) int {
+	if n := t.find(x); n != nil {
+		return len(n.values)
+	}
+	return 0
+}
+
+// CountRange returns the number of elements between lo and hi, both included.
+func (t *Tree[T]) CountRange(lo, hi T) int {
+	if t.cmp(lo, hi) > 0 {
+		return 0
+	}
+	return t.countBelow(hi, true) - t.countBelow(lo, false)
+}
+
+// countBelow returns the number of elements smaller than x, or not greater than x if inclusive is set.
+func (t *Tree[T]) countBelow(x T, inclusive bool) int {
+	count := 0
+	for n := t.root; n != nil; {
+		if c := t.cmp(x, n.values[0]); c < 0 || c == 0 && !inclusive {
+			n = n.left
+		} else {
+			count += size(n.left) + len(n.values)
+			n = n.right
+		}
+	}
+	return count
+}
+
+// Select returns the element at index k of Values(), the k-th smallest counting from 0.
+// It returns ErrIndexOutOfRange unless 0 <= k < Size().
+func (t *Tree[T]) Select(k int) (value T, err error) {
+	if k < 0 || k >= size(t.root) {
+		return value, fmt.Errorf("%w: %d", ErrIndexOutOfRange, k)
+	}
+	n := t.root
+	for {
+		switch l := size(n.left); {
+		case k < l:
+			n = n.left
+		case k < l+len(n.values):
+			return n.values[k-l], nil
+		default:
+			k -= l + len(n.values)
+			n = n.right
+		}
+	}
+}
+
+// Min returns the smallest element, the first inserted among equals.
+func (t *Tree[T]) Min() (value T, err error) {
+	if t.root == nil {
+		return value, ErrEmpty
+	}
+	return leftmost(t.root).values[0], nil
+}
+
+// Max returns the largest element, the last inserted among equals.
+func (t *Tree[T]) Max() (value T, err error) {
+	if t.root == nil {
+		return value, ErrEmpty
+	}
+	n := t.root
+	for n.right != nil {
+		n = n.right
+	}
+	return n.values[len(n.values)-1], nil
+}
+
+// Values returns all elements in ascending order, equal elements in insertion order.
+func (t *Tree[T]) Values() []T {
+	values := make([]T, 0, size(t.root))
+	var visit func(n *node[T])
+	visit = func(n *node[T]) {
+		if n == nil {
+			return
+		}
+		visit(n.left)
+		values = append(values, n.values...)
+		visit(n.right)
+	}
+	visit(t.root)
+	return values
+}
+
+// Empty returns true if the tree holds no elements.
+func (t *Tree[T]) Empty() bool {
+	return t.root == nil
+}
+
+// Size returns the number of elements, counting duplicates.
+func (t *Tree[T]) Size() int {
+	return size(t.root)
+}
+
+// Clear removes all elements.
+func (t *Tree[T]) Clear() {
+	t.root = nil
+}
@@ -0,0 +1,136 @@
+package ostree
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree(t *testing.T) {
+	tree := New(common.IntComparator)
+	assert.True(t, tree.Empty())
+	_, err := tree.Min()
+	assert.True(t, errors.Is(err, ErrEmpty))
+	_, err = tree.Select(0)
+	assert.True(t, errors.Is(err, ErrIndexOutOfRange))
+
+	for _, x := range []int{50, 20, 80, 20, 60, 10, 20, 90} {
+		tree.Insert(x)
+	}
+	assert.Equal(t, 8, tree.Size())
+	assert.Equal(t, []int{10, 20, 20, 20, 50, 60, 80, 90}, tree.Values())
+
+	assert.Equal(t, 1, tree.Rank(20))
+	assert.Equal(t, 4, tree.Rank(21))
+	assert.Equal(t, 0, tree.Rank(5))
+	assert.Equal(t, 8, tree.Rank(100))
+	assert.Equal(t, 3, tree.Count(20))
+	assert.Equal(t, 0, tree.Count(21))
+	assert.Equal(t, 5, tree.CountRange(20, 60))
+	assert.Equal(t, 0, tree.CountRange(60, 20))
+	assert.Equal(t, 0, tree.CountRange(61, 79))
+
+	v, err := tree.Select(4)
+	assert.NoError(t, err)
+	assert.Equal(t, 50, v)
+	_, err = tree.Select(8)
+	assert.True(t, errors.Is(err, ErrIndexOutOfRange))
+
+	min, _ := tree.Min()
+	max, _ := tree.Max()
+	assert.Equal(t, 10, min)
+	assert.Equal(t, 90, max)
+
+	assert.True(t, tree.Remove(20))
+	assert.Equal(t, 2, tree.Count(20))
+	assert.False(t, tree.Remove(21))
+	assert.Equal(t, []int{10, 20, 20, 50, 60, 80, 90}, tree.Values())
+
+	tree.Clear()
+	assert.True(t, tree.Empty())
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tree := New(common.IntComparator)
+	want := []int{}
+
+	for i := 0; i < 5000; i++ {
+		x := rng.Intn(200)
+		if rng.Intn(3) == 0 {
+			j := sort.SearchInts(want, x)
+			found := j < len(want) && want[j] == x
+			assert.Equal(t, found, tree.Remove(x))
+			if found {
+				want = append(want[:j], want[j+1:]...)
+			}
+		} else {
+			tree.Insert(x)
+			j := sort.SearchInts(want, x+1)
+			want = append(want[:j], append([]int{x}, want[j:]...)...)
+		}
+		assertValid(t, tree)
+
+		if i%100 != 0 {
+			continue
+		}
+		assert.Equal(t, want, tree.Values())
+		for k := range want {
+			v, err := tree.Select(k)
+			assert.NoError(t, err)
+			assert.Equal(t, want[k], v)
+		}
+		lo, hi := rng.Intn(200), rng.Intn(200)
+		assert.Equal(t, sort.SearchInts(want, x), tree.Rank(x))
+		if lo <= hi {
+			assert.Equal(t, sort.SearchInts(want, hi+1)-sort.SearchInts(want, lo), tree.CountRange(lo, hi))
+		}
+	}
+}
+
+// assertValid checks the subtree sizes and the left-leaning red-black invariants:
+// no right-leaning or consecutive red links, and the same number of black links on every root-to-leaf path.
+func assertValid(t *testing.T, tree *Tree[int]) {
+	assert.False(t, isRed(tree.root))
+	var blackHeight func(n *node[int]) int
+	blackHeight = func(n *node[int]) int {
+		if n == nil {
+			return 0
+		}
+		assert.False(t, isRed(n.right), "right-leaning red link")
+		assert.False(t, isRed(n) && isRed(n.left), "consecutive red links")
+		assert.Equal(t, size(n.left)+size(n.right)+len(n.values), n.size)
+		l, r := blackHeight(n.left), blackHeight(n.right)
+		assert.Equal(t, l, r)
+		if !n.red {
+			l++
+		}
+		return l
+	}
+	blackHeight(tree.root)
+}
+
+func TestEqualElements(t *testing.T) {
+	type score struct {
+		name   string
+		points int
+	}
+	tree := New(func(a, b score) int8 { return common.IntComparator(a.points, b.points) })
+	tree.Insert(score{"a", 10})
+	tree.Insert(score{"b", 20})
+	tree.Insert(score{"c", 10})
+	tree.Insert(score{"d", 10})
+
+	assert.Equal(t, []score{{"a", 10}, {"c", 10}, {"d", 10}, {"b", 20}}, tree.Values())
+	v, _ := tree.Select(1)
+	assert.Equal(t, "c", v.name)
+	assert.Equal(t, 3, tree.Rank(score{points: 20}))
+
+	// Remove takes the last inserted of the equal elements.
+	assert.True(t, tree.Remove(score{points: 10}))
+	assert.Equal(t, []score{{"a", 10}, {"c", 10}, {"b", 20}}, tree.Values())
+}
@@ -0,0 +1,149 @@
+package ostree
+
+type node[T any] struct {
+	values      []T // elements equal to each other, in insertion order
+	left, right *node[T]
+	size        int  // number of elements in the subtree
+	red         bool // color of the link from the parent
+}
+
+func isRed[T any](n *node[T]) bool {
+	return n != nil && n.red
+}
+
+func size[T any](n *node[T]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *node[T]) update() {
+	n.size = size(n.left) + size(n.right) + len(n.values)
+}
+
+func rotateLeft[T any](n *node[T]) *node[T] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	r.red = n.red
+	n.red = true
+	n.update()
+	r.update()
+	return r
+}
+
+func rotateRight[T any](n *node[T]) *node[T] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	l.red = n.red
+	n.red = true
+	n.update()
+	l.update()
+	return l
+}
+
+func flipColors[T any](n *node[T]) {
+	n.red = !n.red
+	n.left.red = !n.left.red
+	n.right.red = !n.right.red
+}
+
+// balance restores the left-leaning red-black invariants at n on the way up.
+func balance[T any](n *node[T]) *node[T] {
+	if isRed(n.right) && !isRed(n.left) {
+		n = rotateLeft(n)
+	}
+	if isRed(n.left) && isRed(n.left.left) {
+		n = rotateRight(n)
+	}
+	if isRed(n.left) && isRed(n.right) {
+		flipColors(n)
+	}
+	n.update()
+	return n
+}
+
+// insert adds x to the subtree of n, appending it to the node of its equals if there is one.
+func (t *Tree[T]) insert(n *node[T], x T) *node[T] {
+	if n == nil {
+		return &node[T]{values: []T{x}, size: 1, red: true}
+	}
+	switch c := t.cmp(x, n.values[0]); {
+	case c < 0:
+		n.left = t.insert(n.left, x)
+	case c > 0:
+		n.right = t.insert(n.right, x)
+	default:
+		n.values = append(n.values, x)
+	}
+	return balance(n)
+}
+
+// moveRedLeft makes n.left or one of its children red, assuming n is red and n.left and n.left.left are black.
+func moveRedLeft[T any](n *node[T]) *node[T] {
+	flipColors(n)
+	if isRed(n.right.left) {
+		n.right = rotateRight(n.right)
+		n = rotateLeft(n)
+		flipColors(n)
+	}
+	return n
+}
+
+// moveRedRight makes n.right or one of its children red, assuming n is red and n.right and n.right.left are black.
+func moveRedRight[T any](n *node[T]) *node[T] {
+	flipColors(n)
+	if isRed(n.left.left) {
+		n = rotateRight(n)
+		flipColors(n)
+	}
+	return n
+}
+
+func leftmost[T any](n *node[T]) *node[T] {
+	for n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+func removeMin[T any](n *node[T]) *node[T] {
+	if n.left == nil {
+		return nil
+	}
+	if !isRed(n.left) && !isRed(n.left.left) {
+		n = moveRedLeft(n)
+	}
+	n.left = removeMin(n.left)
+	return balance(n)
+}
+
+// remove removes the node holding x from the subtree of n, which must contain it.
+func (t *Tree[T]) remove(n *node[T], x T) *node[T] {
+	if t.cmp(x, n.values[0]) < 0 {
+		if !isRed(n.left) && !isRed(n.left.left) {
+			n = moveRedLeft(n)
+		}
+		n.left = t.remove(n.left, x)
+		return balance(n)
+	}
+
+	if isRed(n.left) {
+		n = rotateRight(n)
+	}
+	if t.cmp(x, n.values[0]) == 0 && n.right == nil {
+		return nil
+	}
+	if !isRed(n.right) && !isRed(n.right.left) {
+		n = moveRedRight(n)
+	}
+	if t.cmp(x, n.values[0]) == 0 {
+		n.values = leftmost(n.right).values
+		n.right = removeMin(n.right)
+	} else {
+		n.right = t.remove(n.right, x)
+	}
+	return balance(n)
+}
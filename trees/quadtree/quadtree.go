@@ -0,0 +1,338 @@
+// Package quadtree implements a point-region quadtree: a tree over a fixed rectangle in which every
+// node that holds too many points is split into four equal quadrants.
+//
+// Points are stored in the leaves in buckets of up to the node capacity. A leaf that overflows is
+// split unless it has reached the maximum depth, which bounds the tree height when many points
+// are close together. Removing points merges quadrants back once they fit into a single bucket.
+// Each point holds one value, so Put on an existing point replaces its value.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Quadtree
+package quadtree
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kwstars/goads/queues/priorityqueue"
+	"github.com/kwstars/goads/trees"
+	"github.com/kwstars/goads/trees/spatial"
+)
+
+var _ trees.Tree[int] = (*Tree[int, int])(nil)
+
+var (
+	ErrInvalidBounds = errors.New("invalid bounds")
+	ErrOutOfBounds   = errors.New("point out of bounds")
+)
+
+const (
+	defaultCapacity = 8
+	defaultMaxDepth = 16
+)
+
+// Item is a point with its value.
+type Item[C spatial.Coordinate, V any] struct {
+	Point spatial.Point[C]
+	Value V
+}
+
+// Neighbor is an item found by Nearest, with its squared Euclidean distance to the query point.
+type Neighbor[C spatial.Coordinate, V any] struct {
+	Item[C, V]
+	DistanceSquared float64
+}
+
+type node[C spatial.Coordinate, V any] struct {
+	bounds   spatial.Rect[C]
+	center   spatial.Point[C]
+	items    []Item[C, V]    // items of a leaf
+	children *[4]*node[C, V] // quadrants of an inner node, nil for a leaf
+	size     int             // number of items in the subtree
+}
+
+func newNode[C spatial.Coordinate, V any](bounds spatial.Rect[C]) *node[C, V] {
+	return &node[C, V]{
+		bounds: bounds,
+		center: spatial.Point[C]{
+			X: bounds.Min.X + (bounds.Max.X-bounds.Min.X)/2,
+			Y: bounds.Min.Y + (bounds.Max.Y-bounds.Min.Y)/2,
+		},
+	}
+}
+
+// quadrant returns the index of the child holding p. Points on a center line belong to the lower quadrant.
+func (n *node[C, V]) quadrant(p spatial.Point[C]) int {
+	q := 0
+	if p.X > n.center.X {
+		q |= 1
+	}
+	if p.Y > n.center.Y {
+		q |= 2
+	}
+	return q
+}
+
+// split turns a leaf into an inner node and distributes its items over the quadrants.
+// Neighboring quadrants share the center lines; quadrant decides where a point on them goes.
+func (n *node[C, V]) split() {
+	b, c := n.bounds, n.center
+	n.children = &[4]*node[C, V]{
+		newNode[C, V](spatial.Rect[C]{Min: b.Min, Max: c}),
+		newNode[C, V](spatial.Rect[C]{Min: spatial.Point[C]{X: c.X, Y: b.Min.Y}, Max: spatial.Point[C]{X: b.Max.X, Y: c.Y}}),
+		newNode[C, V](spatial.Rect[C]{Min: spatial.Point[C]{X: b.Min.X, Y: c.Y}, Max: spatial.Point[C]{X: c.X, Y: b.Max.Y}}),
+		newNode[C, V](spatial.Rect[C]{Min: c, Max: b.Max}),
+	}
+	for _, it := range n.items {
+		child := n.children[n.quadrant(it.Point)]
+		child.items = append(child.items, it)
+		child.size++
+	}
+	n.items = nil
+}
+
+// collect appends all items of the subtree to items.
+func (n *node[C, V]) collect(items []Item[C, V]) []Item[C, V] {
+	if n.children == nil {
+		return append(items, n.items...)
+	}
+	for _, child := range n.children {
+		items = child.collect(items)
+	}
+	return items
+}
+
+// Option is a function that can be passed to New to customize the Tree.
+type Option[C spatial.Coordinate, V any] func(*Tree[C, V])
+
+// WithCapacity sets the number of points a leaf holds before it is split. Values below 1 are ignored.
+func WithCapacity[C spatial.Coordinate, V any](capacity int) Option[C, V] {
+	return func(t *Tree[C, V]) {
+		if capacity >= 1 {
+			t.capacity = capacity
+		}
+	}
+}
+
+// WithMaxDepth sets the depth below which leaves are no longer split. Negative values are ignored.
+func WithMaxDepth[C spatial.Coordinate, V any](depth int) Option[C, V] {
+	return func(t *Tree[C, V]) {
+		if depth >= 0 {
+			t.maxDepth = depth
+		}
+	}
+}
+
+// Tree is a point-region quadtree.
+type Tree[C spatial.Coordinate, V any] struct {
+	root     *node[C, V]
+	capacity int
+	maxDepth int
+}
+
+// New returns an empty quadtree covering bounds, borders included.
+// It returns ErrInvalidBounds if bounds.Min is after bounds.Max.
+func New[C spatial.Coordinate, V any](bounds spatial.Rect[C], opts ...Option[C, V]) (*Tree[C, V], error) {
+	if !bounds.Valid() {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBounds, bounds)
+	}
+	t := &Tree[C, V]{
+		root:     newNode[C, V](bounds),
+		capacity: defaultCapacity,
+		maxDepth: defaultMaxDepth,
+	}
+	for _, option := range opts {
+		option(t)
+	}
+	return t, nil
+}
+
+// Bounds returns the rectangle covered by the tree.
+func (t *Tree[C, V]) Bounds() spatial.Rect[C] {
+	return t.root.bounds
+}
+
+// Put stores value at point, replacing the value of an existing point.
+// It returns ErrOutOfBounds if point lies outside the tree bounds.
+func (t *Tree[C, V]) Put(point spatial.Point[C], value V) error {
+	if !t.root.bounds.ContainsPoint(point) {
+		return fmt.Errorf("%w: %v", ErrOutOfBounds, point)
+	}
+	if it := t.find(point); it != nil {
+		it.Value = value
+		return nil
+	}
+
+	n, depth := t.root, 0
+	for {
+		n.size++
+		if n.children == nil {
+			break
+		}
+		n = n.children[n.quadrant(point)]
+		depth++
+	}
+	n.items = append(n.items, Item[C, V]{Point: point, Value: value})
+	// A split may leave every point in one quadrant, so keep splitting while the bucket overflows.
+	for len(n.items) > t.capacity && depth < t.maxDepth {
+		n.split()
+		n = n.children[n.quadrant(point)]
+		depth++
+	}
+	return nil
+}
+
+// find returns the stored item at point, or nil.
+func (t *Tree[C, V]) find(point spatial.Point[C]) *Item[C, V] {
+	if !t.root.bounds.ContainsPoint(point) {
+		return nil
+	}
+	n := t.root
+	for n.children != nil {
+		n = n.children[n.quadrant(point)]
+	}
+	for i := range n.items {
+		if n.items[i].Point == point {
+			return &n.items[i]
+		}
+	}
+	return nil
+}
+
+// Get returns the value stored at point.
+func (t *Tree[C, V]) Get(point spatial.Point[C]) (value V, found bool) {
+	if it := t.find(point); it != nil {
+		return it.Value, true
+	}
+	return value, false
+}
+
+// Remove removes point and its value. It returns false if the point is not stored.
+func (t *Tree[C, V]) Remove(point spatial.Point[C]) bool {
+	if t.find(point) == nil {
+		return false
+	}
+	n := t.root
+	for {
+		n.size--
+		if n.children == nil {
+			break
+		}
+		if n.size <= t.capacity {
+			// The subtree now fits into one bucket: collapse it into a leaf.
+			items := n.collect(nil)
+			n.children = nil
+			n.items = removeItem(items, point)
+			return true
+		}
+		n = n.children[n.quadrant(point)]
+	}
+	n.items = removeItem(n.items, point)
+	return true
+}
+
+func removeItem[C spatial.Coordinate, V any](items []Item[C, V], point spatial.Point[C]) []Item[C, V] {
+	for i := range items {
+		if items[i].Point == point {
+			last := len(items) - 1
+			items[i] = items[last]
+			items[last] = Item[C, V]{}
+			return items[:last]
+		}
+	}
+	return items
+}
+
+// Query returns the items whose points lie in r, borders included.
+func (t *Tree[C, V]) Query(r spatial.Rect[C]) []Item[C, V] {
+	var found []Item[C, V]
+	var visit func(n *node[C, V])
+	visit = func(n *node[C, V]) {
+		if n.size == 0 || !n.bounds.Intersects(r) {
+			return
+		}
+		if r.Contains(n.bounds) {
+			found = n.collect(found)
+			return
+		}
+		if n.children == nil {
+			for _, it := range n.items {
+				if r.ContainsPoint(it.Point) {
+					found = append(found, it)
+				}
+			}
+			return
+		}
+		for _, child := range n.children {
+			visit(child)
+		}
+	}
+	visit(t.root)
+	return found
+}
+
+// Items returns all items in no particular order.
+func (t *Tree[C, V]) Items() []Item[C, V] {
+	return t.root.collect(make([]Item[C, V], 0, t.root.size))
+}
+
+// entry is a node or an item queued by Nearest, keyed by its distance from the query point.
+type entry[C spatial.Coordinate, V any] struct {
+	node     *node[C, V]
+	item     Item[C, V]
+	distance float64
+}
+
+// nearestFirst makes priorityqueue.Queue return the entry with the smallest distance first.
+func nearestFirst[C spatial.Coordinate, V any](a, b entry[C, V]) int8 {
+	if a.distance < b.distance {
+		return 1
+	} else if a.distance > b.distance {
+		return -1
+	}
+	return 0
+}
+
+// Nearest returns the n items nearest to point, nearest first. Ties are broken arbitrarily.
+// The search is best-first: nodes and items share one priority queue ordered by distance, so an item
+// reaches the front only when no unexplored node can hold anything nearer. The point may lie
+// outside the tree bounds.
+func (t *Tree[C, V]) Nearest(point spatial.Point[C], n int) []Neighbor[C, V] {
+	var found []Neighbor[C, V]
+	pq := priorityqueue.New(nearestFirst[C, V])
+	pq.Enqueue(entry[C, V]{node: t.root, distance: t.root.bounds.DistanceSquared(point)})
+	for len(found) < n && !pq.Empty() {
+		e, _ := pq.Dequeue()
+		switch {
+		case e.node == nil:
+			found = append(found, Neighbor[C, V]{Item: e.item, DistanceSquared: e.distance})
+		case e.node.children == nil:
+			for _, it := range e.node.items {
+				pq.Enqueue(entry[C, V]{item: it, distance: it.Point.DistanceSquared(point)})
+			}
+		default:
+			for _, child := range e.node.children {
+				if child.size > 0 {
+					pq.Enqueue(entry[C, V]{node: child, distance: child.bounds.DistanceSquared(point)})
+				}
+			}
+		}
+	}
+	return found
+}
+
+// Empty returns true if the tree holds no points.
+func (t *Tree[C, V]) Empty() bool {
+	return t.root.size == 0
+}
+
+// Size returns the number of points.
+func (t *Tree[C, V]) Size() int {
+	return t.root.size
+}
+
+// Clear removes all points, keeping the bounds.
+func (t *Tree[C, V]) Clear() {
+	t.root = newNode[C, V](t.root.bounds)
+}
@@ -0,0 +1,123 @@
+package quadtree
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/kwstars/goads/trees/spatial"
+	"github.com/stretchr/testify/assert"
+)
+
+type point = spatial.Point[int]
+
+func TestTree(t *testing.T) {
+	tree, err := New[int, string](spatial.NewRect(point{}, point{X: 100, Y: 100}), WithCapacity[int, string](2))
+	assert.NoError(t, err)
+	assert.True(t, tree.Empty())
+
+	assert.NoError(t, tree.Put(point{X: 10, Y: 10}, "a"))
+	assert.NoError(t, tree.Put(point{X: 90, Y: 10}, "b"))
+	assert.NoError(t, tree.Put(point{X: 10, Y: 90}, "c"))
+	assert.NoError(t, tree.Put(point{X: 90, Y: 90}, "d"))
+	assert.NoError(t, tree.Put(point{X: 50, Y: 50}, "e"))
+	assert.NoError(t, tree.Put(point{X: 100, Y: 100}, "f"))
+	assert.Equal(t, 6, tree.Size())
+
+	assert.True(t, errors.Is(tree.Put(point{X: 101, Y: 0}, "x"), ErrOutOfBounds))
+	_, err = New[int, string](spatial.Rect[int]{Max: point{X: -1}})
+	assert.True(t, errors.Is(err, ErrInvalidBounds))
+
+	// Put on an existing point replaces the value.
+	assert.NoError(t, tree.Put(point{X: 50, Y: 50}, "E"))
+	assert.Equal(t, 6, tree.Size())
+	v, ok := tree.Get(point{X: 50, Y: 50})
+	assert.True(t, ok)
+	assert.Equal(t, "E", v)
+	_, ok = tree.Get(point{X: 50, Y: 51})
+	assert.False(t, ok)
+
+	assert.ElementsMatch(t, []string{"a", "E"}, values(tree.Query(spatial.NewRect(point{}, point{X: 50, Y: 50}))))
+	assert.ElementsMatch(t, []string{"d", "f"}, values(tree.Query(spatial.NewRect(point{X: 60, Y: 60}, point{X: 200, Y: 200}))))
+
+	near := tree.Nearest(point{X: 95, Y: 95}, 2)
+	assert.Len(t, near, 2)
+	assert.Equal(t, "d", near[0].Value)
+	assert.Equal(t, 50.0, near[0].DistanceSquared)
+	assert.Equal(t, "f", near[1].Value)
+
+	assert.True(t, tree.Remove(point{X: 90, Y: 90}))
+	assert.False(t, tree.Remove(point{X: 90, Y: 90}))
+	assert.Equal(t, 5, tree.Size())
+	assert.ElementsMatch(t, []string{"a", "b", "c", "E", "f"}, values(tree.Items()))
+
+	tree.Clear()
+	assert.True(t, tree.Empty())
+	assert.Empty(t, tree.Nearest(point{}, 1))
+}
+
+func TestMaxDepth(t *testing.T) {
+	// Points closer than the smallest quadrant stay together in one oversized leaf.
+	tree, _ := New[float64, int](spatial.NewRect(spatial.Point[float64]{}, spatial.Point[float64]{X: 1, Y: 1}),
+		WithCapacity[float64, int](1), WithMaxDepth[float64, int](3))
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, tree.Put(spatial.Point[float64]{X: float64(i) * 1e-3, Y: 0}, i))
+	}
+	assert.Equal(t, 10, tree.Size())
+	assert.Len(t, tree.Query(tree.Bounds()), 10)
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := func() point {
+		return point{X: rng.Intn(1001) - 500, Y: rng.Intn(1001) - 500}
+	}
+	tree, _ := New[int, int](spatial.NewRect(point{X: -500, Y: -500}, point{X: 500, Y: 500}), WithCapacity[int, int](4))
+	stored := make(map[point]int)
+
+	for i := 0; i < 2000; i++ {
+		p := random()
+		if rng.Intn(3) == 0 {
+			_, ok := stored[p]
+			assert.Equal(t, ok, tree.Remove(p))
+			delete(stored, p)
+		} else {
+			assert.NoError(t, tree.Put(p, i))
+			stored[p] = i
+		}
+		assert.Equal(t, len(stored), tree.Size())
+
+		if i%50 != 0 {
+			continue
+		}
+		r := spatial.NewRect(random(), random())
+		var want []int
+		for p, v := range stored {
+			if r.ContainsPoint(p) {
+				want = append(want, v)
+			}
+		}
+		assert.ElementsMatch(t, want, values(tree.Query(r)))
+
+		q := random()
+		var dists []float64
+		for p := range stored {
+			dists = append(dists, p.DistanceSquared(q))
+		}
+		sort.Float64s(dists)
+		near := tree.Nearest(q, 5)
+		for j, n := range near {
+			assert.Equal(t, dists[j], n.DistanceSquared)
+			assert.Equal(t, stored[n.Point], n.Value)
+		}
+	}
+}
+
+func values[V any](items []Item[int, V]) []V {
+	var vs []V
+	for _, it := range items {
+		vs = append(vs, it.Value)
+	}
+	return vs
+}
@@ -0,0 +1,471 @@
+// Package rtree implements an R-tree: a balanced tree of bounding rectangles indexing rectangles
+// (or points, as rectangles of zero size) for intersection and nearest-neighbor queries.
+//
+// Every node holds between a minimum and a maximum number of entries and all leaves are at the same
+// depth. Insertion follows the R*-tree: a leaf is chosen by least overlap enlargement, inner nodes by
+// least area enlargement, and overflowing nodes are split along the axis with the smallest total margin
+// at the distribution with the least overlap. Forced reinsertion is not performed. Removal condenses
+// underfull nodes by reinserting their entries.
+//
+// The same rectangle may be stored several times with different values.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/R-tree, https://en.wikipedia.org/wiki/R*-tree
+package rtree
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kwstars/goads/queues/priorityqueue"
+	"github.com/kwstars/goads/sorting"
+	"github.com/kwstars/goads/trees"
+	"github.com/kwstars/goads/trees/spatial"
+)
+
+var _ trees.Tree[int] = (*Tree[int, int])(nil)
+
+var ErrInvalidRect = errors.New("invalid rectangle")
+
+const (
+	defaultMaxEntries = 16
+	minFillRatio      = 0.4 // minimum node fill relative to the maximum, as recommended for the R*-tree
+)
+
+// Item is a rectangle with its value.
+type Item[C spatial.Coordinate, V any] struct {
+	Rect  spatial.Rect[C]
+	Value V
+}
+
+// Neighbor is an item found by Nearest, with the squared distance from the query point to its rectangle.
+type Neighbor[C spatial.Coordinate, V any] struct {
+	Item[C, V]
+	DistanceSquared float64
+}
+
+// entry is a child of a node: a subtree in an inner node, an item in a leaf.
+type entry[C spatial.Coordinate, V any] struct {
+	rect  spatial.Rect[C]
+	child *node[C, V]
+	value V
+}
+
+type node[C spatial.Coordinate, V any] struct {
+	leaf    bool
+	entries []entry[C, V]
+}
+
+// bounds returns the smallest rectangle containing all entries of n, which must not be empty.
+func (n *node[C, V]) bounds() spatial.Rect[C] {
+	r := n.entries[0].rect
+	for _, e := range n.entries[1:] {
+		r = r.Union(e.rect)
+	}
+	return r
+}
+
+// Option is a function that can be passed to New to customize the Tree.
+type Option[C spatial.Coordinate, V any] func(*Tree[C, V])
+
+// WithMaxEntries sets the maximum number of entries per node. Values below 4 are ignored.
+func WithMaxEntries[C spatial.Coordinate, V any](max int) Option[C, V] {
+	return func(t *Tree[C, V]) {
+		if max >= 4 {
+			t.maxEntries = max
+		}
+	}
+}
+
+// Tree is an R-tree.
+type Tree[C spatial.Coordinate, V any] struct {
+	root       *node[C, V]
+	height     int // number of levels; the leaves are at level 1
+	size       int
+	maxEntries int
+	minEntries int
+}
+
+// New returns an empty R-tree.
+func New[C spatial.Coordinate, V any](opts ...Option[C, V]) *Tree[C, V] {
+	t := &Tree[C, V]{maxEntries: defaultMaxEntries}
+	for _, option := range opts {
+		option(t)
+	}
+	t.minEntries = int(float64(t.maxEntries) * minFillRatio)
+	if t.minEntries < 2 {
+		t.minEntries = 2
+	}
+	t.Clear()
+	return t
+}
+
+// Insert adds rect with its value. It returns ErrInvalidRect if rect.Min is after rect.Max.
+func (t *Tree[C, V]) Insert(rect spatial.Rect[C], value V) error {
+	if !rect.Valid() {
+		return fmt.Errorf("%w: %v", ErrInvalidRect, rect)
+	}
+	t.insert(entry[C, V]{rect: rect, value: value}, 1)
+	t.size++
+	return nil
+}
+
+// insert places e in a node at the given level, splitting nodes up to the root as needed.
+func (t *Tree[C, V]) insert(e entry[C, V], level int) {
+	// Descend to the target level, remembering the path so that bounds can be updated on the way back.
+	path := []*node[C, V]{t.root}
+	indices := []int{}
+	n := t.root
+	for l := t.height; l > level; l-- {
+		i := chooseSubtree(n, e.rect, l == 2)
+		indices = append(indices, i)
+		n = n.entries[i].child
+		path = append(path, n)
+	}
+
+	n.entries = append(n.entries, e)
+	var sibling *node[C, V]
+	if len(n.entries) > t.maxEntries {
+		sibling = t.split(n)
+	}
+
+	for d := len(path) - 2; d >= 0; d-- {
+		parent, i := path[d], indices[d]
+		parent.entries[i].rect = parent.entries[i].child.bounds()
+		if sibling != nil {
+			parent.entries = append(parent.entries, entry[C, V]{rect: sibling.bounds(), child: sibling})
+			sibling = nil
+			if len(parent.entries) > t.maxEntries {
+				sibling = t.split(parent)
+			}
+		}
+	}
+
+	if sibling != nil {
+		old := t.root
+		t.root = &node[C, V]{entries: []entry[C, V]{
+			{rect: old.bounds(), child: old},
+			{rect: sibling.bounds(), child: sibling},
+		}}
+		t.height++
+	}
+}
+
+// chooseSubtree returns the index of the entry of n that should receive r.
+// Above the leaves it minimizes the overlap enlargement with the sibling entries, elsewhere the area
+// enlargement; ties go to the smaller area.
+func chooseSubtree[C spatial.Coordinate, V any](n *node[C, V], r spatial.Rect[C], leafParent bool) int {
+	best := 0
+	var bestOverlap, bestGrowth, bestArea float64
+	for i, e := range n.entries {
+		grown := e.rect.Union(r)
+		area := e.rect.Area()
+		growth := grown.Area() - area
+		var overlap float64
+		if leafParent {
+			for j, other := range n.entries {
+				if j != i {
+					overlap += overlapArea(grown, other.rect) - overlapArea(e.rect, other.rect)
+				}
+			}
+		}
+		if i == 0 || overlap < bestOverlap ||
+			overlap == bestOverlap && (growth < bestGrowth || growth == bestGrowth && area < bestArea) {
+			best, bestOverlap, bestGrowth, bestArea = i, overlap, growth, area
+		}
+	}
+	return best
+}
+
+// split moves part of the entries of an overflowing node n into a new sibling node and returns it.
+//
+// As in the R*-tree, the entries are sorted along each axis by their lower and by their upper edge,
+// and every distribution into two groups of at least minEntries is considered. The split axis is
+// the one with the smallest sum of group margins; on it the distribution with the least overlap
+// between the groups wins, ties going to the smaller total area.
+func (t *Tree[C, V]) split(n *node[C, V]) *node[C, V] {
+	sorts := [2][2]func(a, b entry[C, V]) int8{
+		{byMinX[C, V], byMaxX[C, V]},
+		{byMinY[C, V], byMaxY[C, V]},
+	}
+
+	bestAxis, bestMargin := 0, 0.0
+	for axis, cmps := range sorts {
+		var margin float64
+		for _, cmp := range cmps {
+			sorting.InsertionSort(n.entries, cmp)
+			t.eachDistribution(n.entries, func(k int, left, right spatial.Rect[C]) {
+				margin += left.Margin() + right.Margin()
+			})
+		}
+		if axis == 0 || margin < bestMargin {
+			bestAxis, bestMargin = axis, margin
+		}
+	}
+
+	bestSort, bestK := 0, 0
+	var bestOverlap, bestArea float64
+	first := true
+	for s, cmp := range sorts[bestAxis] {
+		sorting.InsertionSort(n.entries, cmp)
+		t.eachDistribution(n.entries, func(k int, left, right spatial.Rect[C]) {
+			overlap, area := overlapArea(left, right), left.Area()+right.Area()
+			if first || overlap < bestOverlap || overlap == bestOverlap && area < bestArea {
+				bestSort, bestK, bestOverlap, bestArea, first = s, k, overlap, area, false
+			}
+		})
+	}
+	sorting.InsertionSort(n.entries, sorts[bestAxis][bestSort])
+
+	sibling := &node[C, V]{leaf: n.leaf, entries: make([]entry[C, V], len(n.entries)-bestK, t.maxEntries+1)}
+	copy(sibling.entries, n.entries[bestK:])
+	for i := bestK; i < len(n.entries); i++ {
+		n.entries[i] = entry[C, V]{}
+	}
+	n.entries = n.entries[:bestK]
+	return sibling
+}
+
+// eachDistribution calls fn with the bounds of both groups for every split of entries into
+// entries[:k] and entries[k:] where both groups hold at least minEntries.
+func (t *Tree[C, V]) eachDistribution(entries []entry[C, V], fn func(k int, left, right spatial.Rect[C])) {
+	// suffix[i] is the bounds of entries[i:].
+	suffix := make([]spatial.Rect[C], len(entries))
+	suffix[len(entries)-1] = entries[len(entries)-1].rect
+	for i := len(entries) - 2; i >= 0; i-- {
+		suffix[i] = suffix[i+1].Union(entries[i].rect)
+	}
+	left := entries[0].rect
+	for k := 1; k <= len(entries)-t.minEntries; k++ {
+		if k >= t.minEntries {
+			fn(k, left, suffix[k])
+		}
+		left = left.Union(entries[k].rect)
+	}
+}
+
+// Remove removes one item with the given rectangle for which match returns true.
+// A nil match removes any item with that rectangle. It returns false if no item was removed.
+func (t *Tree[C, V]) Remove(rect spatial.Rect[C], match func(value V) bool) bool {
+	path, indices, found := t.findLeaf(t.root, rect, match, nil, nil)
+	if !found {
+		return false
+	}
+	leaf := path[len(path)-1]
+	i := indices[len(indices)-1]
+	last := len(leaf.entries) - 1
+	leaf.entries[i] = leaf.entries[last]
+	leaf.entries[last] = entry[C, V]{}
+	leaf.entries = leaf.entries[:last]
+	t.size--
+	t.condense(path, indices[:len(indices)-1])
+	return true
+}
+
+// findLeaf returns the path to the leaf holding a matching item, and the entry index taken at each node.
+func (t *Tree[C, V]) findLeaf(n *node[C, V], rect spatial.Rect[C], match func(V) bool, path []*node[C, V], indices []int) ([]*node[C, V], []int, bool) {
+	path = append(path, n)
+	for i, e := range n.entries {
+		if !e.rect.Contains(rect) {
+			continue
+		}
+		if n.leaf {
+			if e.rect == rect && (match == nil || match(e.value)) {
+				return path, append(indices, i), true
+			}
+			continue
+		}
+		if p, idx, ok := t.findLeaf(e.child, rect, match, path, append(indices, i)); ok {
+			return p, idx, true
+		}
+	}
+	return nil, nil, false
+}
+
+// condense walks from the leaf at the end of path to the root, dropping underfull nodes and refreshing
+// bounds, then reinserts the entries of the dropped nodes at their original level.
+func (t *Tree[C, V]) condense(path []*node[C, V], indices []int) {
+	type orphan struct {
+		e     entry[C, V]
+		level int
+	}
+	var orphans []orphan
+	for d := len(path) - 1; d > 0; d-- {
+		n, parent, i := path[d], path[d-1], indices[d-1]
+		level := len(path) - d
+		if len(n.entries) < t.minEntries {
+			for _, e := range n.entries {
+				orphans = append(orphans, orphan{e: e, level: level})
+			}
+			last := len(parent.entries) - 1
+			parent.entries[i] = parent.entries[last]
+			parent.entries[last] = entry[C, V]{}
+			parent.entries = parent.entries[:last]
+		} else {
+			parent.entries[i].rect = n.bounds()
+		}
+	}
+
+	for _, o := range orphans {
+		t.insert(o.e, o.level)
+	}
+	// Shorten the tree while the root is an inner node with a single child.
+	for !t.root.leaf && len(t.root.entries) == 1 {
+		t.root = t.root.entries[0].child
+		t.height--
+	}
+}
+
+// Search returns the items whose rectangles intersect r.
+func (t *Tree[C, V]) Search(r spatial.Rect[C]) []Item[C, V] {
+	var found []Item[C, V]
+	t.Each(r, func(it Item[C, V]) bool {
+		found = append(found, it)
+		return true
+	})
+	return found
+}
+
+// Each calls fn for each item whose rectangle intersects r. If fn returns false, Each stops the iteration.
+func (t *Tree[C, V]) Each(r spatial.Rect[C], fn func(it Item[C, V]) bool) {
+	var visit func(n *node[C, V]) bool
+	visit = func(n *node[C, V]) bool {
+		for _, e := range n.entries {
+			if !e.rect.Intersects(r) {
+				continue
+			}
+			if n.leaf {
+				if !fn(Item[C, V]{Rect: e.rect, Value: e.value}) {
+					return false
+				}
+			} else if !visit(e.child) {
+				return false
+			}
+		}
+		return true
+	}
+	visit(t.root)
+}
+
+// Items returns all items in no particular order.
+func (t *Tree[C, V]) Items() []Item[C, V] {
+	var items []Item[C, V]
+	var visit func(n *node[C, V])
+	visit = func(n *node[C, V]) {
+		for _, e := range n.entries {
+			if n.leaf {
+				items = append(items, Item[C, V]{Rect: e.rect, Value: e.value})
+			} else {
+				visit(e.child)
+			}
+		}
+	}
+	visit(t.root)
+	return items
+}
+
+// queued is an entry queued by Nearest, keyed by its distance from the query point.
+type queued[C spatial.Coordinate, V any] struct {
+	entry    entry[C, V]
+	leaf     bool // entry is an item
+	distance float64
+}
+
+// nearestFirst makes priorityqueue.Queue return the entry with the smallest distance first.
+func nearestFirst[C spatial.Coordinate, V any](a, b queued[C, V]) int8 {
+	if a.distance < b.distance {
+		return 1
+	} else if a.distance > b.distance {
+		return -1
+	}
+	return 0
+}
+
+// Nearest returns the n items whose rectangles are nearest to point, nearest first.
+// The distance to a rectangle is 0 if it contains point. Ties are broken arbitrarily.
+// The search is best-first over one priority queue holding both nodes and items.
+func (t *Tree[C, V]) Nearest(point spatial.Point[C], n int) []Neighbor[C, V] {
+	var found []Neighbor[C, V]
+	pq := priorityqueue.New(nearestFirst[C, V])
+	enqueue := func(nd *node[C, V]) {
+		for _, e := range nd.entries {
+			pq.Enqueue(queued[C, V]{entry: e, leaf: nd.leaf, distance: e.rect.DistanceSquared(point)})
+		}
+	}
+	enqueue(t.root)
+	for len(found) < n && !pq.Empty() {
+		q, _ := pq.Dequeue()
+		if q.leaf {
+			found = append(found, Neighbor[C, V]{
+				Item:            Item[C, V]{Rect: q.entry.rect, Value: q.entry.value},
+				DistanceSquared: q.distance,
+			})
+		} else {
+			enqueue(q.entry.child)
+		}
+	}
+	return found
+}
+
+// Bounds returns the smallest rectangle containing all items, and false if the tree is empty.
+func (t *Tree[C, V]) Bounds() (spatial.Rect[C], bool) {
+	if t.size == 0 {
+		return spatial.Rect[C]{}, false
+	}
+	return t.root.bounds(), true
+}
+
+// Height returns the number of levels of the tree.
+func (t *Tree[C, V]) Height() int {
+	return t.height
+}
+
+// Empty returns true if the tree holds no items.
+func (t *Tree[C, V]) Empty() bool {
+	return t.size == 0
+}
+
+// Size returns the number of items.
+func (t *Tree[C, V]) Size() int {
+	return t.size
+}
+
+// Clear removes all items.
+func (t *Tree[C, V]) Clear() {
+	t.root = &node[C, V]{leaf: true}
+	t.height = 1
+	t.size = 0
+}
+
+func overlapArea[C spatial.Coordinate](a, b spatial.Rect[C]) float64 {
+	if r, ok := a.Intersection(b); ok {
+		return r.Area()
+	}
+	return 0
+}
+
+func byMinX[C spatial.Coordinate, V any](a, b entry[C, V]) int8 {
+	return compare(a.rect.Min.X, b.rect.Min.X)
+}
+
+func byMaxX[C spatial.Coordinate, V any](a, b entry[C, V]) int8 {
+	return compare(a.rect.Max.X, b.rect.Max.X)
+}
+
+func byMinY[C spatial.Coordinate, V any](a, b entry[C, V]) int8 {
+	return compare(a.rect.Min.Y, b.rect.Min.Y)
+}
+
+func byMaxY[C spatial.Coordinate, V any](a, b entry[C, V]) int8 {
+	return compare(a.rect.Max.Y, b.rect.Max.Y)
+}
+
+func compare[T spatial.Coordinate](a, b T) int8 {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
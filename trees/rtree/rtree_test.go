@@ -0,0 +1,152 @@
+package rtree
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/kwstars/goads/trees/spatial"
+	"github.com/stretchr/testify/assert"
+)
+
+type point = spatial.Point[int]
+
+func rect(x1, y1, x2, y2 int) spatial.Rect[int] {
+	return spatial.NewRect(point{X: x1, Y: y1}, point{X: x2, Y: y2})
+}
+
+func TestTree(t *testing.T) {
+	tree := New[int, string]()
+	assert.True(t, tree.Empty())
+	_, ok := tree.Bounds()
+	assert.False(t, ok)
+
+	assert.NoError(t, tree.Insert(rect(0, 0, 10, 10), "a"))
+	assert.NoError(t, tree.Insert(rect(5, 5, 15, 15), "b"))
+	assert.NoError(t, tree.Insert(rect(20, 20, 30, 30), "c"))
+	assert.NoError(t, tree.Insert(rect(25, 0, 25, 0), "d"))
+	assert.NoError(t, tree.Insert(rect(0, 0, 10, 10), "e"))
+	assert.Equal(t, 5, tree.Size())
+	assert.True(t, errors.Is(tree.Insert(spatial.Rect[int]{Min: point{X: 1}}, "x"), ErrInvalidRect))
+
+	assert.ElementsMatch(t, []string{"a", "b", "e"}, values(tree.Search(rect(8, 8, 9, 9))))
+	assert.ElementsMatch(t, []string{"b", "c"}, values(tree.Search(rect(15, 15, 20, 20))))
+	assert.Empty(t, tree.Search(rect(16, 16, 19, 19)))
+
+	near := tree.Nearest(point{X: 25, Y: 5}, 2)
+	assert.Equal(t, "d", near[0].Value)
+	assert.Equal(t, 25.0, near[0].DistanceSquared)
+	assert.Equal(t, "b", near[1].Value)
+	assert.Equal(t, 100.0, near[1].DistanceSquared)
+
+	bounds, ok := tree.Bounds()
+	assert.True(t, ok)
+	assert.Equal(t, rect(0, 0, 30, 30), bounds)
+
+	// Remove picks the item by rectangle and value.
+	assert.True(t, tree.Remove(rect(0, 0, 10, 10), func(v string) bool { return v == "e" }))
+	assert.False(t, tree.Remove(rect(0, 0, 10, 10), func(v string) bool { return v == "e" }))
+	assert.ElementsMatch(t, []string{"a"}, values(tree.Search(rect(1, 1, 2, 2))))
+	assert.True(t, tree.Remove(rect(20, 20, 30, 30), nil))
+	assert.ElementsMatch(t, []string{"a", "b", "d"}, values(tree.Items()))
+
+	// Stop early.
+	var seen int
+	tree.Each(rect(0, 0, 100, 100), func(Item[int, string]) bool {
+		seen++
+		return false
+	})
+	assert.Equal(t, 1, seen)
+
+	tree.Clear()
+	assert.True(t, tree.Empty())
+	assert.Equal(t, 1, tree.Height())
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := func() spatial.Rect[int] {
+		x, y := rng.Intn(1000), rng.Intn(1000)
+		return rect(x, y, x+rng.Intn(30), y+rng.Intn(30))
+	}
+	tree := New[int, int](WithMaxEntries[int, int](6))
+	stored := make(map[int]spatial.Rect[int])
+
+	check := func() {
+		assert.Equal(t, len(stored), tree.Size())
+		assertValid(t, tree)
+
+		r := random()
+		r.Max.X += 100
+		var want []int
+		for v, s := range stored {
+			if s.Intersects(r) {
+				want = append(want, v)
+			}
+		}
+		assert.ElementsMatch(t, want, values(tree.Search(r)))
+
+		q := point{X: rng.Intn(1000), Y: rng.Intn(1000)}
+		var dists []float64
+		for _, s := range stored {
+			dists = append(dists, s.DistanceSquared(q))
+		}
+		sort.Float64s(dists)
+		for j, n := range tree.Nearest(q, 5) {
+			assert.Equal(t, dists[j], n.DistanceSquared)
+			assert.Equal(t, stored[n.Value], n.Rect)
+		}
+	}
+
+	for i := 0; i < 1500; i++ {
+		r := random()
+		assert.NoError(t, tree.Insert(r, i))
+		stored[i] = r
+		if i%100 == 0 {
+			check()
+		}
+	}
+	assert.Greater(t, tree.Height(), 2)
+
+	for v, r := range stored {
+		if rng.Intn(4) != 0 {
+			v := v
+			assert.True(t, tree.Remove(r, func(x int) bool { return x == v }))
+			delete(stored, v)
+			if len(stored)%100 == 0 {
+				check()
+			}
+		}
+	}
+	check()
+}
+
+// assertValid checks the R-tree invariants: every entry rectangle is the exact bounds of its child,
+// nodes other than the root hold between minEntries and maxEntries entries, and all leaves are at the same depth.
+func assertValid[V any](t *testing.T, tree *Tree[int, V]) {
+	var visit func(n *node[int, V], level int)
+	visit = func(n *node[int, V], level int) {
+		assert.Equal(t, level == 1, n.leaf)
+		assert.LessOrEqual(t, len(n.entries), tree.maxEntries)
+		if n != tree.root {
+			assert.GreaterOrEqual(t, len(n.entries), tree.minEntries)
+		}
+		if n.leaf {
+			return
+		}
+		for _, e := range n.entries {
+			assert.Equal(t, e.child.bounds(), e.rect)
+			visit(e.child, level-1)
+		}
+	}
+	visit(tree.root, tree.height)
+}
+
+func values[V any](items []Item[int, V]) []V {
+	var vs []V
+	for _, it := range items {
+		vs = append(vs, it.Value)
+	}
+	return vs
+}
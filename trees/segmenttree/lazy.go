@@ -0,0 +1,167 @@
+package segmenttree
+
+import (
+	"fmt"
+
+	"github.com/kwstars/goads/trees"
+)
+
+var _ trees.Tree[int] = (*LazyTree[int, int])(nil)
+
+// LazyTree is a segment tree with range updates and range queries, both O(log n).
+// An update covering a whole node is recorded at the node and only pushed down to its
+// children when a later operation needs to look inside it (lazy propagation).
+//
+// Updates of type U act on values through apply, which must give the combined value of a range of
+// size positions after the update from their combined value before it, and must distribute over
+// combine. compose merges two pending updates into one.
+type LazyTree[T, U any] struct {
+	n        int
+	values   []T    // values[node] is the combined value of the node's range
+	updates  []U    // updates[node] is waiting to be pushed down to the node's children
+	pending  []bool // pending[node] is true if updates[node] is set
+	identity T
+	combine  func(a, b T) T
+	apply    func(value T, update U, size int) T
+	compose  func(older, newer U) U
+}
+
+// NewLazy returns a lazy segment tree over a copy of values.
+// combine must be associative with identity as its identity element.
+func NewLazy[T, U any](
+	values []T,
+	identity T,
+	combine func(a, b T) T,
+	apply func(value T, update U, size int) T,
+	compose func(older, newer U) U,
+) *LazyTree[T, U] {
+	n := len(values)
+	t := &LazyTree[T, U]{
+		n:        n,
+		values:   make([]T, 4*n),
+		updates:  make([]U, 4*n),
+		pending:  make([]bool, 4*n),
+		identity: identity,
+		combine:  combine,
+		apply:    apply,
+		compose:  compose,
+	}
+	if n > 0 {
+		t.build(1, 0, n, values)
+	}
+	return t
+}
+
+func (t *LazyTree[T, U]) build(node, lo, hi int, values []T) {
+	if hi-lo == 1 {
+		t.values[node] = values[lo]
+		return
+	}
+	mid := lo + (hi-lo)/2
+	t.build(2*node, lo, mid, values)
+	t.build(2*node+1, mid, hi, values)
+	t.values[node] = t.combine(t.values[2*node], t.values[2*node+1])
+}
+
+// applyTo applies an update to the node covering [lo, hi), recording it for the children.
+func (t *LazyTree[T, U]) applyTo(node, lo, hi int, u U) {
+	t.values[node] = t.apply(t.values[node], u, hi-lo)
+	if hi-lo > 1 {
+		if t.pending[node] {
+			t.updates[node] = t.compose(t.updates[node], u)
+		} else {
+			t.updates[node] = u
+			t.pending[node] = true
+		}
+	}
+}
+
+// pushDown hands the pending update of a node on to its children.
+func (t *LazyTree[T, U]) pushDown(node, lo, mid, hi int) {
+	if !t.pending[node] {
+		return
+	}
+	t.applyTo(2*node, lo, mid, t.updates[node])
+	t.applyTo(2*node+1, mid, hi, t.updates[node])
+	var zero U
+	t.updates[node] = zero
+	t.pending[node] = false
+}
+
+// Update applies u to every position from lo to hi-1.
+func (t *LazyTree[T, U]) Update(lo, hi int, u U) error {
+	if err := checkRange(lo, hi, t.n); err != nil {
+		return err
+	}
+	if lo < hi {
+		t.update(1, 0, t.n, lo, hi, u)
+	}
+	return nil
+}
+
+func (t *LazyTree[T, U]) update(node, nodeLo, nodeHi, lo, hi int, u U) {
+	if lo <= nodeLo && nodeHi <= hi {
+		t.applyTo(node, nodeLo, nodeHi, u)
+		return
+	}
+	mid := nodeLo + (nodeHi-nodeLo)/2
+	t.pushDown(node, nodeLo, mid, nodeHi)
+	if lo < mid {
+		t.update(2*node, nodeLo, mid, lo, hi, u)
+	}
+	if hi > mid {
+		t.update(2*node+1, mid, nodeHi, lo, hi, u)
+	}
+	t.values[node] = t.combine(t.values[2*node], t.values[2*node+1])
+}
+
+// Query combines the values at positions lo to hi-1 in order. An empty range yields the identity.
+func (t *LazyTree[T, U]) Query(lo, hi int) (T, error) {
+	if err := checkRange(lo, hi, t.n); err != nil {
+		return t.identity, err
+	}
+	if lo == hi {
+		return t.identity, nil
+	}
+	return t.query(1, 0, t.n, lo, hi), nil
+}
+
+func (t *LazyTree[T, U]) query(node, nodeLo, nodeHi, lo, hi int) T {
+	if lo <= nodeLo && nodeHi <= hi {
+		return t.values[node]
+	}
+	mid := nodeLo + (nodeHi-nodeLo)/2
+	t.pushDown(node, nodeLo, mid, nodeHi)
+	result := t.identity
+	if lo < mid {
+		result = t.query(2*node, nodeLo, mid, lo, hi)
+	}
+	if hi > mid {
+		result = t.combine(result, t.query(2*node+1, mid, nodeHi, lo, hi))
+	}
+	return result
+}
+
+// Get returns the value at position i.
+func (t *LazyTree[T, U]) Get(i int) (T, error) {
+	if i < 0 || i >= t.n {
+		return t.identity, fmt.Errorf("%w: %d", ErrIndexOutOfRange, i)
+	}
+	return t.query(1, 0, t.n, i, i+1), nil
+}
+
+// Empty returns true if the tree has no positions.
+func (t *LazyTree[T, U]) Empty() bool {
+	return t.n == 0
+}
+
+// Size returns the number of positions.
+func (t *LazyTree[T, U]) Size() int {
+	return t.n
+}
+
+// Clear removes all positions.
+func (t *LazyTree[T, U]) Clear() {
+	t.n = 0
+	t.values, t.updates, t.pending = nil, nil, nil
+}
@@ -0,0 +1,111 @@
+// Package segmenttree implements segment trees over a monoid: range queries combining the values
+// of a contiguous range of positions, with point updates (Tree) or range updates (LazyTree).
+//
+// The monoid is given by an identity element and an associative combine function, such as
+// (0, +) for sums, (+∞, min) for minimums or matrix multiplication; it need not be commutative.
+// Ranges are half-open: [lo, hi) covers positions lo to hi-1.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Segment_tree
+package segmenttree
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kwstars/goads/trees"
+)
+
+var _ trees.Tree[int] = (*Tree[int])(nil)
+
+var (
+	ErrIndexOutOfRange = errors.New("index out of range")
+	ErrInvalidRange    = errors.New("invalid range")
+)
+
+// Tree is a segment tree with point updates and range queries, both O(log n).
+// It is stored bottom-up in an array of 2n values: leaves at n..2n-1, node i above 2i and 2i+1.
+type Tree[T any] struct {
+	n        int
+	data     []T
+	identity T
+	combine  func(a, b T) T
+}
+
+// New returns a segment tree over a copy of values.
+// combine must be associative and identity must satisfy combine(identity, x) == combine(x, identity) == x.
+func New[T any](values []T, identity T, combine func(a, b T) T) *Tree[T] {
+	n := len(values)
+	t := &Tree[T]{n: n, data: make([]T, 2*n), identity: identity, combine: combine}
+	copy(t.data[n:], values)
+	for i := n - 1; i > 0; i-- {
+		t.data[i] = combine(t.data[2*i], t.data[2*i+1])
+	}
+	return t
+}
+
+// Set replaces the value at position i.
+func (t *Tree[T]) Set(i int, value T) error {
+	if i < 0 || i >= t.n {
+		return fmt.Errorf("%w: %d", ErrIndexOutOfRange, i)
+	}
+	i += t.n
+	t.data[i] = value
+	for i >>= 1; i > 0; i >>= 1 {
+		t.data[i] = t.combine(t.data[2*i], t.data[2*i+1])
+	}
+	return nil
+}
+
+// Get returns the value at position i.
+func (t *Tree[T]) Get(i int) (T, error) {
+	if i < 0 || i >= t.n {
+		return t.identity, fmt.Errorf("%w: %d", ErrIndexOutOfRange, i)
+	}
+	return t.data[t.n+i], nil
+}
+
+// Query combines the values at positions lo to hi-1 in order. An empty range yields the identity.
+func (t *Tree[T]) Query(lo, hi int) (T, error) {
+	if err := checkRange(lo, hi, t.n); err != nil {
+		return t.identity, err
+	}
+	// Combine from both ends inwards, keeping the left and right results apart
+	// so that non-commutative monoids are combined in order.
+	left, right := t.identity, t.identity
+	for lo, hi = lo+t.n, hi+t.n; lo < hi; lo, hi = lo>>1, hi>>1 {
+		if lo&1 == 1 {
+			left = t.combine(left, t.data[lo])
+			lo++
+		}
+		if hi&1 == 1 {
+			hi--
+			right = t.combine(t.data[hi], right)
+		}
+	}
+	return t.combine(left, right), nil
+}
+
+// Empty returns true if the tree has no positions.
+func (t *Tree[T]) Empty() bool {
+	return t.n == 0
+}
+
+// Size returns the number of positions.
+func (t *Tree[T]) Size() int {
+	return t.n
+}
+
+// Clear removes all positions.
+func (t *Tree[T]) Clear() {
+	t.n = 0
+	t.data = nil
+}
+
+func checkRange(lo, hi, n int) error {
+	if lo < 0 || hi > n || lo > hi {
+		return fmt.Errorf("%w: [%d, %d) of %d", ErrInvalidRange, lo, hi, n)
+	}
+	return nil
+}
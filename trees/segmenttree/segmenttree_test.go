@@ -0,0 +1,143 @@
+package segmenttree
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sum(a, b int) int { return a + b }
+
+func TestTree(t *testing.T) {
+	tree := New([]int{5, 3, 8, 1, 4}, 0, sum)
+	assert.Equal(t, 5, tree.Size())
+
+	got, err := tree.Query(1, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, got)
+	got, _ = tree.Query(2, 2)
+	assert.Equal(t, 0, got)
+
+	assert.NoError(t, tree.Set(2, 10))
+	got, _ = tree.Query(0, 5)
+	assert.Equal(t, 23, got)
+	v, _ := tree.Get(2)
+	assert.Equal(t, 10, v)
+
+	assert.True(t, errors.Is(tree.Set(5, 1), ErrIndexOutOfRange))
+	_, err = tree.Get(-1)
+	assert.True(t, errors.Is(err, ErrIndexOutOfRange))
+	_, err = tree.Query(3, 2)
+	assert.True(t, errors.Is(err, ErrInvalidRange))
+	_, err = tree.Query(0, 6)
+	assert.True(t, errors.Is(err, ErrInvalidRange))
+
+	tree.Clear()
+	assert.True(t, tree.Empty())
+	got, err = New(nil, 0, sum).Query(0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, got)
+}
+
+func TestTree_NonCommutative(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	letters := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}
+	tree := New(letters, "", func(a, b string) string { return a + b })
+	for i := 0; i < 200; i++ {
+		pos := r.Intn(len(letters))
+		letters[pos] = string(rune('a' + r.Intn(26)))
+		assert.NoError(t, tree.Set(pos, letters[pos]))
+
+		lo := r.Intn(len(letters) + 1)
+		hi := lo + r.Intn(len(letters)-lo+1)
+		want := ""
+		for _, l := range letters[lo:hi] {
+			want += l
+		}
+		got, err := tree.Query(lo, hi)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+}
+
+// affine is the update x -> mul·x + add applied to every position.
+type affine struct{ mul, add int }
+
+func TestLazyTree_SumAffine(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for _, n := range []int{1, 2, 7, 64, 100} {
+		values := make([]int, n)
+		for i := range values {
+			values[i] = r.Intn(10)
+		}
+		tree := NewLazy(values, 0, sum,
+			func(v int, u affine, size int) int { return u.mul*v + u.add*size },
+			func(older, newer affine) affine {
+				return affine{mul: newer.mul * older.mul, add: newer.mul*older.add + newer.add}
+			})
+		reference := append([]int(nil), values...)
+
+		for i := 0; i < 300; i++ {
+			lo := r.Intn(n + 1)
+			hi := lo + r.Intn(n-lo+1)
+			if r.Intn(2) == 0 {
+				u := affine{mul: r.Intn(3), add: r.Intn(5) - 2}
+				assert.NoError(t, tree.Update(lo, hi, u))
+				for j := lo; j < hi; j++ {
+					reference[j] = u.mul*reference[j] + u.add
+				}
+				continue
+			}
+			want := 0
+			for _, v := range reference[lo:hi] {
+				want += v
+			}
+			got, err := tree.Query(lo, hi)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+		for i, want := range reference {
+			got, err := tree.Get(i)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+	}
+}
+
+func TestLazyTree_MinAssign(t *testing.T) {
+	min := func(a, b int) int {
+		if a < b {
+			return a
+		}
+		return b
+	}
+	tree := NewLazy([]int{4, 2, 7, 1, 9, 3}, math.MaxInt, min,
+		func(_ int, u int, _ int) int { return u },
+		func(_, newer int) int { return newer })
+
+	got, _ := tree.Query(0, 6)
+	assert.Equal(t, 1, got)
+	assert.NoError(t, tree.Update(2, 5, 6))
+	got, _ = tree.Query(0, 6)
+	assert.Equal(t, 2, got)
+	got, _ = tree.Query(2, 5)
+	assert.Equal(t, 6, got)
+	assert.NoError(t, tree.Update(0, 6, 8))
+	assert.NoError(t, tree.Update(5, 6, 0))
+	got, _ = tree.Query(0, 5)
+	assert.Equal(t, 8, got)
+	got, _ = tree.Query(3, 6)
+	assert.Equal(t, 0, got)
+
+	assert.True(t, errors.Is(tree.Update(4, 7, 1), ErrInvalidRange))
+	_, err := tree.Get(6)
+	assert.True(t, errors.Is(err, ErrIndexOutOfRange))
+	got, _ = tree.Query(3, 3)
+	assert.Equal(t, math.MaxInt, got)
+	assert.Equal(t, 6, tree.Size())
+	tree.Clear()
+	assert.True(t, tree.Empty())
+}
@@ -0,0 +1,130 @@
+// Package spatial defines the coordinate constraint and the geometry shared by the spatial indexes
+// kdtree, quadtree and rtree.
+//
+// Distances and areas are computed in float64 whatever the coordinate type, so they cannot overflow.
+package spatial
+
+import "github.com/kwstars/goads/pkg/common"
+
+// Coordinate is a constraint that permits any integer or floating-point type.
+type Coordinate interface {
+	common.Number
+}
+
+// Point is a point in the plane.
+type Point[C Coordinate] struct {
+	X, Y C
+}
+
+// DistanceSquared returns the squared Euclidean distance between two points.
+func (p Point[C]) DistanceSquared(q Point[C]) float64 {
+	dx, dy := float64(p.X)-float64(q.X), float64(p.Y)-float64(q.Y)
+	return dx*dx + dy*dy
+}
+
+// Rect is an axis-aligned rectangle including its border: the points with Min.X <= X <= Max.X
+// and Min.Y <= Y <= Max.Y. A rectangle with Min equal to Max is a single point.
+type Rect[C Coordinate] struct {
+	Min, Max Point[C]
+}
+
+// NewRect returns the rectangle spanned by two opposite corners given in any order.
+func NewRect[C Coordinate](a, b Point[C]) Rect[C] {
+	r := Rect[C]{Min: a, Max: b}
+	if r.Min.X > r.Max.X {
+		r.Min.X, r.Max.X = r.Max.X, r.Min.X
+	}
+	if r.Min.Y > r.Max.Y {
+		r.Min.Y, r.Max.Y = r.Max.Y, r.Min.Y
+	}
+	return r
+}
+
+// PointRect returns the rectangle holding only p.
+func PointRect[C Coordinate](p Point[C]) Rect[C] {
+	return Rect[C]{Min: p, Max: p}
+}
+
+// Valid returns true if Min is not after Max on either axis.
+func (r Rect[C]) Valid() bool {
+	return r.Min.X <= r.Max.X && r.Min.Y <= r.Max.Y
+}
+
+// ContainsPoint returns true if p lies in r or on its border.
+func (r Rect[C]) ContainsPoint(p Point[C]) bool {
+	return r.Min.X <= p.X && p.X <= r.Max.X && r.Min.Y <= p.Y && p.Y <= r.Max.Y
+}
+
+// Contains returns true if other lies entirely within r.
+func (r Rect[C]) Contains(other Rect[C]) bool {
+	return r.Min.X <= other.Min.X && other.Max.X <= r.Max.X && r.Min.Y <= other.Min.Y && other.Max.Y <= r.Max.Y
+}
+
+// Intersects returns true if the rectangles share at least one point.
+func (r Rect[C]) Intersects(other Rect[C]) bool {
+	return r.Min.X <= other.Max.X && other.Min.X <= r.Max.X && r.Min.Y <= other.Max.Y && other.Min.Y <= r.Max.Y
+}
+
+// Union returns the smallest rectangle containing both rectangles.
+func (r Rect[C]) Union(other Rect[C]) Rect[C] {
+	if other.Min.X < r.Min.X {
+		r.Min.X = other.Min.X
+	}
+	if other.Min.Y < r.Min.Y {
+		r.Min.Y = other.Min.Y
+	}
+	if other.Max.X > r.Max.X {
+		r.Max.X = other.Max.X
+	}
+	if other.Max.Y > r.Max.Y {
+		r.Max.Y = other.Max.Y
+	}
+	return r
+}
+
+// Intersection returns the rectangle shared by both rectangles, and false if they do not intersect.
+func (r Rect[C]) Intersection(other Rect[C]) (Rect[C], bool) {
+	if !r.Intersects(other) {
+		return Rect[C]{}, false
+	}
+	if other.Min.X > r.Min.X {
+		r.Min.X = other.Min.X
+	}
+	if other.Min.Y > r.Min.Y {
+		r.Min.Y = other.Min.Y
+	}
+	if other.Max.X < r.Max.X {
+		r.Max.X = other.Max.X
+	}
+	if other.Max.Y < r.Max.Y {
+		r.Max.Y = other.Max.Y
+	}
+	return r, true
+}
+
+// Area returns the area of r.
+func (r Rect[C]) Area() float64 {
+	return (float64(r.Max.X) - float64(r.Min.X)) * (float64(r.Max.Y) - float64(r.Min.Y))
+}
+
+// Margin returns half the perimeter of r.
+func (r Rect[C]) Margin() float64 {
+	return (float64(r.Max.X) - float64(r.Min.X)) + (float64(r.Max.Y) - float64(r.Min.Y))
+}
+
+// DistanceSquared returns the squared distance from p to the nearest point of r, 0 if p lies in r.
+func (r Rect[C]) DistanceSquared(p Point[C]) float64 {
+	dx := axisDistance(float64(p.X), float64(r.Min.X), float64(r.Max.X))
+	dy := axisDistance(float64(p.Y), float64(r.Min.Y), float64(r.Max.Y))
+	return dx*dx + dy*dy
+}
+
+func axisDistance(v, lo, hi float64) float64 {
+	switch {
+	case v < lo:
+		return lo - v
+	case v > hi:
+		return v - hi
+	}
+	return 0
+}
@@ -0,0 +1,39 @@
+package spatial
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRect(t *testing.T) {
+	r := NewRect(Point[int]{X: 4, Y: 1}, Point[int]{X: 0, Y: 3})
+	assert.Equal(t, Rect[int]{Min: Point[int]{X: 0, Y: 1}, Max: Point[int]{X: 4, Y: 3}}, r)
+	assert.True(t, r.Valid())
+	assert.False(t, Rect[int]{Min: Point[int]{X: 1}, Max: Point[int]{X: 0}}.Valid())
+
+	assert.True(t, r.ContainsPoint(Point[int]{X: 4, Y: 3}))
+	assert.False(t, r.ContainsPoint(Point[int]{X: 5, Y: 3}))
+	assert.True(t, r.Contains(PointRect(Point[int]{X: 2, Y: 2})))
+	assert.Equal(t, 8.0, r.Area())
+	assert.Equal(t, 6.0, r.Margin())
+
+	s := NewRect(Point[int]{X: 3, Y: 3}, Point[int]{X: 6, Y: 5})
+	assert.True(t, r.Intersects(s))
+	in, ok := r.Intersection(s)
+	assert.True(t, ok)
+	assert.Equal(t, NewRect(Point[int]{X: 3, Y: 3}, Point[int]{X: 4, Y: 3}), in)
+	assert.Equal(t, 0.0, in.Area())
+	assert.Equal(t, NewRect(Point[int]{X: 0, Y: 1}, Point[int]{X: 6, Y: 5}), r.Union(s))
+
+	_, ok = r.Intersection(NewRect(Point[int]{X: 5, Y: 0}, Point[int]{X: 6, Y: 0}))
+	assert.False(t, ok)
+
+	assert.Equal(t, 0.0, r.DistanceSquared(Point[int]{X: 2, Y: 2}))
+	assert.Equal(t, 5.0, r.DistanceSquared(Point[int]{X: 6, Y: 4}))
+	assert.Equal(t, 25.0, Point[int]{}.DistanceSquared(Point[int]{X: 3, Y: 4}))
+
+	// Unsigned coordinates do not wrap around.
+	u := NewRect(Point[uint8]{X: 10, Y: 10}, Point[uint8]{X: 20, Y: 20})
+	assert.Equal(t, 200.0, u.DistanceSquared(Point[uint8]{}))
+}
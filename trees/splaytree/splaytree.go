@@ -0,0 +1,265 @@
+// Package splaytree implements a sorted map as a splay tree: a self-adjusting binary search tree
+// that moves every accessed key to the root.
+//
+// Operations take O(log n) amortized time, and keys accessed often or recently stay near the top,
+// so workloads with locality run faster than on a balanced tree. Because lookups restructure the
+// tree, even Get and Contains modify it.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Splay_tree
+package splaytree
+
+import (
+	"github.com/kwstars/goads/maps"
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/trees"
+)
+
+var (
+	_ trees.Tree[int]          = (*Tree[int, int])(nil)
+	_ maps.SortedMap[int, int] = (*Tree[int, int])(nil)
+)
+
+type node[K any, V any] struct {
+	key         K
+	value       V
+	left, right *node[K, V]
+}
+
+// Tree is a sorted map implemented as a splay tree.
+type Tree[K any, V any] struct {
+	root *node[K, V]
+	size int
+	cmp  common.Comparator[K, K]
+}
+
+// New returns an empty splay tree ordered by cmp.
+func New[K any, V any](cmp common.Comparator[K, K]) *Tree[K, V] {
+	return &Tree[K, V]{cmp: cmp}
+}
+
+// splay performs a top-down splay of the subtree of n and returns its new root. dir tells, for a node,
+// whether the target lies to its left (negative), to its right (positive) or at the node (zero).
+// The new root is the target if it exists, else the last node on the search path.
+func splay[K any, V any](n *node[K, V], dir func(n *node[K, V]) int8) *node[K, V] {
+	if n == nil {
+		return nil
+	}
+	// header.right collects the left tree and header.left the right tree, see the reference.
+	var header node[K, V]
+	l, r := &header, &header
+	for {
+		if c := dir(n); c < 0 {
+			if n.left == nil {
+				break
+			}
+			if dir(n.left) < 0 {
+				// zig-zig: rotate right
+				y := n.left
+				n.left = y.right
+				y.right = n
+				n = y
+				if n.left == nil {
+					break
+				}
+			}
+			// link right
+			r.left = n
+			r = n
+			n = n.left
+		} else if c > 0 {
+			if n.right == nil {
+				break
+			}
+			if dir(n.right) > 0 {
+				// zig-zig: rotate left
+				y := n.right
+				n.right = y.left
+				y.left = n
+				n = y
+				if n.right == nil {
+					break
+				}
+			}
+			// link left
+			l.right = n
+			l = n
+			n = n.right
+		} else {
+			break
+		}
+	}
+	// assemble
+	l.right = n.left
+	r.left = n.right
+	n.left = header.right
+	n.right = header.left
+	return n
+}
+
+// splayKey splays the subtree of n for key.
+func (t *Tree[K, V]) splayKey(n *node[K, V], key K) *node[K, V] {
+	return splay(n, func(n *node[K, V]) int8 { return t.cmp(key, n.key) })
+}
+
+// access splays key to the root and returns true if it is present.
+func (t *Tree[K, V]) access(key K) bool {
+	t.root = t.splayKey(t.root, key)
+	return t.root != nil && t.cmp(key, t.root.key) == 0
+}
+
+// Put inserts a key-value pair into the map, replacing the value of an existing key.
+func (t *Tree[K, V]) Put(key K, value V) {
+	if t.access(key) {
+		t.root.value = value
+		return
+	}
+	n := &node[K, V]{key: key, value: value}
+	if t.root != nil {
+		// The root is the neighbor of key, so it and one of its subtrees go to either side of n.
+		if t.cmp(key, t.root.key) < 0 {
+			n.left, n.right = t.root.left, t.root
+			t.root.left = nil
+		} else {
+			n.left, n.right = t.root, t.root.right
+			t.root.right = nil
+		}
+	}
+	t.root = n
+	t.size++
+}
+
+// Get returns the value associated with the given key.
+func (t *Tree[K, V]) Get(key K) (value V, found bool) {
+	if t.access(key) {
+		return t.root.value, true
+	}
+	return value, false
+}
+
+// Remove removes the key-value pair associated with the given key.
+func (t *Tree[K, V]) Remove(key K) {
+	if !t.access(key) {
+		return
+	}
+	left, right := t.root.left, t.root.right
+	if left == nil {
+		t.root = right
+	} else {
+		// key is greater than every key on the left, so splaying for it lifts the maximum, which has no right child.
+		t.root = t.splayKey(left, key)
+		t.root.right = right
+	}
+	t.size--
+}
+
+// Contains returns true if the map holds the given key.
+func (t *Tree[K, V]) Contains(key K) bool {
+	return t.access(key)
+}
+
+// Min returns the smallest key and its value.
+func (t *Tree[K, V]) Min() (key K, value V, found bool) {
+	if t.root == nil {
+		return key, value, false
+	}
+	t.root = splay(t.root, func(*node[K, V]) int8 { return -1 })
+	return t.root.key, t.root.value, true
+}
+
+// Max returns the largest key and its value.
+func (t *Tree[K, V]) Max() (key K, value V, found bool) {
+	if t.root == nil {
+		return key, value, false
+	}
+	t.root = splay(t.root, func(*node[K, V]) int8 { return 1 })
+	return t.root.key, t.root.value, true
+}
+
+// Floor returns the largest key less than or equal to the given key, and its value.
+func (t *Tree[K, V]) Floor(key K) (floor K, value V, found bool) {
+	if t.root == nil {
+		return floor, value, false
+	}
+	t.root = t.splayKey(t.root, key)
+	if t.cmp(t.root.key, key) <= 0 {
+		return t.root.key, t.root.value, true
+	}
+	// The root is the smallest key above key, so the floor is the maximum of its left subtree.
+	if t.root.left == nil {
+		return floor, value, false
+	}
+	t.root.left = t.splayKey(t.root.left, key)
+	return t.root.left.key, t.root.left.value, true
+}
+
+// Ceiling returns the smallest key greater than or equal to the given key, and its value.
+func (t *Tree[K, V]) Ceiling(key K) (ceiling K, value V, found bool) {
+	if t.root == nil {
+		return ceiling, value, false
+	}
+	t.root = t.splayKey(t.root, key)
+	if t.cmp(t.root.key, key) >= 0 {
+		return t.root.key, t.root.value, true
+	}
+	// The root is the largest key below key, so the ceiling is the minimum of its right subtree.
+	if t.root.right == nil {
+		return ceiling, value, false
+	}
+	t.root.right = t.splayKey(t.root.right, key)
+	return t.root.right.key, t.root.right.value, true
+}
+
+// Range calls fn for each key-value pair in ascending key order without restructuring the tree.
+// If fn returns false, Range stops the iteration.
+func (t *Tree[K, V]) Range(fn func(key K, value V) bool) {
+	// A splay tree can be as deep as it is large, so walk it with an explicit stack.
+	var stack []*node[K, V]
+	for n := t.root; n != nil || len(stack) > 0; n = n.right {
+		for ; n != nil; n = n.left {
+			stack = append(stack, n)
+		}
+		n = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !fn(n.key, n.value) {
+			return
+		}
+	}
+}
+
+// Keys returns all keys in ascending order.
+func (t *Tree[K, V]) Keys() []K {
+	keys := make([]K, 0, t.size)
+	t.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Values returns all values in ascending key order.
+func (t *Tree[K, V]) Values() []V {
+	values := make([]V, 0, t.size)
+	t.Range(func(_ K, value V) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// Empty returns true if the map is empty, false otherwise.
+func (t *Tree[K, V]) Empty() bool {
+	return t.size == 0
+}
+
+// Size returns the number of elements in the map.
+func (t *Tree[K, V]) Size() int {
+	return t.size
+}
+
+// Clear removes all elements from the map.
+func (t *Tree[K, V]) Clear() {
+	t.root = nil
+	t.size = 0
+}
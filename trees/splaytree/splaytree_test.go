@@ -0,0 +1,136 @@
+package splaytree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree(t *testing.T) {
+	tree := New[int, string](common.IntComparator)
+	assert.True(t, tree.Empty())
+	_, _, ok := tree.Max()
+	assert.False(t, ok)
+	_, _, ok = tree.Floor(1)
+	assert.False(t, ok)
+
+	for _, k := range []int{50, 30, 70, 20, 40, 60, 80} {
+		tree.Put(k, string(rune('a'+k/10)))
+	}
+	tree.Put(40, "E")
+	assert.Equal(t, 7, tree.Size())
+	assert.Equal(t, []int{20, 30, 40, 50, 60, 70, 80}, tree.Keys())
+
+	// An accessed key moves to the root.
+	v, ok := tree.Get(40)
+	assert.True(t, ok)
+	assert.Equal(t, "E", v)
+	assert.Equal(t, 40, tree.root.key)
+	_, ok = tree.Get(45)
+	assert.False(t, ok)
+
+	k, _, ok := tree.Floor(45)
+	assert.True(t, ok)
+	assert.Equal(t, 40, k)
+	k, _, ok = tree.Ceiling(45)
+	assert.True(t, ok)
+	assert.Equal(t, 50, k)
+	k, _, _ = tree.Ceiling(50)
+	assert.Equal(t, 50, k)
+	_, _, ok = tree.Floor(19)
+	assert.False(t, ok)
+	_, _, ok = tree.Ceiling(81)
+	assert.False(t, ok)
+	k, _, _ = tree.Min()
+	assert.Equal(t, 20, k)
+	k, _, _ = tree.Max()
+	assert.Equal(t, 80, k)
+	assert.Equal(t, 80, tree.root.key)
+
+	tree.Remove(30)
+	tree.Remove(31)
+	assert.False(t, tree.Contains(30))
+	assert.Equal(t, 6, tree.Size())
+	assert.Equal(t, []string{"c", "E", "f", "g", "h", "i"}, tree.Values())
+
+	var seen []int
+	tree.Range(func(key int, _ string) bool {
+		seen = append(seen, key)
+		return key < 40
+	})
+	assert.Equal(t, []int{20, 40}, seen)
+
+	tree.Clear()
+	assert.True(t, tree.Empty())
+}
+
+func TestSequential(t *testing.T) {
+	// Ascending insertion builds a path; the first lookup of the smallest key halves its depth.
+	tree := New[int, int](common.IntComparator)
+	const n = 100000
+	for i := 0; i < n; i++ {
+		tree.Put(i, i)
+	}
+	assert.Equal(t, n, len(tree.Keys()))
+	for i := 0; i < n; i++ {
+		v, ok := tree.Get(i)
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tree := New[int, int](common.IntComparator)
+	want := make(map[int]int)
+
+	for i := 0; i < 5000; i++ {
+		k := rng.Intn(500)
+		switch rng.Intn(4) {
+		case 0:
+			tree.Remove(k)
+			delete(want, k)
+		case 1:
+			v, ok := tree.Get(k)
+			w, found := want[k]
+			assert.Equal(t, found, ok)
+			assert.Equal(t, w, v)
+		default:
+			tree.Put(k, i)
+			want[k] = i
+		}
+		assert.Equal(t, len(want), tree.Size())
+	}
+
+	keys := make([]int, 0, len(want))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	assert.Equal(t, keys, tree.Keys())
+
+	for q := 0; q < 200; q++ {
+		k := rng.Intn(520) - 10
+		i := sort.SearchInts(keys, k)
+		floor, _, ok := tree.Floor(k)
+		if i < len(keys) && keys[i] == k {
+			assert.True(t, ok)
+			assert.Equal(t, k, floor)
+		} else if i > 0 {
+			assert.True(t, ok)
+			assert.Equal(t, keys[i-1], floor)
+		} else {
+			assert.False(t, ok)
+		}
+		ceiling, _, ok := tree.Ceiling(k)
+		if i < len(keys) {
+			assert.True(t, ok)
+			assert.Equal(t, keys[i], ceiling)
+		} else {
+			assert.False(t, ok)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+// Package treap implements a sorted map as a treap: a binary search tree on the keys that is also
+// a heap on random priorities, which keeps its expected depth at O(log n) whatever the insertion order.
+//
+// Besides the map operations, a treap can be split at a key and two treaps whose keys do not
+// interleave can be merged, both in expected O(log n). RemoveRange uses them to drop a whole key
+// range at once.
+//
+// The priorities come from a math/rand source; pass WithSeed to New to get reproducible shapes.
+//
+// Structure is not thread safe.
+//
+// References: https://en.wikipedia.org/wiki/Treap
+package treap
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/kwstars/goads/maps"
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/kwstars/goads/trees"
+)
+
+var (
+	_ trees.Tree[int]          = (*Tree[int, int])(nil)
+	_ maps.SortedMap[int, int] = (*Tree[int, int])(nil)
+)
+
+var ErrOverlap = errors.New("key ranges overlap")
+
+type node[K any, V any] struct {
+	key         K
+	value       V
+	priority    uint64
+	left, right *node[K, V]
+	size        int // number of nodes in the subtree
+}
+
+func size[K any, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *node[K, V]) update() {
+	n.size = size(n.left) + size(n.right) + 1
+}
+
+// Option is a function that can be passed to New to customize the Tree.
+type Option[K any, V any] func(*Tree[K, V])
+
+// WithSeed makes the priorities, and thereby the shape of the tree, depend only on seed and the operations.
+func WithSeed[K any, V any](seed int64) Option[K, V] {
+	return func(t *Tree[K, V]) {
+		t.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// Tree is a sorted map implemented as a treap.
+type Tree[K any, V any] struct {
+	root *node[K, V]
+	cmp  common.Comparator[K, K]
+	rng  *rand.Rand
+}
+
+// New returns an empty treap ordered by cmp.
+func New[K any, V any](cmp common.Comparator[K, K], opts ...Option[K, V]) *Tree[K, V] {
+	t := &Tree[K, V]{cmp: cmp}
+	for _, option := range opts {
+		option(t)
+	}
+	if t.rng == nil {
+		t.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return t
+}
+
+// split divides the subtree of n into the keys less than key and the others.
+func (t *Tree[K, V]) split(n *node[K, V], key K) (less, rest *node[K, V]) {
+	if n == nil {
+		return nil, nil
+	}
+	if t.cmp(n.key, key) < 0 {
+		n.right, rest = t.split(n.right, key)
+		n.update()
+		return n, rest
+	}
+	less, n.left = t.split(n.left, key)
+	n.update()
+	return less, n
+}
+
+// merge joins two subtrees where every key of a is less than every key of b.
+func merge[K any, V any](a, b *node[K, V]) *node[K, V] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.priority > b.priority {
+		a.right = merge(a.right, b)
+		a.update()
+		return a
+	}
+	b.left = merge(a, b.left)
+	b.update()
+	return b
+}
+
+func (t *Tree[K, V]) find(key K) *node[K, V] {
+	for n := t.root; n != nil; {
+		switch c := t.cmp(key, n.key); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// Put inserts a key-value pair into the map, replacing the value of an existing key.
+func (t *Tree[K, V]) Put(key K, value V) {
+	if n := t.find(key); n != nil {
+		n.value = value
+		return
+	}
+	less, rest := t.split(t.root, key)
+	n := &node[K, V]{key: key, value: value, priority: t.rng.Uint64(), size: 1}
+	t.root = merge(merge(less, n), rest)
+}
+
+// Get returns the value associated with the given key.
+func (t *Tree[K, V]) Get(key K) (value V, found bool) {
+	if n := t.find(key); n != nil {
+		return n.value, true
+	}
+	return value, false
+}
+
+// Remove removes the key-value pair associated with the given key.
+func (t *Tree[K, V]) Remove(key K) {
+	t.root = t.remove(t.root, key)
+}
+
+func (t *Tree[K, V]) remove(n *node[K, V], key K) *node[K, V] {
+	if n == nil {
+		return nil
+	}
+	switch c := t.cmp(key, n.key); {
+	case c < 0:
+		n.left = t.remove(n.left, key)
+	case c > 0:
+		n.right = t.remove(n.right, key)
+	default:
+		return merge(n.left, n.right)
+	}
+	n.update()
+	return n
+}
+
+// RemoveRange removes all keys k with lo <= k < hi and returns how many were removed.
+func (t *Tree[K, V]) RemoveRange(lo, hi K) int {
+	if t.cmp(lo, hi) >= 0 {
+		return 0
+	}
+	less, rest := t.split(t.root, lo)
+	middle, greater := t.split(rest, hi)
+	t.root = merge(less, greater)
+	return size(middle)
+}
+
+// Split moves the keys less than key into the first returned tree and the others into the second.
+// Both share the comparator and random source of t, which is left empty.
+func (t *Tree[K, V]) Split(key K) (*Tree[K, V], *Tree[K, V]) {
+	less, rest := t.split(t.root, key)
+	t.root = nil
+	return &Tree[K, V]{root: less, cmp: t.cmp, rng: t.rng}, &Tree[K, V]{root: rest, cmp: t.cmp, rng: t.rng}
+}
+
+// Merge moves all pairs of a and b into a new tree that uses the comparator and random source of a,
+// leaving a and b empty. Every key of a must be less than every key of b; otherwise Merge returns
+// ErrOverlap and leaves both trees unchanged.
+func Merge[K any, V any](a, b *Tree[K, V]) (*Tree[K, V], error) {
+	if a.root != nil && b.root != nil {
+		last, first := rightmost(a.root), leftmost(b.root)
+		if a.cmp(last.key, first.key) >= 0 {
+			return nil, fmt.Errorf("%w: %v is not less than %v", ErrOverlap, last.key, first.key)
+		}
+	}
+	merged := &Tree[K, V]{root: merge(a.root, b.root), cmp: a.cmp, rng: a.rng}
+	a.root, b.root = nil, nil
+	return merged, nil
+}
+
+// Contains returns true if the map holds the given key.
+func (t *Tree[K, V]) Contains(key K) bool {
+	return t.find(key) != nil
+}
+
+// Min returns the smallest key and its value.
+func (t *Tree[K, V]) Min() (key K, value V, found bool) {
+	if t.root == nil {
+		return key, value, false
+	}
+	n := leftmost(t.root)
+	return n.key, n.value, true
+}
+
+// Max returns the largest key and its value.
+func (t *Tree[K, V]) Max() (key K, value V, found bool) {
+	if t.root == nil {
+		return key, value, false
+	}
+	n := rightmost(t.root)
+	return n.key, n.value, true
+}
+
+// Floor returns the largest key less than or equal to the given key, and its value.
+func (t *Tree[K, V]) Floor(key K) (floor K, value V, found bool) {
+	var best *node[K, V]
+	for n := t.root; n != nil; {
+		if c := t.cmp(key, n.key); c < 0 {
+			n = n.left
+		} else {
+			best = n
+			if c == 0 {
+				break
+			}
+			n = n.right
+		}
+	}
+	if best == nil {
+		return floor, value, false
+	}
+	return best.key, best.value, true
+}
+
+// Ceiling returns the smallest key greater than or equal to the given key, and its value.
+func (t *Tree[K, V]) Ceiling(key K) (ceiling K, value V, found bool) {
+	var best *node[K, V]
+	for n := t.root; n != nil; {
+		if c := t.cmp(key, n.key); c > 0 {
+			n = n.right
+		} else {
+			best = n
+			if c == 0 {
+				break
+			}
+			n = n.left
+		}
+	}
+	if best == nil {
+		return ceiling, value, false
+	}
+	return best.key, best.value, true
+}
+
+// Range calls fn for each key-value pair in ascending key order.
+// If fn returns false, Range stops the iteration.
+func (t *Tree[K, V]) Range(fn func(key K, value V) bool) {
+	var visit func(n *node[K, V]) bool
+	visit = func(n *node[K, V]) bool {
+		return n == nil || visit(n.left) && fn(n.key, n.value) && visit(n.right)
+	}
+	visit(t.root)
+}
+
+// Keys returns all keys in ascending order.
+func (t *Tree[K, V]) Keys() []K {
+	keys := make([]K, 0, size(t.root))
+	t.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Values returns all values in ascending key order.
+func (t *Tree[K, V]) Values() []V {
+	values := make([]V, 0, size(t.root))
+	t.Range(func(_ K, value V) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// Empty returns true if the map is empty, false otherwise.
+func (t *Tree[K, V]) Empty() bool {
+	return t.root == nil
+}
+
+// Size returns the number of elements in the map.
+func (t *Tree[K, V]) Size() int {
+	return size(t.root)
+}
+
+// Clear removes all elements from the map.
+func (t *Tree[K, V]) Clear() {
+	t.root = nil
+}
+
+func leftmost[K any, V any](n *node[K, V]) *node[K, V] {
+	for n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+func rightmost[K any, V any](n *node[K, V]) *node[K, V] {
+	for n.right != nil {
+		n = n.right
+	}
+	return n
+}
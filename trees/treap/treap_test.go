@@ -0,0 +1,172 @@
+package treap
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/kwstars/goads/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree(t *testing.T) {
+	tree := New[int, string](common.IntComparator, WithSeed[int, string](1))
+	assert.True(t, tree.Empty())
+	_, _, ok := tree.Min()
+	assert.False(t, ok)
+
+	for _, k := range []int{50, 30, 70, 20, 40, 60, 80} {
+		tree.Put(k, string(rune('a'+k/10)))
+	}
+	tree.Put(40, "E")
+	assert.Equal(t, 7, tree.Size())
+	assert.Equal(t, []int{20, 30, 40, 50, 60, 70, 80}, tree.Keys())
+	v, ok := tree.Get(40)
+	assert.True(t, ok)
+	assert.Equal(t, "E", v)
+
+	k, _, ok := tree.Floor(45)
+	assert.True(t, ok)
+	assert.Equal(t, 40, k)
+	k, _, ok = tree.Ceiling(45)
+	assert.True(t, ok)
+	assert.Equal(t, 50, k)
+	k, _, _ = tree.Floor(50)
+	assert.Equal(t, 50, k)
+	_, _, ok = tree.Floor(19)
+	assert.False(t, ok)
+	_, _, ok = tree.Ceiling(81)
+	assert.False(t, ok)
+	k, _, _ = tree.Min()
+	assert.Equal(t, 20, k)
+	k, _, _ = tree.Max()
+	assert.Equal(t, 80, k)
+
+	tree.Remove(30)
+	tree.Remove(31)
+	assert.False(t, tree.Contains(30))
+	assert.Equal(t, 6, tree.Size())
+
+	assert.Equal(t, 2, tree.RemoveRange(40, 60))
+	assert.Equal(t, []int{20, 60, 70, 80}, tree.Keys())
+	assert.Equal(t, 0, tree.RemoveRange(60, 60))
+
+	var seen []int
+	tree.Range(func(key int, _ string) bool {
+		seen = append(seen, key)
+		return key < 60
+	})
+	assert.Equal(t, []int{20, 60}, seen)
+
+	tree.Clear()
+	assert.True(t, tree.Empty())
+}
+
+func TestSplitMerge(t *testing.T) {
+	tree := New[int, int](common.IntComparator, WithSeed[int, int](1))
+	for i := 0; i < 100; i++ {
+		tree.Put(i, i*i)
+	}
+
+	less, rest := tree.Split(40)
+	assert.True(t, tree.Empty())
+	assert.Equal(t, 40, less.Size())
+	assert.Equal(t, 60, rest.Size())
+	k, _, _ := less.Max()
+	assert.Equal(t, 39, k)
+	k, _, _ = rest.Min()
+	assert.Equal(t, 40, k)
+	assertHeap(t, less.root)
+	assertHeap(t, rest.root)
+
+	_, err := Merge(rest, less)
+	assert.True(t, errors.Is(err, ErrOverlap))
+	assert.Equal(t, 40, less.Size())
+
+	merged, err := Merge(less, rest)
+	assert.NoError(t, err)
+	assert.True(t, less.Empty())
+	assert.True(t, rest.Empty())
+	assert.Equal(t, 100, merged.Size())
+	v, _ := merged.Get(50)
+	assert.Equal(t, 2500, v)
+	assertHeap(t, merged.root)
+
+	// Splitting outside the key range leaves one side empty.
+	less, rest = merged.Split(-1)
+	assert.True(t, less.Empty())
+	assert.Equal(t, 100, rest.Size())
+	merged, err = Merge(New[int, int](common.IntComparator), rest)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, merged.Size())
+}
+
+func TestSeed(t *testing.T) {
+	build := func(seed int64) *Tree[int, int] {
+		tree := New[int, int](common.IntComparator, WithSeed[int, int](seed))
+		for i := 0; i < 50; i++ {
+			tree.Put(i, i)
+		}
+		return tree
+	}
+	assert.Equal(t, build(7).root, build(7).root)
+	assert.NotEqual(t, build(7).root, build(8).root)
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tree := New[int, int](common.IntComparator, WithSeed[int, int](1))
+	want := make(map[int]int)
+
+	for i := 0; i < 3000; i++ {
+		k := rng.Intn(500)
+		switch rng.Intn(10) {
+		case 0:
+			lo := rng.Intn(500)
+			hi := lo + rng.Intn(20)
+			removed := 0
+			for key := range want {
+				if lo <= key && key < hi {
+					delete(want, key)
+					removed++
+				}
+			}
+			assert.Equal(t, removed, tree.RemoveRange(lo, hi))
+		case 1, 2, 3:
+			tree.Remove(k)
+			delete(want, k)
+		default:
+			tree.Put(k, i)
+			want[k] = i
+		}
+	}
+
+	keys := make([]int, 0, len(want))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	assert.Equal(t, keys, tree.Keys())
+	for k, v := range want {
+		got, ok := tree.Get(k)
+		assert.True(t, ok)
+		assert.Equal(t, v, got)
+	}
+	assertHeap(t, tree.root)
+}
+
+// assertHeap checks that priorities never increase downwards and that subtree sizes are correct.
+func assertHeap[K any, V any](t *testing.T, n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	for _, child := range []*node[K, V]{n.left, n.right} {
+		if child != nil {
+			assert.LessOrEqual(t, child.priority, n.priority)
+		}
+	}
+	s := assertHeap(t, n.left) + assertHeap(t, n.right) + 1
+	assert.Equal(t, s, n.size)
+	return s
+}